@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// Activity fingerprints a pull request's conversation without bodies,
+// diff hunks or reactor lists: every thread's resolution and every
+// comment's edit time, hidden state and reaction totals. Two equal
+// fingerprints mean no comments, replies, edits, resolutions or
+// reactions happened in between, so pollers can skip fetching the pull
+// request in full.
+type Activity struct {
+	Comments []CommentActivity
+	Threads  []ThreadActivity
+}
+
+// ThreadActivity is the fingerprint of a review thread
+type ThreadActivity struct {
+	ID         string
+	IsResolved bool
+	Comments   []CommentActivity
+}
+
+// CommentActivity is the fingerprint of a comment. Reactions counts
+// reactions by content, leaving out contents nobody used.
+type CommentActivity struct {
+	ID           string
+	LastEditedAt time.Time
+	IsMinimized  bool
+	Reactions    map[string]int
+}
+
+// Equal reports whether two fingerprints are the same
+func (a *Activity) Equal(b *Activity) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// activityCommentNode is the part of a comment its fingerprint needs
+type activityCommentNode struct {
+	ID             graphql.String
+	LastEditedAt   *string
+	IsMinimized    graphql.Boolean
+	ReactionGroups []struct {
+		Content graphql.String
+		Users   struct {
+			TotalCount graphql.Int
+		}
+	}
+}
+
+// activityComments is one page of comment fingerprints
+type activityComments struct {
+	Nodes    []activityCommentNode
+	PageInfo pageInfo
+}
+
+// activityThreads is one page of thread fingerprints
+type activityThreads struct {
+	Nodes []struct {
+		ID         graphql.String
+		IsResolved graphql.Boolean
+		Comments   activityComments `graphql:"comments(first: 100)"`
+	}
+	PageInfo pageInfo
+}
+
+// GetActivity fetches the activity fingerprint of a pull request, paging
+// through every comment and thread
+func (c *Client) GetActivity(ctx context.Context, owner, name string, pr int) (*Activity, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				Comments      activityComments `graphql:"comments(first: 100)"`
+				ReviewThreads activityThreads  `graphql:"reviewThreads(first: 50)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":  graphQLString(owner),
+		"name":   graphQLString(name),
+		"number": graphQLInt(pr),
+	}
+
+	if err := c.queryWithContext(ctx, "GetActivity", &query, variables); err != nil {
+		return nil, fmt.Errorf("get activity of %s/%s#%d: %w", owner, name, pr, err)
+	}
+
+	activity := &Activity{}
+	comments := query.Repository.PullRequest.Comments
+	threads := query.Repository.PullRequest.ReviewThreads
+
+	for {
+		activity.Comments = append(activity.Comments, commentActivities(comments.Nodes)...)
+		after := comments.PageInfo.next()
+		if after == nil {
+			break
+		}
+
+		var page struct {
+			Repository struct {
+				PullRequest struct {
+					Comments activityComments `graphql:"comments(first: 100, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["after"] = after
+		if err := c.queryWithContext(ctx, "GetMoreCommentActivity", &page, variables); err != nil {
+			return nil, fmt.Errorf("get activity of %s/%s#%d: %w", owner, name, pr, err)
+		}
+		comments = page.Repository.PullRequest.Comments
+	}
+
+	for {
+		for _, n := range threads.Nodes {
+			thread := ThreadActivity{
+				ID:         string(n.ID),
+				IsResolved: bool(n.IsResolved),
+				Comments:   commentActivities(n.Comments.Nodes),
+			}
+			for after := n.Comments.PageInfo.next(); after != nil; {
+				var page struct {
+					Node struct {
+						Thread struct {
+							Comments activityComments `graphql:"comments(first: 100, after: $after)"`
+						} `graphql:"... on PullRequestReviewThread"`
+					} `graphql:"node(id: $id)"`
+				}
+				pageVariables := map[string]interface{}{
+					"id":    graphQLID(thread.ID),
+					"after": after,
+				}
+				if err := c.queryWithContext(ctx, "GetMoreReplyActivity", &page, pageVariables); err != nil {
+					return nil, fmt.Errorf("get activity of thread %s: %w", thread.ID, err)
+				}
+				thread.Comments = append(thread.Comments, commentActivities(page.Node.Thread.Comments.Nodes)...)
+				after = page.Node.Thread.Comments.PageInfo.next()
+			}
+			activity.Threads = append(activity.Threads, thread)
+		}
+
+		after := threads.PageInfo.next()
+		if after == nil {
+			break
+		}
+
+		var page struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads activityThreads `graphql:"reviewThreads(first: 50, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["after"] = after
+		if err := c.queryWithContext(ctx, "GetMoreThreadActivity", &page, variables); err != nil {
+			return nil, fmt.Errorf("get activity of %s/%s#%d: %w", owner, name, pr, err)
+		}
+		threads = page.Repository.PullRequest.ReviewThreads
+	}
+
+	return activity, nil
+}
+
+// commentActivities converts comment nodes to fingerprints
+func commentActivities(nodes []activityCommentNode) []CommentActivity {
+	activities := make([]CommentActivity, 0, len(nodes))
+	for _, n := range nodes {
+		activity := CommentActivity{
+			ID:          string(n.ID),
+			IsMinimized: bool(n.IsMinimized),
+		}
+		if n.LastEditedAt != nil {
+			activity.LastEditedAt = parseTime(*n.LastEditedAt)
+		}
+		for _, rg := range n.ReactionGroups {
+			if rg.Users.TotalCount == 0 {
+				continue
+			}
+			if activity.Reactions == nil {
+				activity.Reactions = make(map[string]int)
+			}
+			activity.Reactions[string(rg.Content)] = int(rg.Users.TotalCount)
+		}
+		activities = append(activities, activity)
+	}
+	return activities
+}
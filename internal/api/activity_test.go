@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetActivity(t *testing.T) {
+	client := newTestClient(t,
+		`{"data":{"repository":{"pullRequest":{
+			"comments":{"nodes":[
+				{"id":"IC_1","lastEditedAt":"2025-01-01T01:00:00Z","isMinimized":false,"reactionGroups":[
+					{"content":"THUMBS_UP","users":{"totalCount":2}},{"content":"EYES","users":{"totalCount":0}}]}
+			],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}},
+			"reviewThreads":{"nodes":[
+				{"id":"PRRT_1","isResolved":true,"comments":{"nodes":[{"id":"PRRC_1","reactionGroups":[]}],
+					"pageInfo":{"hasNextPage":true,"endCursor":"r1"}}}
+			],"pageInfo":{"hasNextPage":true,"endCursor":"t1"}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"comments":{"nodes":[{"id":"IC_2","isMinimized":true,"reactionGroups":[]}],
+			"pageInfo":{"hasNextPage":false}}}}}}`,
+		`{"data":{"node":{"comments":{"nodes":[{"id":"PRRC_2","reactionGroups":[{"content":"EYES","users":{"totalCount":1}}]}],
+			"pageInfo":{"hasNextPage":false}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[
+			{"id":"PRRT_2","isResolved":false,"comments":{"nodes":[{"id":"PRRC_3","reactionGroups":[]}],"pageInfo":{"hasNextPage":false}}}
+		],"pageInfo":{"hasNextPage":false}}}}}}`,
+	)
+
+	got, err := client.GetActivity(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+	want := &Activity{
+		Comments: []CommentActivity{
+			{ID: "IC_1", LastEditedAt: time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC), Reactions: map[string]int{"THUMBS_UP": 2}},
+			{ID: "IC_2", IsMinimized: true},
+		},
+		Threads: []ThreadActivity{
+			{ID: "PRRT_1", IsResolved: true, Comments: []CommentActivity{
+				{ID: "PRRC_1"},
+				{ID: "PRRC_2", Reactions: map[string]int{"EYES": 1}},
+			}},
+			{ID: "PRRT_2", Comments: []CommentActivity{{ID: "PRRC_3"}}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetActivity() = %+v, want %+v", got, want)
+	}
+}
+
+func TestActivityEqual(t *testing.T) {
+	base := func() *Activity {
+		return &Activity{Threads: []ThreadActivity{
+			{ID: "PRRT_1", IsResolved: true, Comments: []CommentActivity{{ID: "PRRC_1"}}},
+			{ID: "PRRT_2", Comments: []CommentActivity{{ID: "PRRC_2", Reactions: map[string]int{"EYES": 1}}}},
+		}}
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *Activity)
+		want   bool
+	}{
+		{"same", func(a *Activity) {}, true},
+		{"resolution swapped", func(a *Activity) {
+			a.Threads[0].IsResolved, a.Threads[1].IsResolved = false, true
+		}, false},
+		{"reaction added", func(a *Activity) { a.Threads[1].Comments[0].Reactions["EYES"] = 2 }, false},
+		{"comment edited", func(a *Activity) { a.Threads[0].Comments[0].LastEditedAt = time.Unix(1, 0) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base()
+			tt.change(changed)
+			if got := base().Equal(changed); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
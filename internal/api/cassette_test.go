@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Cassettes are GraphQL request/response pairs in testdata/cassettes.
+//
+// Tests replay them by default, failing when a request differs from the
+// recorded one, so query text and variable shapes are checked offline.
+// With GH_TALK_RECORD=1 the requests go to GitHub using gh's
+// authentication and the cassettes are rewritten. Only request and
+// response bodies are stored; headers, and with them the auth token, are
+// never written.
+
+const cassetteDir = "../../testdata/cassettes"
+
+// recordTransport is where requests go when recording
+var recordTransport http.RoundTripper = http.DefaultTransport
+
+// cassette is a recorded sequence of GraphQL requests and responses
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+// interaction is one GraphQL request and its response
+type interaction struct {
+	Operation string          `json:"operation"`
+	Query     string          `json:"query"`
+	Variables json.RawMessage `json:"variables,omitempty"`
+	Response  json.RawMessage `json:"response"`
+}
+
+// cassetteTransport records interactions into, or replays them from, a
+// cassette file
+type cassetteTransport struct {
+	t      *testing.T
+	path   string
+	record bool
+	next   http.RoundTripper
+
+	mu       sync.Mutex
+	cassette cassette
+	played   int
+}
+
+// newCassetteClient returns a client that replays testdata/cassettes/
+// <name>.json, or records it when GH_TALK_RECORD=1
+func newCassetteClient(t *testing.T, name string) *Client {
+	t.Helper()
+
+	transport := &cassetteTransport{
+		t:      t,
+		path:   filepath.Join(cassetteDir, name+".json"),
+		record: os.Getenv("GH_TALK_RECORD") == "1",
+		next:   recordTransport,
+	}
+
+	opts := api.ClientOptions{Transport: transport}
+	if transport.record {
+		t.Cleanup(func() {
+			if err := transport.save(); err != nil {
+				t.Errorf("save cassette: %v", err)
+			}
+		})
+	} else {
+		data, err := os.ReadFile(transport.path)
+		if err != nil {
+			t.Fatalf("read cassette (record it with GH_TALK_RECORD=1): %v", err)
+		}
+		if err := json.Unmarshal(data, &transport.cassette); err != nil {
+			t.Fatalf("parse cassette %s: %v", transport.path, err)
+		}
+		t.Cleanup(func() {
+			if transport.played < len(transport.cassette.Interactions) {
+				t.Errorf("cassette %s: %d of %d interactions were not replayed", transport.path,
+					len(transport.cassette.Interactions)-transport.played, len(transport.cassette.Interactions))
+			}
+		})
+		opts.Host = "github.com"
+		opts.AuthToken = "test-token"
+		opts.LogIgnoreEnv = true
+	}
+
+	client, err := NewClientWithOptions(opts)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+	return client
+}
+
+func (c *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("parse GraphQL request: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.record {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		resp, err := c.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		c.cassette.Interactions = append(c.cassette.Interactions, interaction{
+			Operation: operationName(payload.Query),
+			Query:     payload.Query,
+			Variables: payload.Variables,
+			Response:  data,
+		})
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		return resp, nil
+	}
+
+	if c.played >= len(c.cassette.Interactions) {
+		c.t.Errorf("cassette %s: unexpected request %s", c.path, payload.Query)
+		return nil, fmt.Errorf("no more interactions in %s", c.path)
+	}
+	want := c.cassette.Interactions[c.played]
+	c.played++
+
+	if payload.Query != want.Query {
+		c.t.Errorf("cassette %s: request %d query differs\ngot:  %s\nwant: %s", c.path, c.played, payload.Query, want.Query)
+	}
+	if !sameJSON(payload.Variables, want.Variables) {
+		c.t.Errorf("cassette %s: request %d (%s) variables differ\ngot:  %s\nwant: %s", c.path, c.played, want.Operation, payload.Variables, want.Variables)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(want.Response)),
+		Request:    req,
+	}, nil
+}
+
+// save writes the recorded interactions to the cassette file
+func (c *cassetteTransport) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.cassette.Interactions {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, c.cassette.Interactions[i].Response, "", "  "); err == nil {
+			c.cassette.Interactions[i].Response = indented.Bytes()
+		}
+	}
+
+	data, err := json.MarshalIndent(c.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// sameJSON reports whether two JSON documents are equal, ignoring
+// formatting and key order. Missing documents equal null.
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if len(bytes.TrimSpace(a)) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(bytes.TrimSpace(b)) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestSameJSON(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a":1,"b":[2]}`, `{ "b": [2], "a": 1 }`, true},
+		{`{"a":1}`, `{"a":2}`, false},
+		{``, `null`, true},
+		{``, `{}`, false},
+	}
+
+	for _, tt := range tests {
+		if got := sameJSON(json.RawMessage(tt.a), json.RawMessage(tt.b)); got != tt.want {
+			t.Errorf("sameJSON(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCassetteNeverStoresAuth(t *testing.T) {
+	dir := t.TempDir()
+	transport := &cassetteTransport{
+		t:      t,
+		path:   filepath.Join(dir, "auth.json"),
+		record: true,
+		next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"data":{"viewer":{"login":"me"}}}`)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "secret-token",
+		Transport:    transport,
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CurrentUser(context.Background()); err != nil {
+		t.Fatalf("CurrentUser() error = %v", err)
+	}
+	if err := transport.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(transport.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Errorf("cassette contains the auth token:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"operation": "CurrentUser"`) {
+		t.Errorf("cassette is missing the interaction:\n%s", data)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -6,21 +6,56 @@ import (
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	graphql "github.com/cli/shurcooL-graphql"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
 )
 
+// GraphQLDoer executes GraphQL queries and mutations built from structs.
+// *api.GraphQLClient from go-gh implements it.
+type GraphQLDoer interface {
+	QueryWithContext(ctx context.Context, name string, query interface{}, variables map[string]interface{}) error
+	MutateWithContext(ctx context.Context, name string, mutation interface{}, variables map[string]interface{}) error
+}
+
 // Client provides methods for interacting with GitHub API
 type Client struct {
-	graphql *api.GraphQLClient
+	graphql GraphQLDoer
+
+	// dryRun records mutations instead of sending them, if set
+	dryRun *dryRunTransport
+
+	// journal records mutations so they can be undone, if set; seen holds
+	// the states the entries' prior states are taken from
+	journal *journal.Journal
+	seen    seenStates
+
+	// host is the GitHub host, "" for the default; features limits what
+	// it accepts
+	host     string
+	features HostFeatures
 }
 
-// NewClient creates a new API client using gh authentication
+// NewClient creates a new API client using gh authentication for the
+// default host. Mutations are recorded in the default journal.
 func NewClient() (*Client, error) {
-	gql, err := api.DefaultGraphQLClient()
+	return NewClientForHost("")
+}
+
+// NewClientForHost creates a new API client using gh authentication for
+// host, e.g. a GitHub Enterprise Server hostname. An empty host means the
+// default host (GH_HOST, or github.com). Mutations are recorded in the
+// default journal.
+func NewClientForHost(host string) (*Client, error) {
+	gql, err := api.NewGraphQLClient(api.ClientOptions{Host: host})
 	if err != nil {
-		return nil, fmt.Errorf("create GraphQL client: %w", err)
+		return nil, fmt.Errorf("create GraphQL client for %s: %w", hostName(host), err)
+	}
+
+	j, err := journal.Default()
+	if err != nil {
+		return nil, err
 	}
 
-	return &Client{graphql: gql}, nil
+	return &Client{graphql: gql, journal: j, host: host}, nil
 }
 
 // NewClientWithOptions creates a client with custom options (for testing)
@@ -30,7 +65,15 @@ func NewClientWithOptions(opts api.ClientOptions) (*Client, error) {
 		return nil, fmt.Errorf("create GraphQL client: %w", err)
 	}
 
-	return &Client{graphql: gql}, nil
+	client := NewClientFromGraphQL(gql)
+	client.host = opts.Host
+	return client, nil
+}
+
+// NewClientFromGraphQL creates a client that sends requests through gql,
+// without a journal
+func NewClientFromGraphQL(gql GraphQLDoer) *Client {
+	return &Client{graphql: gql}
 }
 
 // graphQLString is a helper to create graphql.String values
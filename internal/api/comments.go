@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// commentPageSize is how many comments or threads each page requests
+const commentPageSize = 100
+
+// pageInfo is the cursor of a paged connection
+type pageInfo struct {
+	HasNextPage graphql.Boolean `json:"hasNextPage"`
+	EndCursor   graphql.String  `json:"endCursor"`
+}
+
+// next returns the cursor of the following page, or nil on the last page
+func (p pageInfo) next() *graphql.String {
+	if !p.HasNextPage {
+		return nil
+	}
+	cursor := p.EndCursor
+	return &cursor
+}
+
+// pagedCommentNode is the part of a comment needed to select and hide it
+type pagedCommentNode struct {
+	ID                graphql.String
+	URL               graphql.String
+	Body              graphql.String
+	CreatedAt         string
+	IsMinimized       graphql.Boolean
+	MinimizedReason   graphql.String
+	ViewerCanMinimize graphql.Boolean
+	Author            struct {
+		Login graphql.String
+	}
+}
+
+// comment converts the node to our Comment type
+func (n pagedCommentNode) comment() Comment {
+	return Comment{
+		ID:                string(n.ID),
+		URL:               string(n.URL),
+		Body:              string(n.Body),
+		CreatedAt:         parseTime(n.CreatedAt),
+		IsMinimized:       bool(n.IsMinimized),
+		MinimizedReason:   string(n.MinimizedReason),
+		ViewerCanMinimize: bool(n.ViewerCanMinimize),
+		Author:            User{Login: string(n.Author.Login)},
+	}
+}
+
+// pagedComments is one page of a comment connection
+type pagedComments struct {
+	Nodes    []pagedCommentNode
+	PageInfo pageInfo
+}
+
+// ListPullRequestComments fetches every conversation and review comment of
+// a pull request, paging through comments and threads that GetPullRequest
+// truncates. Only the fields needed to select and hide comments are set,
+// so use it for bulk operations over all comments.
+func (c *Client) ListPullRequestComments(ctx context.Context, owner, name string, pr int) (*PullRequest, error) {
+	result := &PullRequest{Repository: owner + "/" + name, Number: pr}
+
+	var after *graphql.String
+	for {
+		var query struct {
+			Repository struct {
+				PullRequest struct {
+					ID       graphql.String
+					URL      graphql.String
+					Comments pagedComments `graphql:"comments(first: $first, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+
+		variables := map[string]interface{}{
+			"owner":  graphQLString(owner),
+			"name":   graphQLString(name),
+			"number": graphQLInt(pr),
+			"first":  graphQLInt(commentPageSize),
+			"after":  after,
+		}
+
+		if err := c.queryWithContext(ctx, "ListPullRequestComments", &query, variables); err != nil {
+			return nil, fmt.Errorf("list comments of %s/%s#%d: %w", owner, name, pr, err)
+		}
+
+		node := query.Repository.PullRequest
+		if node.ID == "" {
+			return nil, fmt.Errorf("pull request not found: %s/%s#%d", owner, name, pr)
+		}
+		result.ID = string(node.ID)
+		result.URL = string(node.URL)
+		for _, n := range node.Comments.Nodes {
+			result.Comments = append(result.Comments, n.comment())
+		}
+
+		if after = node.Comments.PageInfo.next(); after == nil {
+			break
+		}
+	}
+
+	threads, err := c.listThreadComments(ctx, owner, name, pr)
+	if err != nil {
+		return nil, err
+	}
+	result.ReviewThreads = threads
+
+	c.seen.rememberPullRequest(result, false)
+	return result, nil
+}
+
+// listThreadComments fetches every review thread of a pull request with
+// all of its comments
+func (c *Client) listThreadComments(ctx context.Context, owner, name string, pr int) ([]Thread, error) {
+	var threads []Thread
+
+	var after *graphql.String
+	for {
+		var query struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID       graphql.String
+							Path     graphql.String
+							Line     *graphql.Int
+							Comments pagedComments `graphql:"comments(first: $first)"`
+						}
+						PageInfo pageInfo
+					} `graphql:"reviewThreads(first: $threads, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+
+		// Threads rarely hold many comments, so fewer threads per page
+		// keeps the request's node count well under GitHub's limit
+		variables := map[string]interface{}{
+			"owner":   graphQLString(owner),
+			"name":    graphQLString(name),
+			"number":  graphQLInt(pr),
+			"threads": graphQLInt(commentPageSize / 2),
+			"first":   graphQLInt(commentPageSize),
+			"after":   after,
+		}
+
+		if err := c.queryWithContext(ctx, "ListThreadComments", &query, variables); err != nil {
+			return nil, fmt.Errorf("list review threads of %s/%s#%d: %w", owner, name, pr, err)
+		}
+
+		for _, n := range query.Repository.PullRequest.ReviewThreads.Nodes {
+			thread := Thread{ID: string(n.ID), Path: string(n.Path)}
+			if n.Line != nil {
+				thread.Line = int(*n.Line)
+			}
+			for _, cn := range n.Comments.Nodes {
+				thread.Comments = append(thread.Comments, cn.comment())
+			}
+			if cursor := n.Comments.PageInfo.next(); cursor != nil {
+				rest, err := c.listMoreThreadComments(ctx, thread.ID, cursor)
+				if err != nil {
+					return nil, err
+				}
+				thread.Comments = append(thread.Comments, rest...)
+			}
+			for i := range thread.Comments {
+				thread.Comments[i].Path = thread.Path
+			}
+			threads = append(threads, thread)
+		}
+
+		if after = query.Repository.PullRequest.ReviewThreads.PageInfo.next(); after == nil {
+			break
+		}
+	}
+
+	return threads, nil
+}
+
+// listMoreThreadComments fetches the comments of a review thread after
+// cursor
+func (c *Client) listMoreThreadComments(ctx context.Context, threadID string, after *graphql.String) ([]Comment, error) {
+	var comments []Comment
+
+	for after != nil {
+		var query struct {
+			Node struct {
+				Thread struct {
+					Comments pagedComments `graphql:"comments(first: $first, after: $after)"`
+				} `graphql:"... on PullRequestReviewThread"`
+			} `graphql:"node(id: $id)"`
+		}
+
+		variables := map[string]interface{}{
+			"id":    graphQLID(threadID),
+			"first": graphQLInt(commentPageSize),
+			"after": after,
+		}
+
+		if err := c.queryWithContext(ctx, "ListMoreThreadComments", &query, variables); err != nil {
+			return nil, fmt.Errorf("list comments of thread %s: %w", threadID, err)
+		}
+
+		for _, n := range query.Node.Thread.Comments.Nodes {
+			comments = append(comments, n.comment())
+		}
+		after = query.Node.Thread.Comments.PageInfo.next()
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListPullRequestComments(t *testing.T) {
+	client := newTestClient(t,
+		`{"data":{"repository":{"pullRequest":{"id":"PR_1","url":"https://github.com/o/r/pull/1","comments":{
+			"nodes":[{"id":"IC_1","body":"one","createdAt":"2025-01-01T01:00:00Z","author":{"login":"codecov"},"viewerCanMinimize":true}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"id":"PR_1","url":"https://github.com/o/r/pull/1","comments":{
+			"nodes":[{"id":"IC_2","body":"two","createdAt":"2025-01-01T02:00:00Z","author":{"login":"codecov"},"isMinimized":true}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"nodes":[{"id":"PRRT_1","path":"main.go","line":3,"comments":{
+				"nodes":[{"id":"PRRC_1","body":"first","createdAt":"2025-01-01T03:00:00Z","author":{"login":"alice"}}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"t1"}}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"r1"}}}}}}`,
+		`{"data":{"node":{"comments":{
+			"nodes":[{"id":"PRRC_2","body":"second","createdAt":"2025-01-01T04:00:00Z","author":{"login":"bob"}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"t2"}}}}}`,
+	)
+
+	pr, err := client.ListPullRequestComments(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("ListPullRequestComments() error = %v", err)
+	}
+
+	var comments []string
+	for _, c := range pr.Comments {
+		comments = append(comments, c.ID)
+	}
+	if got := strings.Join(comments, ","); got != "IC_1,IC_2" {
+		t.Errorf("Comments = %s, want IC_1,IC_2", got)
+	}
+	if !pr.Comments[0].ViewerCanMinimize || !pr.Comments[1].IsMinimized {
+		t.Errorf("Comments = %+v, want flags kept", pr.Comments)
+	}
+
+	if len(pr.ReviewThreads) != 1 {
+		t.Fatalf("ReviewThreads = %+v, want one thread", pr.ReviewThreads)
+	}
+	thread := pr.ReviewThreads[0]
+	var replies []string
+	for _, c := range thread.Comments {
+		replies = append(replies, c.ID+"@"+c.Path)
+	}
+	if want := []string{"PRRC_1@main.go", "PRRC_2@main.go"}; !reflect.DeepEqual(replies, want) {
+		t.Errorf("thread comments = %v, want %v", replies, want)
+	}
+	if pr.Repository != "o/r" || pr.Number != 1 || thread.Line != 3 {
+		t.Errorf("pull request = %s#%d, line %d", pr.Repository, pr.Number, thread.Line)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Mutation is a GraphQL mutation recorded by a dry-run client
+type Mutation struct {
+	Name      string          `json:"name"`
+	Query     string          `json:"query"`
+	Variables json.RawMessage `json:"variables"`
+}
+
+// NewDryRunClient creates a client that sends queries but records
+// mutations instead of sending them. Recorded mutations are returned by
+// Mutations; each one is answered with an empty response.
+func NewDryRunClient() (*Client, error) {
+	return NewDryRunClientForHost("")
+}
+
+// NewDryRunClientForHost creates a dry-run client for host; an empty host
+// means the default host
+func NewDryRunClientForHost(host string) (*Client, error) {
+	return NewDryRunClientWithOptions(api.ClientOptions{Host: host})
+}
+
+// NewDryRunClientWithOptions creates a dry-run client with custom options
+// (for testing)
+func NewDryRunClientWithOptions(opts api.ClientOptions) (*Client, error) {
+	next := opts.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	recorder := &dryRunTransport{next: next}
+	opts.Transport = recorder
+
+	client, err := NewClientWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	client.dryRun = recorder
+	return client, nil
+}
+
+// Mutations returns the mutations recorded by a dry-run client, in the
+// order they would have been sent
+func (c *Client) Mutations() []Mutation {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return append([]Mutation(nil), c.dryRun.mutations...)
+}
+
+// dryRunTransport passes queries through and records mutations
+type dryRunTransport struct {
+	next http.RoundTripper
+
+	mu        sync.Mutex
+	mutations []Mutation
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || !strings.HasPrefix(payload.Query, "mutation") {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return t.next.RoundTrip(req)
+	}
+
+	t.mu.Lock()
+	t.mutations = append(t.mutations, Mutation{
+		Name:      operationName(payload.Query),
+		Query:     payload.Query,
+		Variables: payload.Variables,
+	})
+	t.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+		Request:    req,
+	}, nil
+}
+
+// operationName returns the name of a GraphQL operation, e.g. "AddReply"
+// for "mutation AddReply($input:...){...}"
+func operationName(query string) string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return r == ' ' || r == '(' || r == '{'
+	})
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestDryRunClient(t *testing.T) {
+	var requests []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"node":{"id":"PRRC_1","path":"main.go","viewerCanReact":true,"author":{"login":"reviewer"},
+			"reactionGroups":[{"content":"EYES","users":{"totalCount":2},"viewerHasReacted":true}]}}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDryRunClientWithOptions(api.ClientOptions{
+		Host:         strings.TrimPrefix(server.URL, "https://"),
+		AuthToken:    "test-token",
+		Transport:    server.Client().Transport,
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatalf("NewDryRunClientWithOptions() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Queries are sent
+	comment, err := client.GetComment(ctx, "PRRC_1")
+	if err != nil {
+		t.Fatalf("GetComment() error = %v", err)
+	}
+	if comment.Path != "main.go" || !comment.ViewerCanReact || comment.Author.Login != "reviewer" {
+		t.Errorf("GetComment() = %+v", comment)
+	}
+	if len(comment.ReactionGroups) != 1 || !comment.ReactionGroups[0].ViewerHasReacted {
+		t.Errorf("GetComment() reactions = %+v", comment.ReactionGroups)
+	}
+
+	// Mutations are recorded, not sent
+	if _, err := client.AddReaction(ctx, "PRRC_1", "ROCKET"); err != nil {
+		t.Fatalf("AddReaction() error = %v", err)
+	}
+	if _, err := client.ResolveThread(ctx, "PRRT_1"); err != nil {
+		t.Fatalf("ResolveThread() error = %v", err)
+	}
+
+	if len(requests) != 1 {
+		t.Errorf("server got %d requests, want 1", len(requests))
+	}
+
+	mutations := client.Mutations()
+	if len(mutations) != 2 {
+		t.Fatalf("Mutations() returned %d, want 2", len(mutations))
+	}
+	if mutations[0].Name != "AddReaction" {
+		t.Errorf("Name = %q, want AddReaction", mutations[0].Name)
+	}
+	if !strings.HasPrefix(mutations[0].Query, "mutation AddReaction($input:AddReactionInput!){addReaction(input: $input)") {
+		t.Errorf("Query = %q", mutations[0].Query)
+	}
+	if got := string(mutations[0].Variables); got != `{"input":{"subjectId":"PRRC_1","content":"ROCKET"}}` {
+		t.Errorf("Variables = %s", got)
+	}
+	if mutations[1].Name != "ResolveThread" {
+		t.Errorf("Name = %q, want ResolveThread", mutations[1].Name)
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"mutation AddReply($input:AddPullRequestReviewThreadReplyInput!){}", "AddReply"},
+		{"mutation Unminimize{unminimizeComment}", "Unminimize"},
+		{"mutation", ""},
+	}
+
+	for _, tt := range tests {
+		if got := operationName(tt.query); got != tt.want {
+			t.Errorf("operationName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -6,10 +6,15 @@ import (
 	"strings"
 
 	graphql "github.com/cli/shurcooL-graphql"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
 )
 
-// MinimizeComment hides/minimizes a comment
-func (c *Client) MinimizeComment(ctx context.Context, commentID, classifier string) error {
+// MinimizeComment hides/minimizes a comment and returns its new state
+func (c *Client) MinimizeComment(ctx context.Context, commentID, classifier string) (*Comment, error) {
+	if err := c.checkClassifier(classifier); err != nil {
+		return nil, err
+	}
+
 	var mutation struct {
 		MinimizeComment struct {
 			MinimizedComment struct {
@@ -31,20 +36,30 @@ func (c *Client) MinimizeComment(ctx context.Context, commentID, classifier stri
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "MinimizeComment", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.Hide, ID: commentID, Reason: classifier}, func(*journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "MinimizeComment", &mutation, variables); err != nil {
+			return fmt.Errorf("minimize comment: %w", c.unsupported(err, "hide reason", "ReportedContentClassifiers", classifier))
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("minimize comment: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return &Comment{
+		ID:              commentID,
+		IsMinimized:     bool(mutation.MinimizeComment.MinimizedComment.IsMinimized),
+		MinimizedReason: string(mutation.MinimizeComment.MinimizedComment.MinimizedReason),
+	}, nil
 }
 
-// UnminimizeComment unhides a comment
-func (c *Client) UnminimizeComment(ctx context.Context, commentID string) error {
+// UnminimizeComment unhides a comment and returns its new state
+func (c *Client) UnminimizeComment(ctx context.Context, commentID string) (*Comment, error) {
 	var mutation struct {
 		UnminimizeComment struct {
 			UnminimizedComment struct {
-				IsMinimized graphql.Boolean
+				IsMinimized     graphql.Boolean
+				MinimizedReason graphql.String
 			}
 		} `graphql:"unminimizeComment(input: $input)"`
 	}
@@ -59,12 +74,21 @@ func (c *Client) UnminimizeComment(ctx context.Context, commentID string) error
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "UnminimizeComment", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.Unhide, ID: commentID}, func(*journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "UnminimizeComment", &mutation, variables); err != nil {
+			return fmt.Errorf("unminimize comment: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("unminimize comment: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return &Comment{
+		ID:              commentID,
+		IsMinimized:     bool(mutation.UnminimizeComment.UnminimizedComment.IsMinimized),
+		MinimizedReason: string(mutation.UnminimizeComment.UnminimizedComment.MinimizedReason),
+	}, nil
 }
 
 // ParseClassifier converts user-friendly reason to GraphQL classifier
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// HostFeatures limits the reactions and minimize classifiers a host
+// accepts, for GitHub Enterprise Server versions that predate some of
+// them. Empty lists allow everything.
+type HostFeatures struct {
+	Reactions   []string
+	Classifiers []string
+}
+
+// Host returns the host the client talks to, or "" for the default host
+func (c *Client) Host() string {
+	return c.host
+}
+
+// SetHostFeatures sets what the host supports. Reactions and classifiers
+// outside the lists are refused before anything is sent.
+func (c *Client) SetHostFeatures(f HostFeatures) {
+	c.features = f
+}
+
+// CheckReaction returns an error if the host does not support a reaction.
+// AddReaction and RemoveReaction check too; commands call it to refuse a
+// reaction before looking anything up.
+func (c *Client) CheckReaction(content string) error {
+	if supports(c.features.Reactions, content) {
+		return nil
+	}
+	return &UnsupportedError{Host: hostName(c.host), Kind: "reaction", Value: content, Supported: c.features.Reactions}
+}
+
+// checkClassifier returns an error if the host does not support a
+// minimize classifier
+func (c *Client) checkClassifier(classifier string) error {
+	if supports(c.features.Classifiers, classifier) {
+		return nil
+	}
+	return &UnsupportedError{Host: hostName(c.host), Kind: "hide reason", Value: classifier, Supported: c.features.Classifiers}
+}
+
+func supports(supported []string, value string) bool {
+	if len(supported) == 0 {
+		return true
+	}
+	for _, s := range supported {
+		if strings.EqualFold(s, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnsupportedError reports a reaction or hide reason the host does not
+// accept, either because the host config excludes it or because the
+// server rejected it
+type UnsupportedError struct {
+	Host  string
+	Kind  string
+	Value string
+	// Supported lists the accepted values, when known
+	Supported []string
+}
+
+func (e *UnsupportedError) Error() string {
+	msg := fmt.Sprintf("%s %s is not supported on %s", e.Kind, e.Value, e.Host)
+	if len(e.Supported) > 0 {
+		msg += fmt.Sprintf("\n\nSupported: %s", strings.Join(e.Supported, ", "))
+	} else {
+		msg += "\n\nThe server may run a GitHub Enterprise Server version that predates it"
+	}
+	return msg
+}
+
+// hostName returns host for messages, resolving the default host
+func hostName(host string) string {
+	if host != "" {
+		return host
+	}
+	if env := os.Getenv("GH_HOST"); env != "" {
+		return env
+	}
+	return "github.com"
+}
+
+// enumValuesPattern matches GitHub's error for an unknown enum value, e.g.
+// `Expected "ROCKET" to be one of: THUMBS_UP, THUMBS_DOWN`
+var enumValuesPattern = regexp.MustCompile(`Expected "([A-Z_]+)" to be one of: ([A-Z_, ]+)`)
+
+// unsupported turns a server error rejecting value of the GraphQL enum
+// enumType into an UnsupportedError, and returns other errors unchanged
+func (c *Client) unsupported(err error, kind, enumType, value string) error {
+	msg := err.Error()
+	if m := enumValuesPattern.FindStringSubmatch(msg); m != nil && m[1] == value {
+		return &UnsupportedError{Host: hostName(c.host), Kind: kind, Value: value, Supported: strings.Split(m[2], ", ")}
+	}
+	if strings.Contains(msg, enumType) && strings.Contains(msg, value) {
+		return &UnsupportedError{Host: hostName(c.host), Kind: kind, Value: value}
+	}
+	return err
+}
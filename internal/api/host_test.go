@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHostFeatures(t *testing.T) {
+	ctx := context.Background()
+
+	// Any request would fail the test: the fake answers with an error
+	client := newTestClient(t, `{"errors":[{"message":"request should not be sent"}]}`)
+	client.host = "ghe.example.com"
+	client.SetHostFeatures(HostFeatures{Reactions: []string{"THUMBS_UP"}, Classifiers: []string{"OUTDATED", "RESOLVED"}})
+
+	_, err := client.AddReaction(ctx, "PRRC_1", "ROCKET")
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("AddReaction(ROCKET) error = %v, want UnsupportedError", err)
+	}
+	want := &UnsupportedError{Host: "ghe.example.com", Kind: "reaction", Value: "ROCKET", Supported: []string{"THUMBS_UP"}}
+	if !reflect.DeepEqual(unsupported, want) {
+		t.Errorf("AddReaction(ROCKET) error = %+v, want %+v", unsupported, want)
+	}
+
+	if _, err := client.MinimizeComment(ctx, "PRRC_1", "SPAM"); !errors.As(err, &unsupported) || unsupported.Kind != "hide reason" {
+		t.Errorf("MinimizeComment(SPAM) error = %v, want UnsupportedError", err)
+	}
+
+	if _, err := client.MinimizeComment(ctx, "PRRC_1", "resolved"); errors.As(err, &unsupported) {
+		t.Errorf("MinimizeComment(resolved) error = %v, want the request to be sent", err)
+	}
+}
+
+func TestUnsupportedServerErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		response string
+		run      func(*Client) error
+		want     *UnsupportedError
+	}{
+		{
+			name:     "enum values listed",
+			response: `{"errors":[{"message":"Variable $input of type AddReactionInput! was provided invalid value for content (Expected \"ROCKET\" to be one of: THUMBS_UP, THUMBS_DOWN, LAUGH)"}]}`,
+			run: func(c *Client) error {
+				_, err := c.AddReaction(ctx, "PRRC_1", "ROCKET")
+				return err
+			},
+			want: &UnsupportedError{Host: "github.com", Kind: "reaction", Value: "ROCKET", Supported: []string{"THUMBS_UP", "THUMBS_DOWN", "LAUGH"}},
+		},
+		{
+			name:     "enum type named",
+			response: `{"errors":[{"message":"Argument 'classifier' on InputObject 'MinimizeCommentInput' has an invalid value (DUPLICATE). Expected type 'ReportedContentClassifiers!'."}]}`,
+			run: func(c *Client) error {
+				_, err := c.MinimizeComment(ctx, "PRRC_1", "DUPLICATE")
+				return err
+			},
+			want: &UnsupportedError{Host: "github.com", Kind: "hide reason", Value: "DUPLICATE"},
+		},
+		{
+			name:     "other errors unchanged",
+			response: `{"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a node with the global id of 'PRRC_1'"}]}`,
+			run: func(c *Client) error {
+				_, err := c.AddReaction(ctx, "PRRC_1", "ROCKET")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_HOST", "")
+			client := newTestClient(t, tt.response)
+			client.host = ""
+
+			err := tt.run(client)
+			if err == nil {
+				t.Fatal("error = nil")
+			}
+
+			var unsupported *UnsupportedError
+			if tt.want == nil {
+				if errors.As(err, &unsupported) {
+					t.Errorf("error = %v, want it unchanged", err)
+				}
+				return
+			}
+			if !errors.As(err, &unsupported) || !reflect.DeepEqual(unsupported, tt.want) {
+				t.Errorf("error = %#v, want %+v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// IssueMetadata holds the node IDs needed to create issues in a
+// repository, resolved from names by LookupIssueMetadata
+type IssueMetadata struct {
+	RepositoryID string
+	LabelIDs     []string
+	AssigneeIDs  []string
+	MilestoneID  string
+}
+
+// LookupIssueMetadata resolves label names, assignee logins ("@me" for the
+// viewer) and a milestone title or number in a repository
+func (c *Client) LookupIssueMetadata(ctx context.Context, owner, name string, labels, assignees []string, milestone string) (*IssueMetadata, error) {
+	var query struct {
+		Repository struct {
+			ID         graphql.String
+			Milestones struct {
+				Nodes []struct {
+					ID     graphql.String
+					Number graphql.Int
+					Title  graphql.String
+				}
+			} `graphql:"milestones(first: 100, states: OPEN)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner": graphQLString(owner),
+		"name":  graphQLString(name),
+	}
+
+	if err := c.queryWithContext(ctx, "IssueMetadata", &query, variables); err != nil {
+		return nil, fmt.Errorf("look up issue metadata: %w", err)
+	}
+
+	meta := &IssueMetadata{RepositoryID: string(query.Repository.ID)}
+
+	for _, label := range labels {
+		id, err := c.labelID(ctx, owner, name, label)
+		if err != nil {
+			return nil, err
+		}
+		meta.LabelIDs = append(meta.LabelIDs, id)
+	}
+
+	if milestone != "" {
+		number, _ := strconv.Atoi(milestone)
+		for _, m := range query.Repository.Milestones.Nodes {
+			if strings.EqualFold(string(m.Title), milestone) || (number > 0 && int(m.Number) == number) {
+				meta.MilestoneID = string(m.ID)
+				break
+			}
+		}
+		if meta.MilestoneID == "" {
+			return nil, fmt.Errorf("open milestone not found in %s/%s: %s", owner, name, milestone)
+		}
+	}
+
+	for _, login := range assignees {
+		id, err := c.userID(ctx, login)
+		if err != nil {
+			return nil, err
+		}
+		meta.AssigneeIDs = append(meta.AssigneeIDs, id)
+	}
+
+	return meta, nil
+}
+
+// labelID returns the node ID of a label in a repository. GitHub matches
+// the name case-insensitively.
+func (c *Client) labelID(ctx context.Context, owner, name, label string) (string, error) {
+	var query struct {
+		Repository struct {
+			Label *struct {
+				ID graphql.String
+			} `graphql:"label(name: $label)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": graphQLString(owner),
+		"name":  graphQLString(name),
+		"label": graphQLString(label),
+	}
+	if err := c.queryWithContext(ctx, "LabelID", &query, variables); err != nil {
+		return "", fmt.Errorf("look up label %s: %w", label, err)
+	}
+	if query.Repository.Label == nil {
+		return "", fmt.Errorf("label not found in %s/%s: %s", owner, name, label)
+	}
+	return string(query.Repository.Label.ID), nil
+}
+
+// userID returns the node ID of a user, or of the viewer for "@me"
+func (c *Client) userID(ctx context.Context, login string) (string, error) {
+	if login == "@me" {
+		var query struct {
+			Viewer struct {
+				ID graphql.String
+			}
+		}
+		if err := c.queryWithContext(ctx, "ViewerID", &query, nil); err != nil {
+			return "", fmt.Errorf("look up viewer: %w", err)
+		}
+		return string(query.Viewer.ID), nil
+	}
+
+	var query struct {
+		User *struct {
+			ID graphql.String
+		} `graphql:"user(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login": graphQLString(strings.TrimPrefix(login, "@")),
+	}
+	if err := c.queryWithContext(ctx, "UserID", &query, variables); err != nil {
+		return "", fmt.Errorf("look up user %s: %w", login, err)
+	}
+	if query.User == nil {
+		return "", fmt.Errorf("user not found: %s", login)
+	}
+	return string(query.User.ID), nil
+}
+
+// CreateIssue creates an issue in the repository meta was looked up for
+func (c *Client) CreateIssue(ctx context.Context, meta *IssueMetadata, title, body string) (*Issue, error) {
+	var mutation struct {
+		CreateIssue struct {
+			Issue struct {
+				ID     graphql.String
+				Number graphql.Int
+				URL    graphql.String
+				Title  graphql.String
+				State  graphql.String
+			}
+		} `graphql:"createIssue(input: $input)"`
+	}
+
+	type CreateIssueInput struct {
+		RepositoryID graphql.ID     `json:"repositoryId"`
+		Title        graphql.String `json:"title"`
+		Body         graphql.String `json:"body"`
+		LabelIDs     []graphql.ID   `json:"labelIds,omitempty"`
+		AssigneeIDs  []graphql.ID   `json:"assigneeIds,omitempty"`
+		MilestoneID  *graphql.ID    `json:"milestoneId,omitempty"`
+	}
+
+	input := CreateIssueInput{
+		RepositoryID: graphQLID(meta.RepositoryID),
+		Title:        graphQLString(title),
+		Body:         graphQLString(body),
+	}
+	for _, id := range meta.LabelIDs {
+		input.LabelIDs = append(input.LabelIDs, graphQLID(id))
+	}
+	for _, id := range meta.AssigneeIDs {
+		input.AssigneeIDs = append(input.AssigneeIDs, graphQLID(id))
+	}
+	if meta.MilestoneID != "" {
+		id := graphQLID(meta.MilestoneID)
+		input.MilestoneID = &id
+	}
+
+	variables := map[string]interface{}{
+		"input": input,
+	}
+
+	if err := c.mutateWithContext(ctx, "CreateIssue", &mutation, variables); err != nil {
+		return nil, fmt.Errorf("create issue: %w", err)
+	}
+
+	issue := mutation.CreateIssue.Issue
+	return &Issue{
+		ID:     string(issue.ID),
+		Number: int(issue.Number),
+		URL:    string(issue.URL),
+		Title:  string(issue.Title),
+		State:  string(issue.State),
+		Body:   body,
+	}, nil
+}
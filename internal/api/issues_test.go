@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const issueMetadataResponse = `{"data":{"repository":{"id":"R_1",
+	"milestones":{"nodes":[{"id":"MI_1","number":3,"title":"v2.0"}]}}}}`
+
+func TestLookupIssueMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		responses []string
+		labels    []string
+		assignees []string
+		milestone string
+		want      *IssueMetadata
+		wantErr   string
+	}{
+		{
+			name:      "names",
+			responses: []string{issueMetadataResponse, `{"data":{"repository":{"label":{"id":"LA_debt"}}}}`, `{"data":{"user":{"id":"U_alice"}}}`},
+			labels:    []string{"tech debt"},
+			assignees: []string{"alice"},
+			milestone: "v2.0",
+			want:      &IssueMetadata{RepositoryID: "R_1", LabelIDs: []string{"LA_debt"}, AssigneeIDs: []string{"U_alice"}, MilestoneID: "MI_1"},
+		},
+		{
+			name:      "viewer and milestone number",
+			responses: []string{issueMetadataResponse, `{"data":{"viewer":{"id":"U_me"}}}`},
+			assignees: []string{"@me"},
+			milestone: "3",
+			want:      &IssueMetadata{RepositoryID: "R_1", AssigneeIDs: []string{"U_me"}, MilestoneID: "MI_1"},
+		},
+		{
+			name:      "none",
+			responses: []string{issueMetadataResponse},
+			want:      &IssueMetadata{RepositoryID: "R_1"},
+		},
+		{
+			name:      "unknown label",
+			responses: []string{issueMetadataResponse, `{"data":{"repository":{"label":null}}}`},
+			labels:    []string{"wontfix"},
+			wantErr:   "label not found in o/r: wontfix",
+		},
+		{
+			name:      "unknown milestone",
+			responses: []string{issueMetadataResponse},
+			milestone: "v9",
+			wantErr:   "open milestone not found in o/r: v9",
+		},
+		{
+			name:      "unknown user",
+			responses: []string{issueMetadataResponse, `{"data":{"user":null}}`},
+			assignees: []string{"ghost"},
+			wantErr:   "user not found: ghost",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.responses...)
+			got, err := client.LookupIssueMetadata(ctx, "o", "r", tt.labels, tt.assignees, tt.milestone)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LookupIssueMetadata() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupIssueMetadata() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LookupIssueMetadata() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateIssue(t *testing.T) {
+	client := newTestClient(t, `{"data":{"createIssue":{"issue":{
+		"id":"I_1","number":12,"url":"https://github.com/o/r/issues/12","title":"Follow up","state":"OPEN"}}}}`)
+
+	issue, err := client.CreateIssue(context.Background(), &IssueMetadata{RepositoryID: "R_1"}, "Follow up", "Details")
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	want := Issue{ID: "I_1", Number: 12, URL: "https://github.com/o/r/issues/12", Title: "Follow up", State: "OPEN", Body: "Details"}
+	if !reflect.DeepEqual(*issue, want) {
+		t.Errorf("CreateIssue() = %+v, want %+v", issue, want)
+	}
+}
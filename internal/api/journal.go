@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	graphql "github.com/cli/shurcooL-graphql"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
+)
+
+// SetJournal sets the journal mutations are recorded in, or turns
+// journaling off when j is nil
+func (c *Client) SetJournal(j *journal.Journal) {
+	c.journal = j
+}
+
+// journaled runs mutate and records it in the journal together with the
+// state of the target beforehand. mutate may fill in entry fields that
+// are only known afterwards, such as the ID of a posted reply.
+//
+// The prior state comes from what the client last fetched or changed of
+// the target, so bulk operations cost no extra requests. Targets it has
+// not seen are looked up; if that fails the prior state is recorded as
+// unknown rather than blocking the mutation.
+func (c *Client) journaled(ctx context.Context, entry journal.Entry, mutate func(entry *journal.Entry) error) error {
+	if c.journal == nil {
+		if err := mutate(&entry); err != nil {
+			return err
+		}
+		c.seen.update(entry)
+		return nil
+	}
+
+	entry.Host = hostName(c.host)
+	if !c.seen.prior(&entry) {
+		if err := c.priorState(ctx, &entry); err != nil {
+			entry.PR, entry.Prior, entry.PriorReason = "", "", ""
+		}
+	}
+
+	if err := mutate(&entry); err != nil {
+		return err
+	}
+	c.seen.update(entry)
+
+	if _, err := c.journal.Append(entry); err != nil {
+		return fmt.Errorf("%s succeeded but was not journaled: %w", entry.Op, err)
+	}
+	return nil
+}
+
+// seenState is what a client last saw of a thread or comment
+type seenState struct {
+	pr string
+
+	resolved bool
+
+	minimized       bool
+	minimizedReason string
+	// reacted holds the viewer's reactions by content, or is nil when
+	// reactions were not fetched
+	reacted map[string]bool
+}
+
+// seenStates remembers the threads and comments a client fetched or
+// changed, by ID
+type seenStates struct {
+	mu     sync.Mutex
+	states map[string]seenState
+}
+
+// remember records the state of a thread or comment
+func (s *seenStates) remember(id string, state seenState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[string]seenState)
+	}
+	s.states[id] = state
+}
+
+// rememberPullRequest records the state of every thread and comment of pr
+func (s *seenStates) rememberPullRequest(pr *PullRequest, withReactions bool) {
+	ref := fmt.Sprintf("%s#%d", pr.Repository, pr.Number)
+	for _, c := range pr.Comments {
+		s.rememberComment(ref, c, withReactions)
+	}
+	for _, t := range pr.ReviewThreads {
+		s.remember(t.ID, seenState{pr: ref, resolved: t.IsResolved})
+		for _, c := range t.Comments {
+			s.rememberComment(ref, c, withReactions)
+		}
+	}
+}
+
+// rememberComment records the state of a comment on the pull request ref
+func (s *seenStates) rememberComment(ref string, c Comment, withReactions bool) {
+	state := seenState{pr: ref, minimized: c.IsMinimized, minimizedReason: c.MinimizedReason}
+	if withReactions {
+		state.reacted = make(map[string]bool)
+		for _, rg := range c.ReactionGroups {
+			state.reacted[rg.Content] = rg.ViewerHasReacted
+		}
+	}
+	s.remember(c.ID, state)
+}
+
+// prior fills in an entry's pull request and prior state from what was
+// seen of its target, reporting whether it could
+func (s *seenStates) prior(entry *journal.Entry) bool {
+	s.mu.Lock()
+	state, ok := s.states[entry.ID]
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	switch entry.Op {
+	case journal.Resolve, journal.Unresolve:
+		entry.Prior = journal.StateOpen
+		if state.resolved {
+			entry.Prior = journal.StateResolved
+		}
+	case journal.React, journal.Unreact:
+		if state.reacted == nil {
+			return false
+		}
+		entry.Prior = journal.StateNotReacted
+		if state.reacted[entry.Reaction] {
+			entry.Prior = journal.StateReacted
+		}
+	case journal.Hide, journal.Unhide:
+		entry.Prior = journal.StateVisible
+		if state.minimized {
+			entry.Prior = journal.StateHidden
+			entry.PriorReason = state.minimizedReason
+		}
+	}
+	entry.PR = state.pr
+	return true
+}
+
+// update records the state of an entry's target after its mutation
+func (s *seenStates) update(entry journal.Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[entry.ID]
+	if !ok {
+		return
+	}
+
+	switch entry.Op {
+	case journal.Resolve, journal.Unresolve:
+		state.resolved = entry.Op == journal.Resolve
+	case journal.React, journal.Unreact:
+		if state.reacted != nil {
+			state.reacted[entry.Reaction] = entry.Op == journal.React
+		}
+	case journal.Hide:
+		state.minimized, state.minimizedReason = true, entry.Reason
+	case journal.Unhide:
+		state.minimized, state.minimizedReason = false, ""
+	}
+	s.states[entry.ID] = state
+}
+
+// pullRequestRef is the pull request a thread or comment belongs to
+type pullRequestRef struct {
+	Number     graphql.Int
+	Repository struct {
+		NameWithOwner graphql.String
+	}
+}
+
+// String formats the reference as OWNER/REPO#NUMBER, or "" when unknown
+func (pr pullRequestRef) String() string {
+	if pr.Number == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s#%d", pr.Repository.NameWithOwner, pr.Number)
+}
+
+// priorState fills in the pull request and prior state of an entry's target
+func (c *Client) priorState(ctx context.Context, entry *journal.Entry) error {
+	type commentState struct {
+		IsMinimized     graphql.Boolean
+		MinimizedReason graphql.String
+		ReactionGroups  []struct {
+			Content          graphql.String
+			ViewerHasReacted graphql.Boolean
+		}
+	}
+
+	var query struct {
+		Node struct {
+			Thread struct {
+				IsResolved  graphql.Boolean
+				PullRequest pullRequestRef
+			} `graphql:"... on PullRequestReviewThread"`
+			ReviewComment struct {
+				commentState
+				PullRequest pullRequestRef
+			} `graphql:"... on PullRequestReviewComment"`
+			IssueComment struct {
+				commentState
+				Issue pullRequestRef
+			} `graphql:"... on IssueComment"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphQLID(entry.ID),
+	}
+
+	if err := c.queryWithContext(ctx, "PriorState", &query, variables); err != nil {
+		return fmt.Errorf("look up %s before %s: %w", entry.ID, entry.Op, err)
+	}
+
+	switch entry.Op {
+	case journal.Reply, journal.Resolve, journal.Unresolve:
+		thread := query.Node.Thread
+		entry.PR = thread.PullRequest.String()
+		if entry.Op == journal.Reply {
+			return nil
+		}
+		entry.Prior = journal.StateOpen
+		if thread.IsResolved {
+			entry.Prior = journal.StateResolved
+		}
+		return nil
+	}
+
+	comment := query.Node.IssueComment.commentState
+	entry.PR = query.Node.IssueComment.Issue.String()
+	if pr := query.Node.ReviewComment.PullRequest.String(); pr != "" {
+		comment = query.Node.ReviewComment.commentState
+		entry.PR = pr
+	}
+
+	switch entry.Op {
+	case journal.React, journal.Unreact:
+		entry.Prior = journal.StateNotReacted
+		for _, rg := range comment.ReactionGroups {
+			if string(rg.Content) == entry.Reaction && bool(rg.ViewerHasReacted) {
+				entry.Prior = journal.StateReacted
+			}
+		}
+	case journal.Hide, journal.Unhide:
+		entry.Prior = journal.StateVisible
+		if comment.IsMinimized {
+			entry.Prior = journal.StateHidden
+			entry.PriorReason = string(comment.MinimizedReason)
+		}
+	}
+	return nil
+}
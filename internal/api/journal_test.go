@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
+)
+
+func TestJournaledMutations(t *testing.T) {
+	responses := []string{
+		// PriorState for the reply, then AddReply
+		`{"data":{"node":{"isResolved":false,"pullRequest":{"number":7,"repository":{"nameWithOwner":"o/r"}}}}}`,
+		`{"data":{"addPullRequestReviewThreadReply":{"comment":{"id":"PRRC_new"}}}}`,
+		// PriorState for unhide on an issue comment, then UnminimizeComment
+		`{"data":{"node":{"isMinimized":true,"minimizedReason":"outdated","issue":{"number":7,"repository":{"nameWithOwner":"o/r"}}}}}`,
+		`{"data":{"unminimizeComment":{"unminimizedComment":{"isMinimized":false}}}}`,
+	}
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[calls]))
+		calls++
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:         strings.TrimPrefix(server.URL, "https://"),
+		AuthToken:    "test-token",
+		Transport:    server.Client().Transport,
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+	j := journal.Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+	client.SetJournal(j)
+
+	ctx := context.Background()
+	if _, err := client.ReplyToThread(ctx, "PRRT_a", "Done"); err != nil {
+		t.Fatalf("ReplyToThread() error = %v", err)
+	}
+	if _, err := client.UnminimizeComment(ctx, "IC_1"); err != nil {
+		t.Fatalf("UnminimizeComment() error = %v", err)
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	reply := entries[0]
+	if reply.Op != journal.Reply || reply.ID != "PRRT_a" || reply.Reply != "PRRC_new" || reply.PR != "o/r#7" || reply.Host != client.Host() {
+		t.Errorf("reply entry = %+v", reply)
+	}
+
+	unhide := entries[1]
+	if unhide.Op != journal.Unhide || unhide.Prior != journal.StateHidden || unhide.PriorReason != "outdated" || unhide.PR != "o/r#7" {
+		t.Errorf("unhide entry = %+v", unhide)
+	}
+}
+
+func TestJournaledPriorState(t *testing.T) {
+	var operations []string
+	responses := map[string]string{
+		"ListThreads": `{"data":{"repository":{"pullRequest":{"number":7,"reviewThreads":{"nodes":[
+			{"id":"PRRT_a","isResolved":false,"comments":{"nodes":[
+				{"id":"PRRC_1","isMinimized":true,"minimizedReason":"outdated","reactionGroups":[
+					{"content":"EYES","users":{"totalCount":1},"viewerHasReacted":true}]}]}}]}}}}}`,
+		"ResolveThread":     `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":true}}}}`,
+		"AddReaction":       `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"EYES"},"subject":{"id":"PRRC_1"}}}}`,
+		"UnminimizeComment": `{"data":{"unminimizeComment":{"unminimizedComment":{"isMinimized":false}}}}`,
+		"PriorState":        `{"errors":[{"message":"lookup failed"}]}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload struct{ Query string }
+		_ = json.Unmarshal(body, &payload)
+		// "query Name(...){...}" or "mutation Name(...){...}"
+		name := strings.FieldsFunc(payload.Query, func(r rune) bool { return r == ' ' || r == '(' || r == '{' })[1]
+		operations = append(operations, name)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[name]))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:         strings.TrimPrefix(server.URL, "https://"),
+		AuthToken:    "test-token",
+		Transport:    server.Client().Transport,
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := journal.Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+	client.SetJournal(j)
+
+	ctx := context.Background()
+	if _, err := client.GetPullRequest(ctx, "o", "r", 7); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ResolveThread(ctx, "PRRT_a"); err != nil {
+		t.Fatalf("ResolveThread() error = %v", err)
+	}
+	if _, err := client.UnminimizeComment(ctx, "PRRC_1"); err != nil {
+		t.Fatalf("UnminimizeComment() error = %v", err)
+	}
+	// An unseen target is looked up; a failed lookup must not block it
+	if _, err := client.AddReaction(ctx, "PRRC_9", "EYES"); err != nil {
+		t.Fatalf("AddReaction() error = %v", err)
+	}
+
+	want := "ListThreads,ResolveThread,UnminimizeComment,PriorState,AddReaction"
+	if got := strings.Join(operations, ","); got != want {
+		t.Errorf("operations = %s, want %s", got, want)
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		op, prior, reason, pr string
+	}{
+		{journal.Resolve, journal.StateOpen, "", "o/r#7"},
+		{journal.Unhide, journal.StateHidden, "outdated", "o/r#7"},
+		{journal.React, "", "", ""},
+	}
+	if len(entries) != len(tests) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(tests))
+	}
+	for i, tt := range tests {
+		e := entries[i]
+		if e.Op != tt.op || e.Prior != tt.prior || e.PriorReason != tt.reason || e.PR != tt.pr {
+			t.Errorf("entry %d = %+v, want %s from %q (%q) on %q", i, e, tt.op, tt.prior, tt.reason, tt.pr)
+		}
+	}
+}
@@ -5,14 +5,22 @@ import (
 	"fmt"
 
 	graphql "github.com/cli/shurcooL-graphql"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
 )
 
-// ReplyToThread adds a reply to a review thread
-func (c *Client) ReplyToThread(ctx context.Context, threadID, body string) error {
+// ReplyToThread adds a reply to a review thread and returns the new comment
+func (c *Client) ReplyToThread(ctx context.Context, threadID, body string) (*Comment, error) {
 	var mutation struct {
 		AddPullRequestReviewThreadReply struct {
 			Comment struct {
-				ID graphql.String
+				ID         graphql.String
+				DatabaseID graphql.Int
+				URL        graphql.String
+				Body       graphql.String
+				CreatedAt  string
+				Author     struct {
+					Login graphql.String
+				}
 			}
 		} `graphql:"addPullRequestReviewThreadReply(input: $input)"`
 	}
@@ -29,21 +37,38 @@ func (c *Client) ReplyToThread(ctx context.Context, threadID, body string) error
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "AddReply", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.Reply, ID: threadID}, func(entry *journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "AddReply", &mutation, variables); err != nil {
+			return fmt.Errorf("add reply: %w", err)
+		}
+		entry.Reply = string(mutation.AddPullRequestReviewThreadReply.Comment.ID)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("add reply: %w", err)
+		return nil, err
 	}
 
-	return nil
+	comment := mutation.AddPullRequestReviewThreadReply.Comment
+	return &Comment{
+		ID:         string(comment.ID),
+		DatabaseID: int(comment.DatabaseID),
+		URL:        string(comment.URL),
+		Body:       string(comment.Body),
+		CreatedAt:  parseTime(comment.CreatedAt),
+		Author:     User{Login: string(comment.Author.Login)},
+	}, nil
 }
 
-// ResolveThread marks a review thread as resolved
-func (c *Client) ResolveThread(ctx context.Context, threadID string) error {
+// ResolveThread marks a review thread as resolved and returns its new state
+func (c *Client) ResolveThread(ctx context.Context, threadID string) (*Thread, error) {
 	var mutation struct {
 		ResolveReviewThread struct {
 			Thread struct {
 				ID         graphql.String
 				IsResolved graphql.Boolean
+				ResolvedBy *struct {
+					Login graphql.String
+				}
 			}
 		} `graphql:"resolveReviewThread(input: $input)"`
 	}
@@ -58,21 +83,30 @@ func (c *Client) ResolveThread(ctx context.Context, threadID string) error {
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "ResolveThread", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.Resolve, ID: threadID}, func(*journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "ResolveThread", &mutation, variables); err != nil {
+			return fmt.Errorf("resolve thread: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("resolve thread: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return threadState(mutation.ResolveReviewThread.Thread.ID, mutation.ResolveReviewThread.Thread.IsResolved, mutation.ResolveReviewThread.Thread.ResolvedBy), nil
 }
 
-// UnresolveThread marks a review thread as unresolved
-func (c *Client) UnresolveThread(ctx context.Context, threadID string) error {
+// UnresolveThread marks a review thread as unresolved and returns its new
+// state
+func (c *Client) UnresolveThread(ctx context.Context, threadID string) (*Thread, error) {
 	var mutation struct {
 		UnresolveReviewThread struct {
 			Thread struct {
 				ID         graphql.String
 				IsResolved graphql.Boolean
+				ResolvedBy *struct {
+					Login graphql.String
+				}
 			}
 		} `graphql:"unresolveReviewThread(input: $input)"`
 	}
@@ -87,19 +121,32 @@ func (c *Client) UnresolveThread(ctx context.Context, threadID string) error {
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "UnresolveThread", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.Unresolve, ID: threadID}, func(*journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "UnresolveThread", &mutation, variables); err != nil {
+			return fmt.Errorf("unresolve thread: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("unresolve thread: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return threadState(mutation.UnresolveReviewThread.Thread.ID, mutation.UnresolveReviewThread.Thread.IsResolved, mutation.UnresolveReviewThread.Thread.ResolvedBy), nil
 }
 
-// AddReaction adds an emoji reaction to a comment
-func (c *Client) AddReaction(ctx context.Context, subjectID, content string) error {
+// AddReaction adds an emoji reaction to a comment and returns the reaction
+func (c *Client) AddReaction(ctx context.Context, subjectID, content string) (*Reaction, error) {
+	if err := c.CheckReaction(content); err != nil {
+		return nil, err
+	}
+
 	var mutation struct {
 		AddReaction struct {
 			Reaction struct {
+				ID      graphql.String
+				Content graphql.String
+			}
+			Subject struct {
 				ID graphql.String
 			}
 		} `graphql:"addReaction(input: $input)"`
@@ -117,19 +164,37 @@ func (c *Client) AddReaction(ctx context.Context, subjectID, content string) err
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "AddReaction", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.React, ID: subjectID, Reaction: content}, func(*journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "AddReaction", &mutation, variables); err != nil {
+			return fmt.Errorf("add reaction: %w", c.unsupported(err, "reaction", "ReactionContent", content))
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("add reaction: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return &Reaction{
+		ID:        string(mutation.AddReaction.Reaction.ID),
+		Content:   string(mutation.AddReaction.Reaction.Content),
+		SubjectID: string(mutation.AddReaction.Subject.ID),
+	}, nil
 }
 
-// RemoveReaction removes an emoji reaction from a comment
-func (c *Client) RemoveReaction(ctx context.Context, subjectID, content string) error {
+// RemoveReaction removes an emoji reaction from a comment and returns the
+// removed reaction
+func (c *Client) RemoveReaction(ctx context.Context, subjectID, content string) (*Reaction, error) {
+	if err := c.CheckReaction(content); err != nil {
+		return nil, err
+	}
+
 	var mutation struct {
 		RemoveReaction struct {
 			Reaction struct {
+				ID      graphql.String
+				Content graphql.String
+			}
+			Subject struct {
 				ID graphql.String
 			}
 		} `graphql:"removeReaction(input: $input)"`
@@ -147,10 +212,57 @@ func (c *Client) RemoveReaction(ctx context.Context, subjectID, content string)
 		},
 	}
 
-	err := c.mutateWithContext(ctx, "RemoveReaction", &mutation, variables)
+	err := c.journaled(ctx, journal.Entry{Op: journal.Unreact, ID: subjectID, Reaction: content}, func(*journal.Entry) error {
+		if err := c.mutateWithContext(ctx, "RemoveReaction", &mutation, variables); err != nil {
+			return fmt.Errorf("remove reaction: %w", c.unsupported(err, "reaction", "ReactionContent", content))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reaction{
+		ID:        string(mutation.RemoveReaction.Reaction.ID),
+		Content:   string(mutation.RemoveReaction.Reaction.Content),
+		SubjectID: string(mutation.RemoveReaction.Subject.ID),
+	}, nil
+}
+
+// DeleteReviewComment deletes a review comment, such as a posted reply
+func (c *Client) DeleteReviewComment(ctx context.Context, commentID string) error {
+	var mutation struct {
+		DeletePullRequestReviewComment struct {
+			ClientMutationID graphql.String
+		} `graphql:"deletePullRequestReviewComment(input: $input)"`
+	}
+
+	type DeletePullRequestReviewCommentInput struct {
+		ID graphql.ID `json:"id"`
+	}
+
+	variables := map[string]interface{}{
+		"input": DeletePullRequestReviewCommentInput{
+			ID: graphQLID(commentID),
+		},
+	}
+
+	err := c.mutateWithContext(ctx, "DeleteReviewComment", &mutation, variables)
 	if err != nil {
-		return fmt.Errorf("remove reaction: %w", err)
+		return fmt.Errorf("delete comment: %w", err)
 	}
 
 	return nil
 }
+
+// threadState converts a thread mutation payload to a Thread
+func threadState(id graphql.String, isResolved graphql.Boolean, resolvedBy *struct{ Login graphql.String }) *Thread {
+	thread := &Thread{
+		ID:         string(id),
+		IsResolved: bool(isResolved),
+	}
+	if resolvedBy != nil {
+		thread.ResolvedBy = &User{Login: string(resolvedBy.Login)}
+	}
+	return thread
+}
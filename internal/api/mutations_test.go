@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// newTestClient returns a client whose requests are answered with
+// responses in order
+func newTestClient(t *testing.T, responses ...string) *Client {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[calls%len(responses)]))
+		calls++
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:         strings.TrimPrefix(server.URL, "https://"),
+		AuthToken:    "test-token",
+		Transport:    server.Client().Transport,
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+	return client
+}
+
+func TestMutationPayloads(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reply", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"addPullRequestReviewThreadReply":{"comment":{
+			"id":"PRRC_new","databaseId":42,"url":"https://github.com/o/r/pull/1#discussion_r42","body":"Done",
+			"createdAt":"2025-01-01T10:00:00Z","author":{"login":"me"}}}}}`)
+		comment, err := client.ReplyToThread(ctx, "PRRT_a", "Done")
+		if err != nil {
+			t.Fatalf("ReplyToThread() error = %v", err)
+		}
+		if comment.ID != "PRRC_new" || comment.DatabaseID != 42 || !strings.HasSuffix(comment.URL, "#discussion_r42") || comment.Author.Login != "me" || comment.CreatedAt.IsZero() {
+			t.Errorf("ReplyToThread() = %+v", comment)
+		}
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":true,"resolvedBy":{"login":"me"}}}}}`)
+		thread, err := client.ResolveThread(ctx, "PRRT_a")
+		if err != nil {
+			t.Fatalf("ResolveThread() error = %v", err)
+		}
+		if thread.ID != "PRRT_a" || !thread.IsResolved || thread.ResolvedBy == nil || thread.ResolvedBy.Login != "me" {
+			t.Errorf("ResolveThread() = %+v", thread)
+		}
+	})
+
+	t.Run("react", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"ROCKET"},"subject":{"id":"PRRC_1"}}}}`)
+		reaction, err := client.AddReaction(ctx, "PRRC_1", "ROCKET")
+		if err != nil {
+			t.Fatalf("AddReaction() error = %v", err)
+		}
+		if *reaction != (Reaction{ID: "REA_1", Content: "ROCKET", SubjectID: "PRRC_1"}) {
+			t.Errorf("AddReaction() = %+v", reaction)
+		}
+	})
+
+	t.Run("hide", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true,"minimizedReason":"outdated"}}}}`)
+		comment, err := client.MinimizeComment(ctx, "IC_1", "OUTDATED")
+		if err != nil {
+			t.Fatalf("MinimizeComment() error = %v", err)
+		}
+		if comment.ID != "IC_1" || !comment.IsMinimized || comment.MinimizedReason != "outdated" {
+			t.Errorf("MinimizeComment() = %+v", comment)
+		}
+	})
+}
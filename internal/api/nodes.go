@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// commentNode is the part of a comment shared by review and issue comments
+type commentNode struct {
+	ID                graphql.String
+	DatabaseID        graphql.Int
+	URL               graphql.String
+	Body              graphql.String
+	CreatedAt         string
+	IsMinimized       graphql.Boolean
+	MinimizedReason   graphql.String
+	ViewerCanReact    graphql.Boolean
+	ViewerCanMinimize graphql.Boolean
+	Author            struct {
+		Login graphql.String
+	}
+	ReactionGroups []reactionGroupNode
+}
+
+// comment converts the node to our Comment type
+func (n commentNode) comment() Comment {
+	comment := Comment{
+		ID:                string(n.ID),
+		DatabaseID:        int(n.DatabaseID),
+		URL:               string(n.URL),
+		Body:              string(n.Body),
+		CreatedAt:         parseTime(n.CreatedAt),
+		IsMinimized:       bool(n.IsMinimized),
+		MinimizedReason:   string(n.MinimizedReason),
+		ViewerCanReact:    bool(n.ViewerCanReact),
+		ViewerCanMinimize: bool(n.ViewerCanMinimize),
+		Author:            User{Login: string(n.Author.Login)},
+	}
+
+	comment.ReactionGroups = reactionGroups(n.ReactionGroups)
+
+	return comment
+}
+
+// threadComments is one page of a thread's comments
+type threadComments struct {
+	Nodes    []commentNode
+	PageInfo pageInfo
+}
+
+// GetThread fetches a single review thread by ID with all of its
+// comments
+func (c *Client) GetThread(ctx context.Context, threadID string) (*Thread, error) {
+	var query struct {
+		Node struct {
+			Thread struct {
+				ID                 graphql.String
+				IsResolved         graphql.Boolean
+				IsOutdated         graphql.Boolean
+				Path               graphql.String
+				Line               *graphql.Int
+				ViewerCanResolve   graphql.Boolean
+				ViewerCanUnresolve graphql.Boolean
+				ViewerCanReply     graphql.Boolean
+				PullRequest        pullRequestRef
+				Comments           threadComments `graphql:"comments(first: 50)"`
+			} `graphql:"... on PullRequestReviewThread"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphQLID(threadID),
+	}
+
+	if err := c.queryWithContext(ctx, "GetThread", &query, variables); err != nil {
+		return nil, fmt.Errorf("get thread %s: %w", threadID, err)
+	}
+
+	node := query.Node.Thread
+	if node.ID == "" {
+		return nil, fmt.Errorf("thread not found: %s", threadID)
+	}
+
+	thread := &Thread{
+		ID:                 string(node.ID),
+		IsResolved:         bool(node.IsResolved),
+		IsOutdated:         bool(node.IsOutdated),
+		Path:               string(node.Path),
+		ViewerCanResolve:   bool(node.ViewerCanResolve),
+		ViewerCanUnresolve: bool(node.ViewerCanUnresolve),
+		ViewerCanReply:     bool(node.ViewerCanReply),
+	}
+	if node.Line != nil {
+		thread.Line = int(*node.Line)
+	}
+	comments := node.Comments
+	for {
+		for _, n := range comments.Nodes {
+			comment := n.comment()
+			comment.Path = thread.Path
+			thread.Comments = append(thread.Comments, comment)
+		}
+
+		after := comments.PageInfo.next()
+		if after == nil {
+			break
+		}
+
+		var page struct {
+			Node struct {
+				Thread struct {
+					Comments threadComments `graphql:"comments(first: 50, after: $after)"`
+				} `graphql:"... on PullRequestReviewThread"`
+			} `graphql:"node(id: $id)"`
+		}
+		variables["after"] = after
+		if err := c.queryWithContext(ctx, "GetMoreThreadComments", &page, variables); err != nil {
+			return nil, fmt.Errorf("get comments of thread %s: %w", threadID, err)
+		}
+		comments = page.Node.Thread.Comments
+	}
+
+	ref := node.PullRequest.String()
+	c.seen.remember(thread.ID, seenState{pr: ref, resolved: thread.IsResolved})
+	for _, comment := range thread.Comments {
+		c.seen.rememberComment(ref, comment, true)
+	}
+	return thread, nil
+}
+
+// GetComment fetches a single review or issue comment by ID
+func (c *Client) GetComment(ctx context.Context, commentID string) (*Comment, error) {
+	var query struct {
+		Node struct {
+			ReviewComment struct {
+				commentNode
+				Path        graphql.String
+				PullRequest pullRequestRef
+			} `graphql:"... on PullRequestReviewComment"`
+			IssueComment struct {
+				commentNode
+				Issue pullRequestRef
+			} `graphql:"... on IssueComment"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphQLID(commentID),
+	}
+
+	if err := c.queryWithContext(ctx, "GetComment", &query, variables); err != nil {
+		return nil, fmt.Errorf("get comment %s: %w", commentID, err)
+	}
+
+	if query.Node.ReviewComment.ID != "" {
+		comment := query.Node.ReviewComment.comment()
+		comment.Path = string(query.Node.ReviewComment.Path)
+		c.seen.rememberComment(query.Node.ReviewComment.PullRequest.String(), comment, true)
+		return &comment, nil
+	}
+	if query.Node.IssueComment.ID != "" {
+		comment := query.Node.IssueComment.comment()
+		c.seen.rememberComment(query.Node.IssueComment.Issue.String(), comment, true)
+		return &comment, nil
+	}
+	return nil, fmt.Errorf("comment not found: %s", commentID)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Targets of the cassette tests: PR #1 of the gh-talk repository and its
+// first review thread. Mutation cassettes undo their change, so recording
+// them leaves the PR as it was.
+const (
+	cassetteOwner   = "hamishmorgan"
+	cassetteRepo    = "gh-talk"
+	cassettePR      = 1
+	cassetteThread  = "PRRT_kwDOQN97u85gQeTN"
+	cassetteComment = "PRRC_kwDOQN97u86UHqK7"
+)
+
+func TestQueriesCassettes(t *testing.T) {
+	tests := []struct {
+		cassette string
+		run      func(ctx context.Context, t *testing.T, client *Client)
+	}{
+		{"list_threads", func(ctx context.Context, t *testing.T, client *Client) {
+			threads, err := client.ListThreads(ctx, cassetteOwner, cassetteRepo, cassettePR)
+			if err != nil {
+				t.Fatalf("ListThreads() error = %v", err)
+			}
+			if len(threads) == 0 {
+				t.Fatal("ListThreads() returned no threads")
+			}
+			for _, thread := range threads {
+				if !strings.HasPrefix(thread.ID, "PRRT_") || len(thread.Comments) == 0 {
+					t.Errorf("thread = %+v, want an ID and comments", thread)
+				}
+			}
+		}},
+		{"get_thread", func(ctx context.Context, t *testing.T, client *Client) {
+			thread, err := client.GetThread(ctx, cassetteThread)
+			if err != nil {
+				t.Fatalf("GetThread() error = %v", err)
+			}
+			if thread.ID != cassetteThread || thread.Path == "" || len(thread.Comments) == 0 {
+				t.Errorf("GetThread() = %+v", thread)
+			}
+		}},
+		{"get_comment", func(ctx context.Context, t *testing.T, client *Client) {
+			comment, err := client.GetComment(ctx, cassetteComment)
+			if err != nil {
+				t.Fatalf("GetComment() error = %v", err)
+			}
+			if comment.ID != cassetteComment || comment.Body == "" || comment.Author.Login == "" {
+				t.Errorf("GetComment() = %+v", comment)
+			}
+		}},
+		{"current_user", func(ctx context.Context, t *testing.T, client *Client) {
+			login, err := client.CurrentUser(ctx)
+			if err != nil || login == "" {
+				t.Errorf("CurrentUser() = %q, %v", login, err)
+			}
+		}},
+		{"resolve_unresolve", func(ctx context.Context, t *testing.T, client *Client) {
+			thread, err := client.ResolveThread(ctx, cassetteThread)
+			if err != nil {
+				t.Fatalf("ResolveThread() error = %v", err)
+			}
+			if !thread.IsResolved {
+				t.Errorf("ResolveThread() = %+v, want resolved", thread)
+			}
+			thread, err = client.UnresolveThread(ctx, cassetteThread)
+			if err != nil {
+				t.Fatalf("UnresolveThread() error = %v", err)
+			}
+			if thread.IsResolved {
+				t.Errorf("UnresolveThread() = %+v, want open", thread)
+			}
+		}},
+		{"react_unreact", func(ctx context.Context, t *testing.T, client *Client) {
+			reaction, err := client.AddReaction(ctx, cassetteComment, "HOORAY")
+			if err != nil {
+				t.Fatalf("AddReaction() error = %v", err)
+			}
+			if reaction.Content != "HOORAY" || reaction.SubjectID != cassetteComment {
+				t.Errorf("AddReaction() = %+v", reaction)
+			}
+			if _, err := client.RemoveReaction(ctx, cassetteComment, "HOORAY"); err != nil {
+				t.Fatalf("RemoveReaction() error = %v", err)
+			}
+		}},
+		{"hide_unhide", func(ctx context.Context, t *testing.T, client *Client) {
+			comment, err := client.MinimizeComment(ctx, cassetteComment, "OUTDATED")
+			if err != nil {
+				t.Fatalf("MinimizeComment() error = %v", err)
+			}
+			if !comment.IsMinimized || !strings.EqualFold(comment.MinimizedReason, "outdated") {
+				t.Errorf("MinimizeComment() = %+v", comment)
+			}
+			comment, err = client.UnminimizeComment(ctx, cassetteComment)
+			if err != nil {
+				t.Fatalf("UnminimizeComment() error = %v", err)
+			}
+			if comment.IsMinimized {
+				t.Errorf("UnminimizeComment() = %+v, want visible", comment)
+			}
+		}},
+		{"reply_delete", func(ctx context.Context, t *testing.T, client *Client) {
+			comment, err := client.ReplyToThread(ctx, cassetteThread, "Recording a cassette, this reply is deleted right away")
+			if err != nil {
+				t.Fatalf("ReplyToThread() error = %v", err)
+			}
+			if !strings.HasPrefix(comment.ID, "PRRC_") || comment.URL == "" {
+				t.Errorf("ReplyToThread() = %+v", comment)
+			}
+			if err := client.DeleteReviewComment(ctx, comment.ID); err != nil {
+				t.Fatalf("DeleteReviewComment() error = %v", err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cassette, func(t *testing.T) {
+			tt.run(context.Background(), t, newCassetteClient(t, tt.cassette))
+		})
+	}
+}
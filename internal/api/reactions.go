@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// reactionDetailNode is a comment with up to 100 users per reaction group
+type reactionDetailNode struct {
+	ID        graphql.String
+	URL       graphql.String
+	Body      graphql.String
+	CreatedAt string
+	Author    struct {
+		Login graphql.String
+	}
+	// ReactionGroups has the fields of reactionGroupNode, with more users
+	ReactionGroups []struct {
+		Content          graphql.String
+		CreatedAt        *string
+		Users            reactionUsersNode `graphql:"users(first: 100)"`
+		ViewerHasReacted graphql.Boolean
+	}
+}
+
+// comment converts the node to our Comment type
+func (n reactionDetailNode) comment() Comment {
+	groups := make([]reactionGroupNode, len(n.ReactionGroups))
+	for i, rg := range n.ReactionGroups {
+		groups[i] = reactionGroupNode(rg)
+	}
+
+	return Comment{
+		ID:             string(n.ID),
+		URL:            string(n.URL),
+		Body:           string(n.Body),
+		CreatedAt:      parseTime(n.CreatedAt),
+		Author:         User{Login: string(n.Author.Login)},
+		ReactionGroups: reactionGroups(groups),
+	}
+}
+
+// GetReactions fetches the reactions on a comment, or on every comment of
+// a review thread, with the users who reacted
+func (c *Client) GetReactions(ctx context.Context, id string) ([]Comment, error) {
+	var query struct {
+		Node struct {
+			Typename graphql.String `graphql:"__typename"`
+			Thread   struct {
+				ID       graphql.String
+				Path     graphql.String
+				Comments struct {
+					Nodes []reactionDetailNode
+				} `graphql:"comments(first: 50)"`
+			} `graphql:"... on PullRequestReviewThread"`
+			ReviewComment struct {
+				reactionDetailNode
+				Path graphql.String
+			} `graphql:"... on PullRequestReviewComment"`
+			IssueComment struct {
+				reactionDetailNode
+			} `graphql:"... on IssueComment"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphQLID(id),
+	}
+
+	if err := c.queryWithContext(ctx, "GetReactions", &query, variables); err != nil {
+		return nil, fmt.Errorf("get reactions on %s: %w", id, err)
+	}
+
+	// Fragment fields are filled from any node with the same keys, so
+	// the type decides which one to read
+	node := query.Node
+	switch node.Typename {
+	case "PullRequestReviewThread":
+		comments := make([]Comment, 0, len(node.Thread.Comments.Nodes))
+		for _, n := range node.Thread.Comments.Nodes {
+			comment := n.comment()
+			comment.Path = string(node.Thread.Path)
+			comments = append(comments, comment)
+		}
+		return comments, nil
+	case "PullRequestReviewComment":
+		comment := node.ReviewComment.comment()
+		comment.Path = string(node.ReviewComment.Path)
+		return []Comment{comment}, nil
+	case "IssueComment":
+		return []Comment{node.IssueComment.comment()}, nil
+	}
+	return nil, fmt.Errorf("comment or thread not found: %s", id)
+}
+
+// ListReactors fetches every user who reacted to a comment with content,
+// paging past the users listings and GetReactions include
+func (c *Client) ListReactors(ctx context.Context, subjectID, content string) ([]User, error) {
+	// The variable's type name must match the GraphQL enum
+	type ReactionContent string
+
+	var users []User
+	var after *graphql.String
+	for {
+		var query struct {
+			Node struct {
+				Reactable struct {
+					Reactions struct {
+						Nodes []struct {
+							User *struct {
+								Login graphql.String
+							}
+						}
+						PageInfo pageInfo
+					} `graphql:"reactions(content: $content, first: $first, after: $after)"`
+				} `graphql:"... on Reactable"`
+			} `graphql:"node(id: $id)"`
+		}
+
+		variables := map[string]interface{}{
+			"id":      graphQLID(subjectID),
+			"content": ReactionContent(content),
+			"first":   graphQLInt(commentPageSize),
+			"after":   after,
+		}
+
+		if err := c.queryWithContext(ctx, "ListReactors", &query, variables); err != nil {
+			return nil, fmt.Errorf("list %s reactions on %s: %w", content, subjectID, err)
+		}
+
+		reactions := query.Node.Reactable.Reactions
+		for _, n := range reactions.Nodes {
+			// Deleted accounts have no user
+			if n.User != nil {
+				users = append(users, User{Login: string(n.User.Login)})
+			}
+		}
+		if after = reactions.PageInfo.next(); after == nil {
+			break
+		}
+	}
+
+	return users, nil
+}
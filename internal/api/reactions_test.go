@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetReactions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("comment", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"node":{"__typename":"PullRequestReviewComment","id":"PRRC_1","path":"main.go","body":"Use a constant","author":{"login":"bob"},
+			"reactionGroups":[
+				{"content":"THUMBS_UP","createdAt":"2025-01-02T03:04:05Z","users":{"totalCount":2,"nodes":[{"login":"alice"},{"login":"carol"}]},"viewerHasReacted":true},
+				{"content":"HEART","createdAt":null,"users":{"totalCount":0,"nodes":[]},"viewerHasReacted":false}
+			]}}}`)
+
+		comments, err := client.GetReactions(ctx, "PRRC_1")
+		if err != nil {
+			t.Fatalf("GetReactions() error = %v", err)
+		}
+		if len(comments) != 1 || comments[0].ID != "PRRC_1" || comments[0].Path != "main.go" {
+			t.Fatalf("GetReactions() = %+v", comments)
+		}
+		groups := comments[0].ReactionGroups
+		if len(groups) != 1 {
+			t.Fatalf("reaction groups = %+v, want only THUMBS_UP", groups)
+		}
+		rg := groups[0]
+		if rg.Content != "THUMBS_UP" || !rg.ViewerHasReacted || rg.Users.TotalCount != 2 ||
+			len(rg.Users.Nodes) != 2 || rg.Users.Nodes[1].Login != "carol" {
+			t.Errorf("reaction group = %+v", rg)
+		}
+		if rg.CreatedAt == nil || !rg.CreatedAt.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("CreatedAt = %v", rg.CreatedAt)
+		}
+	})
+
+	t.Run("thread", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"node":{"__typename":"PullRequestReviewThread","id":"PRRT_a","path":"main.go","comments":{"nodes":[
+			{"id":"PRRC_1","body":"Use a constant","author":{"login":"bob"},"reactionGroups":[
+				{"content":"EYES","createdAt":"2025-01-02T03:04:05Z","users":{"totalCount":1,"nodes":[{"login":"alice"}]},"viewerHasReacted":false}]},
+			{"id":"PRRC_2","body":"Done","author":{"login":"alice"},"reactionGroups":[]}
+		]}}}}`)
+
+		comments, err := client.GetReactions(ctx, "PRRT_a")
+		if err != nil {
+			t.Fatalf("GetReactions() error = %v", err)
+		}
+		if len(comments) != 2 || comments[0].Path != "main.go" || len(comments[0].ReactionGroups) != 1 || len(comments[1].ReactionGroups) != 0 {
+			t.Errorf("GetReactions() = %+v", comments)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := newTestClient(t, `{"data":{"node":null}}`)
+		if _, err := client.GetReactions(ctx, "PRRC_missing"); err == nil {
+			t.Error("GetReactions() error = nil")
+		}
+	})
+}
+
+func TestListReactors(t *testing.T) {
+	client := newTestClient(t,
+		`{"data":{"node":{"reactions":{"nodes":[{"user":{"login":"alice"}},{"user":null}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}`,
+		`{"data":{"node":{"reactions":{"nodes":[{"user":{"login":"bob"}}],"pageInfo":{"hasNextPage":false}}}}}`,
+	)
+
+	users, err := client.ListReactors(context.Background(), "PRRC_1", "THUMBS_UP")
+	if err != nil {
+		t.Fatalf("ListReactors() error = %v", err)
+	}
+	if len(users) != 2 || users[0].Login != "alice" || users[1].Login != "bob" {
+		t.Errorf("ListReactors() = %+v, want alice and bob", users)
+	}
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// searchPullRequest is the part of a pull request search result shared by
+// summaries and metrics
+type searchPullRequest struct {
+	ID        graphql.String
+	Number    graphql.Int
+	Title     graphql.String
+	State     graphql.String
+	URL       graphql.String
+	CreatedAt string
+	Author    struct {
+		Login graphql.String
+	}
+	Repository struct {
+		NameWithOwner graphql.String
+	}
+}
+
+// searchCommentNode is a review comment in a search result
+type searchCommentNode struct {
+	ID        graphql.String
+	CreatedAt string
+	Author    struct {
+		Login graphql.String
+	}
+}
+
+// searchSummaryNodes is the shape of a pull request search for summaries.
+// Each thread brings only its first and last comment, which is all a
+// summary needs, to keep the query far below GitHub's node limit.
+type searchSummaryNodes struct {
+	Nodes []struct {
+		PullRequest struct {
+			searchPullRequest
+			ReviewThreads struct {
+				Nodes []struct {
+					ID           graphql.String
+					IsResolved   graphql.Boolean
+					FirstComment struct {
+						Nodes []searchCommentNode
+					} `graphql:"firstComment: comments(first: 1)"`
+					LastComment struct {
+						Nodes []searchCommentNode
+					} `graphql:"lastComment: comments(last: 1)"`
+				}
+			} `graphql:"reviewThreads(first: 100)"`
+		} `graphql:"... on PullRequest"`
+	}
+}
+
+// searchMetricsNodes is the shape of a pull request search for metrics,
+// with every comment of each thread
+type searchMetricsNodes struct {
+	Nodes []struct {
+		PullRequest struct {
+			searchPullRequest
+			ReviewThreads struct {
+				Nodes []struct {
+					ID         graphql.String
+					IsResolved graphql.Boolean
+					Path       graphql.String
+					Line       *graphql.Int
+					ResolvedBy *struct {
+						Login graphql.String
+					}
+					Comments struct {
+						Nodes []searchCommentNode
+					} `graphql:"comments(first: 50)"`
+				}
+			} `graphql:"reviewThreads(first: 100)"`
+		} `graphql:"... on PullRequest"`
+	}
+	PageInfo struct {
+		HasNextPage graphql.Boolean
+		EndCursor   graphql.String
+	}
+}
+
+// viewerPullRequestsQuery is the SearchViewerPullRequests query
+type viewerPullRequestsQuery struct {
+	Authored        searchSummaryNodes `graphql:"authored: search(query: $authored, type: ISSUE, first: $limit)"`
+	Assigned        searchSummaryNodes `graphql:"assigned: search(query: $assigned, type: ISSUE, first: $limit)"`
+	ReviewRequested searchSummaryNodes `graphql:"reviewRequested: search(query: $reviewRequested, type: ISSUE, first: $limit)"`
+}
+
+// searchPullRequestsQuery is one page of the SearchPullRequests query
+type searchPullRequestsQuery struct {
+	Search searchMetricsNodes `graphql:"search(query: $query, type: ISSUE, first: $limit, after: $after)"`
+}
+
+// searchPageSize is how many pull requests SearchPullRequests fetches per
+// request. Every comment of every thread is fetched, so pages are kept
+// small to stay within GitHub's limit of 500,000 nodes per query.
+const searchPageSize = 10
+
+// SearchViewerPullRequests finds open pull requests the viewer authored,
+// is assigned to, or has been asked to review.
+//
+// scope is a search qualifier such as "repo:owner/name", "org:name" or
+// several repo qualifiers separated by spaces; empty searches everywhere.
+// All three searches are sent in a single request. Threads carry only
+// their first and last comments.
+func (c *Client) SearchViewerPullRequests(ctx context.Context, scope string, limit int) (*ViewerPullRequests, error) {
+	var query viewerPullRequestsQuery
+
+	base := strings.TrimSpace("is:pr is:open archived:false " + scope)
+
+	variables := map[string]interface{}{
+		"authored":        graphQLString(base + " author:@me"),
+		"assigned":        graphQLString(base + " assignee:@me"),
+		"reviewRequested": graphQLString(base + " review-requested:@me"),
+		"limit":           graphQLInt(limit),
+	}
+
+	err := c.queryWithContext(ctx, "SearchViewerPullRequests", &query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("search pull requests: %w", err)
+	}
+
+	return &ViewerPullRequests{
+		Authored:        convertSummaryResults(query.Authored),
+		Assigned:        convertSummaryResults(query.Assigned),
+		ReviewRequested: convertSummaryResults(query.ReviewRequested),
+	}, nil
+}
+
+// SearchPullRequests finds pull requests matching a search query, such as
+// "repo:owner/name created:2025-01-01..2025-01-31". "is:pr" is added
+// automatically. Results are fetched in pages of searchPageSize.
+func (c *Client) SearchPullRequests(ctx context.Context, searchQuery string, limit int) ([]PullRequest, error) {
+	var prs []PullRequest
+	var after *graphql.String
+
+	for len(prs) < limit {
+		var query searchPullRequestsQuery
+
+		variables := map[string]interface{}{
+			"query": graphQLString(strings.TrimSpace("is:pr " + searchQuery)),
+			"limit": graphQLInt(min(searchPageSize, limit-len(prs))),
+			"after": after,
+		}
+
+		err := c.queryWithContext(ctx, "SearchPullRequests", &query, variables)
+		if err != nil {
+			return nil, fmt.Errorf("search pull requests: %w", err)
+		}
+
+		prs = append(prs, convertMetricsResults(query.Search)...)
+
+		if !query.Search.PageInfo.HasNextPage {
+			break
+		}
+		cursor := query.Search.PageInfo.EndCursor
+		after = &cursor
+	}
+
+	return prs, nil
+}
+
+// pullRequest converts the fields shared by search results
+func (n searchPullRequest) pullRequest() PullRequest {
+	return PullRequest{
+		ID:         string(n.ID),
+		Number:     int(n.Number),
+		Title:      string(n.Title),
+		State:      string(n.State),
+		URL:        string(n.URL),
+		Repository: string(n.Repository.NameWithOwner),
+		Author:     User{Login: string(n.Author.Login)},
+		CreatedAt:  parseTime(n.CreatedAt),
+	}
+}
+
+// comment converts a search comment node to our Comment type
+func (n searchCommentNode) comment() Comment {
+	return Comment{
+		ID:        string(n.ID),
+		CreatedAt: parseTime(n.CreatedAt),
+		Author:    User{Login: string(n.Author.Login)},
+	}
+}
+
+func convertSummaryResults(results searchSummaryNodes) []PullRequest {
+	prs := make([]PullRequest, 0, len(results.Nodes))
+	for _, node := range results.Nodes {
+		n := node.PullRequest
+		if n.ID == "" {
+			// Not a pull request
+			continue
+		}
+
+		pr := n.pullRequest()
+		pr.ReviewThreads = make([]Thread, 0, len(n.ReviewThreads.Nodes))
+		for _, t := range n.ReviewThreads.Nodes {
+			thread := Thread{
+				ID:         string(t.ID),
+				IsResolved: bool(t.IsResolved),
+			}
+
+			// The first and last comment, once if there is only one
+			for _, c := range t.FirstComment.Nodes {
+				thread.Comments = append(thread.Comments, c.comment())
+			}
+			for _, c := range t.LastComment.Nodes {
+				if len(thread.Comments) == 0 || thread.Comments[0].ID != string(c.ID) {
+					thread.Comments = append(thread.Comments, c.comment())
+				}
+			}
+
+			pr.ReviewThreads = append(pr.ReviewThreads, thread)
+		}
+		setWaitingOn(pr.ReviewThreads, pr.Author.Login)
+
+		prs = append(prs, pr)
+	}
+	return prs
+}
+
+func convertMetricsResults(results searchMetricsNodes) []PullRequest {
+	prs := make([]PullRequest, 0, len(results.Nodes))
+	for _, node := range results.Nodes {
+		n := node.PullRequest
+		if n.ID == "" {
+			// Not a pull request
+			continue
+		}
+
+		pr := n.pullRequest()
+		pr.ReviewThreads = make([]Thread, 0, len(n.ReviewThreads.Nodes))
+		for _, t := range n.ReviewThreads.Nodes {
+			thread := Thread{
+				ID:         string(t.ID),
+				IsResolved: bool(t.IsResolved),
+				Path:       string(t.Path),
+			}
+			if t.Line != nil {
+				thread.Line = int(*t.Line)
+			}
+			if t.ResolvedBy != nil {
+				thread.ResolvedBy = &User{Login: string(t.ResolvedBy.Login)}
+			}
+
+			thread.Comments = make([]Comment, 0, len(t.Comments.Nodes))
+			for _, c := range t.Comments.Nodes {
+				thread.Comments = append(thread.Comments, c.comment())
+			}
+
+			pr.ReviewThreads = append(pr.ReviewThreads, thread)
+		}
+		setWaitingOn(pr.ReviewThreads, pr.Author.Login)
+
+		prs = append(prs, pr)
+	}
+	return prs
+}
+
+// parseTime parses an RFC 3339 timestamp, returning the zero time if invalid
+func parseTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
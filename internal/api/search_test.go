@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// githubNodeLimit is the most nodes GitHub allows a single query to request
+const githubNodeLimit = 500000
+
+var connectionSizePattern = regexp.MustCompile(`\((?:.*\s)?(?:first|last): (\$?\w+)`)
+
+// nodeBudget works out the most nodes a query type can request, the way
+// GitHub does: each connection's first or last multiplied by those of the
+// connections it is nested in, summed over all connections
+func nodeBudget(t *testing.T, typ reflect.Type, parent int, variables map[string]int) int {
+	t.Helper()
+
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return 0
+	}
+
+	total := 0
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		multiplier := parent
+		if m := connectionSizePattern.FindStringSubmatch(field.Tag.Get("graphql")); m != nil {
+			size, ok := variables[strings.TrimPrefix(m[1], "$")]
+			if !strings.HasPrefix(m[1], "$") {
+				n, err := strconv.Atoi(m[1])
+				if err != nil {
+					t.Fatalf("connection size %q of %s", m[1], field.Name)
+				}
+				size, ok = n, true
+			}
+			if !ok {
+				t.Fatalf("no value for %s in %s", m[1], field.Name)
+			}
+			multiplier = parent * size
+			total += multiplier
+		}
+		total += nodeBudget(t, field.Type, multiplier, variables)
+	}
+	return total
+}
+
+func TestSearchNodeBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     interface{}
+		variables map[string]int
+	}{
+		// dashboard --limit is at most 100 (50 by default)
+		{"SearchViewerPullRequests", viewerPullRequestsQuery{}, map[string]int{"limit": 100}},
+		// stats fetches date ranges a page at a time
+		{"SearchPullRequests", searchPullRequestsQuery{}, map[string]int{"limit": searchPageSize}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := nodeBudget(t, reflect.TypeOf(tt.query), 1, tt.variables)
+			if budget > githubNodeLimit {
+				t.Errorf("%s requests up to %d nodes, over GitHub's limit of %d", tt.name, budget, githubNodeLimit)
+			}
+		})
+	}
+}
+
+func TestSearchPullRequestsPages(t *testing.T) {
+	pages := []string{
+		`{"data":{"search":{"nodes":[
+			{"id":"PR_1","number":1,"title":"One","author":{"login":"me"},"repository":{"nameWithOwner":"o/r"},
+			 "reviewThreads":{"nodes":[{"id":"PRRT_a","isResolved":true,"path":"a.go","comments":{"nodes":[
+			   {"id":"PRRC_1","createdAt":"2025-01-01T00:00:00Z","author":{"login":"rev"}}]}}]}}
+		],"pageInfo":{"hasNextPage":true,"endCursor":"cursor1"}}}}`,
+		`{"data":{"search":{"nodes":[
+			{"id":"PR_2","number":2,"title":"Two","author":{"login":"me"},"repository":{"nameWithOwner":"o/r"},"reviewThreads":{"nodes":[]}},
+			{}
+		],"pageInfo":{"hasNextPage":false,"endCursor":"cursor2"}}}}`,
+	}
+
+	var requests []map[string]interface{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.Unmarshal(body, &req)
+		requests = append(requests, req.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[len(requests)-1]))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:         strings.TrimPrefix(server.URL, "https://"),
+		AuthToken:    "test-token",
+		Transport:    server.Client().Transport,
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+
+	prs, err := client.SearchPullRequests(context.Background(), "repo:o/r", 25)
+	if err != nil {
+		t.Fatalf("SearchPullRequests() error = %v", err)
+	}
+
+	if len(prs) != 2 || prs[0].Number != 1 || prs[1].Number != 2 || prs[0].Repository != "o/r" {
+		t.Fatalf("SearchPullRequests() = %+v", prs)
+	}
+	if len(prs[0].ReviewThreads) != 1 || len(prs[0].ReviewThreads[0].Comments) != 1 || prs[0].ReviewThreads[0].Path != "a.go" {
+		t.Errorf("threads = %+v", prs[0].ReviewThreads)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(requests))
+	}
+	if requests[0]["after"] != nil || requests[0]["limit"] != float64(searchPageSize) {
+		t.Errorf("first page variables = %v", requests[0])
+	}
+	if requests[1]["after"] != "cursor1" {
+		t.Errorf("second page after = %v, want cursor1", requests[1]["after"])
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Snapshot is a saved copy of a pull request conversation.
+//
+// Data holds the ListThreads query response exactly as GitHub returns it,
+// with every page merged in, so a raw API response (such as
+// testdata/pr_with_resolved_threads.json) is also a valid snapshot.
+// Responses with unfetched pages are rejected: diffing them would report
+// threads moving in and out of the first page as added or missing.
+type Snapshot struct {
+	Repository string           `json:"repository,omitempty"`
+	Number     int              `json:"number,omitempty"`
+	CapturedAt time.Time        `json:"capturedAt,omitempty"`
+	Data       pullRequestQuery `json:"data"`
+}
+
+// Snapshot fetches a pull request conversation for saving
+func (c *Client) Snapshot(ctx context.Context, owner, name string, pr int) (*Snapshot, error) {
+	query, err := c.queryPullRequest(ctx, owner, name, pr)
+	if err != nil {
+		return nil, err
+	}
+	if query.truncated() {
+		return nil, fmt.Errorf("snapshot of %s/%s#%d is missing pages of threads or comments", owner, name, pr)
+	}
+
+	return &Snapshot{
+		Repository: owner + "/" + name,
+		Number:     pr,
+		CapturedAt: time.Now().UTC(),
+		Data:       *query,
+	}, nil
+}
+
+// LoadSnapshot reads a snapshot file
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("decode snapshot %s: %w", path, err)
+	}
+	if snapshot.Data.truncated() {
+		return nil, fmt.Errorf("snapshot %s is missing pages of threads or comments", path)
+	}
+
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to path as indented JSON
+func (s *Snapshot) Save(path string) error {
+	if s.Data.truncated() {
+		return fmt.Errorf("refusing to save a snapshot with missing pages of threads or comments")
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// PullRequest converts the snapshot to a pull request.
+//
+// Raw API responses carry no repository, so it is taken from the pull
+// request URL when available.
+func (s *Snapshot) PullRequest() *PullRequest {
+	repository := s.Repository
+	if repository == "" {
+		repository = repositoryFromURL(string(s.Data.Repository.PullRequest.URL))
+	}
+
+	pr := s.Data.pullRequest(repository)
+	if pr.Number == 0 {
+		pr.Number = s.Number
+	}
+	return pr
+}
+
+// repositoryFromURL extracts OWNER/REPO from a pull request URL
+func repositoryFromURL(url string) string {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://"), "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1] + "/" + parts[2]
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSnapshotFromAPIResponse(t *testing.T) {
+	snapshot, err := LoadSnapshot("../../testdata/pr_with_resolved_threads.json")
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+
+	pr := snapshot.PullRequest()
+	if len(pr.ReviewThreads) != 4 {
+		t.Fatalf("got %d threads, want 4", len(pr.ReviewThreads))
+	}
+
+	first := pr.ReviewThreads[0]
+	if first.ID != "PRRT_kwDOQN97u85gQeTN" || first.Path != "test_file.go" || first.Line != 7 {
+		t.Errorf("first thread = %+v", first)
+	}
+	if len(first.Comments) != 2 || first.Comments[0].CreatedAt.IsZero() {
+		t.Errorf("first thread comments = %+v", first.Comments)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	original, err := LoadSnapshot("../../testdata/pr_with_resolved_threads.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original.Repository = "owner/repo"
+	original.Number = 3
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := original.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+
+	want, got := original.PullRequest(), loaded.PullRequest()
+	if got.Repository != "owner/repo" || got.Number != 3 {
+		t.Errorf("got %s#%d, want owner/repo#3", got.Repository, got.Number)
+	}
+	if !reflect.DeepEqual(got.ReviewThreads, want.ReviewThreads) {
+		t.Errorf("threads changed after round trip")
+	}
+}
+
+func TestSnapshotRejectsTruncated(t *testing.T) {
+	dir := t.TempDir()
+
+	truncated, err := LoadSnapshot("../../testdata/pr_with_resolved_threads.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated.Data.Repository.PullRequest.ReviewThreads.Nodes[0].Comments.PageInfo.HasNextPage = true
+	if err := truncated.Save(filepath.Join(dir, "saved.json")); err == nil {
+		t.Error("Save() of a truncated snapshot: error = nil")
+	}
+
+	path := filepath.Join(dir, "raw.json")
+	raw := `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[],"pageInfo":{"hasNextPage":true,"endCursor":"t1"}}}}}}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("LoadSnapshot() of a truncated response: error = nil")
+	}
+}
+
+func TestRepositoryFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/owner/repo/pull/1", "owner/repo"},
+		{"https://github.example.com/team/app/pull/22", "team/app"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := repositoryFromURL(tt.url); got != tt.want {
+			t.Errorf("repositoryFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
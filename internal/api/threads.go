@@ -3,56 +3,187 @@ package api
 import (
 	"context"
 	"fmt"
-	"time"
 
 	graphql "github.com/cli/shurcooL-graphql"
 )
 
 // ListThreads fetches all review threads for a pull request
 func (c *Client) ListThreads(ctx context.Context, owner, name string, pr int) ([]Thread, error) {
-	var query struct {
-		Repository struct {
-			PullRequest struct {
-				ReviewThreads struct {
-					Nodes []struct {
-						ID          graphql.String
-						IsResolved  graphql.Boolean
-						IsCollapsed graphql.Boolean
-						IsOutdated  graphql.Boolean
-						Path        graphql.String
-						Line        *graphql.Int
-						StartLine   *graphql.Int
-						DiffSide    graphql.String
-						SubjectType graphql.String
-						ResolvedBy  *struct {
-							Login graphql.String
-						}
-						ViewerCanResolve   graphql.Boolean
-						ViewerCanUnresolve graphql.Boolean
-						ViewerCanReply     graphql.Boolean
-						Comments           struct {
-							TotalCount graphql.Int
-							Nodes      []struct {
-								ID        graphql.String
-								Body      graphql.String
-								CreatedAt string
-								Author    struct {
-									Login graphql.String
-								}
-								ReactionGroups []struct {
-									Content graphql.String
-									Users   struct {
-										TotalCount graphql.Int
-									} `graphql:"users(first: 1)"`
-									ViewerHasReacted graphql.Boolean
-								}
-							}
-						} `graphql:"comments(first: 50)"`
-					}
-				} `graphql:"reviewThreads(first: 100)"`
-			} `graphql:"pullRequest(number: $number)"`
-		} `graphql:"repository(owner: $owner, name: $name)"`
+	pullRequest, err := c.GetPullRequest(ctx, owner, name, pr)
+	if err != nil {
+		return nil, err
+	}
+	return pullRequest.ReviewThreads, nil
+}
+
+// pullRequestQuery is the shape of the ListThreads query response.
+// The json tags match the GraphQL response so snapshots can be saved and
+// loaded without a separate file format.
+type pullRequestQuery struct {
+	Repository struct {
+		PullRequest struct {
+			ID         graphql.String `json:"id"`
+			Number     graphql.Int    `json:"number"`
+			Title      graphql.String `json:"title"`
+			State      graphql.String `json:"state"`
+			URL        graphql.String `json:"url"`
+			Body       graphql.String `json:"body"`
+			CreatedAt  string         `json:"createdAt"`
+			HeadRefOid graphql.String `json:"headRefOid"`
+			Author     struct {
+				Login graphql.String `json:"login"`
+			} `json:"author"`
+			Comments      issueCommentConnection `graphql:"comments(first: 100)" json:"comments"`
+			ReviewThreads reviewThreadConnection `graphql:"reviewThreads(first: 100)" json:"reviewThreads"`
+		} `graphql:"pullRequest(number: $number)" json:"pullRequest"`
+	} `graphql:"repository(owner: $owner, name: $name)" json:"repository"`
+}
+
+// issueCommentConnection is a page of top-level PR comments
+type issueCommentConnection struct {
+	Nodes []struct {
+		ID                graphql.String  `json:"id"`
+		DatabaseID        graphql.Int     `json:"databaseId"`
+		URL               graphql.String  `json:"url"`
+		Body              graphql.String  `json:"body"`
+		CreatedAt         string          `json:"createdAt"`
+		IsMinimized       graphql.Boolean `json:"isMinimized"`
+		MinimizedReason   graphql.String  `json:"minimizedReason"`
+		ViewerCanReact    graphql.Boolean `json:"viewerCanReact"`
+		ViewerCanMinimize graphql.Boolean `json:"viewerCanMinimize"`
+		Author            struct {
+			Login graphql.String `json:"login"`
+		} `json:"author"`
+		ReactionGroups []reactionGroupNode `json:"reactionGroups"`
+	} `json:"nodes"`
+	PageInfo pageInfo `json:"pageInfo"`
+}
+
+// reviewThreadConnection is a page of review threads
+type reviewThreadConnection struct {
+	Nodes []struct {
+		ID                graphql.String  `json:"id"`
+		IsResolved        graphql.Boolean `json:"isResolved"`
+		IsCollapsed       graphql.Boolean `json:"isCollapsed"`
+		IsOutdated        graphql.Boolean `json:"isOutdated"`
+		Path              graphql.String  `json:"path"`
+		Line              *graphql.Int    `json:"line"`
+		StartLine         *graphql.Int    `json:"startLine"`
+		OriginalLine      *graphql.Int    `json:"originalLine"`
+		OriginalStartLine *graphql.Int    `json:"originalStartLine"`
+		DiffSide          graphql.String  `json:"diffSide"`
+		SubjectType       graphql.String  `json:"subjectType"`
+		ResolvedBy        *struct {
+			Login graphql.String `json:"login"`
+		} `json:"resolvedBy"`
+		ViewerCanResolve   graphql.Boolean         `json:"viewerCanResolve"`
+		ViewerCanUnresolve graphql.Boolean         `json:"viewerCanUnresolve"`
+		ViewerCanReply     graphql.Boolean         `json:"viewerCanReply"`
+		Comments           reviewCommentConnection `graphql:"comments(first: 50)" json:"comments"`
+	} `json:"nodes"`
+	PageInfo pageInfo `json:"pageInfo"`
+}
+
+// reviewCommentConnection is a page of comments in a review thread
+type reviewCommentConnection struct {
+	TotalCount graphql.Int `json:"totalCount"`
+	Nodes      []struct {
+		ID                graphql.String  `json:"id"`
+		DatabaseID        graphql.Int     `json:"databaseId"`
+		URL               graphql.String  `json:"url"`
+		Body              graphql.String  `json:"body"`
+		CreatedAt         string          `json:"createdAt"`
+		DiffHunk          graphql.String  `json:"diffHunk"`
+		IsMinimized       graphql.Boolean `json:"isMinimized"`
+		MinimizedReason   graphql.String  `json:"minimizedReason"`
+		ViewerCanReact    graphql.Boolean `json:"viewerCanReact"`
+		ViewerCanMinimize graphql.Boolean `json:"viewerCanMinimize"`
+		Author            struct {
+			Login graphql.String `json:"login"`
+		} `json:"author"`
+		OriginalCommit *struct {
+			Oid graphql.String `json:"oid"`
+		} `json:"originalCommit"`
+		ReactionGroups []reactionGroupNode `json:"reactionGroups"`
+	} `json:"nodes"`
+	PageInfo pageInfo `json:"pageInfo"`
+}
+
+// truncated reports whether any connection in the response has pages
+// that were not fetched
+func (query *pullRequestQuery) truncated() bool {
+	pr := query.Repository.PullRequest
+	if pr.Comments.PageInfo.HasNextPage || pr.ReviewThreads.PageInfo.HasNextPage {
+		return true
+	}
+	for _, t := range pr.ReviewThreads.Nodes {
+		if t.Comments.PageInfo.HasNextPage {
+			return true
+		}
+	}
+	return false
+}
+
+// reactionGroupNode is a reaction group with the first users who reacted.
+// Listings fetch up to 20 users per group to stay within GraphQL node
+// limits; GetReactions fetches more for a single comment or thread.
+type reactionGroupNode struct {
+	Content          graphql.String    `json:"content"`
+	CreatedAt        *string           `json:"createdAt"`
+	Users            reactionUsersNode `graphql:"users(first: 20)" json:"users"`
+	ViewerHasReacted graphql.Boolean   `json:"viewerHasReacted"`
+}
+
+// reactionUsersNode is the users who reacted with one reaction
+type reactionUsersNode struct {
+	TotalCount graphql.Int `json:"totalCount"`
+	Nodes      []struct {
+		Login graphql.String `json:"login"`
+	} `json:"nodes"`
+}
+
+// reactionGroups converts reaction group nodes to our types, dropping
+// empty groups
+func reactionGroups(nodes []reactionGroupNode) []ReactionGroup {
+	var groups []ReactionGroup
+	for _, rg := range nodes {
+		if rg.Users.TotalCount == 0 {
+			continue
+		}
+		group := ReactionGroup{
+			Content:          string(rg.Content),
+			ViewerHasReacted: bool(rg.ViewerHasReacted),
+			Users: ReactionUsers{
+				TotalCount: int(rg.Users.TotalCount),
+			},
+		}
+		if rg.CreatedAt != nil {
+			if t := parseTime(*rg.CreatedAt); !t.IsZero() {
+				group.CreatedAt = &t
+			}
+		}
+		for _, u := range rg.Users.Nodes {
+			group.Users.Nodes = append(group.Users.Nodes, User{Login: string(u.Login)})
+		}
+		groups = append(groups, group)
 	}
+	return groups
+}
+
+// GetPullRequest fetches a pull request with all of its review threads
+func (c *Client) GetPullRequest(ctx context.Context, owner, name string, pr int) (*PullRequest, error) {
+	query, err := c.queryPullRequest(ctx, owner, name, pr)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest := query.pullRequest(owner + "/" + name)
+	c.seen.rememberPullRequest(pullRequest, true)
+	return pullRequest, nil
+}
+
+// queryPullRequest runs the ListThreads query
+func (c *Client) queryPullRequest(ctx context.Context, owner, name string, pr int) (*pullRequestQuery, error) {
+	var query pullRequestQuery
 
 	variables := map[string]interface{}{
 		"owner":  graphQLString(owner),
@@ -65,7 +196,136 @@ func (c *Client) ListThreads(ctx context.Context, owner, name string, pr int) ([
 		return nil, fmt.Errorf("query threads: %w", err)
 	}
 
-	// Convert to our types
+	if err := c.fetchRemaining(ctx, owner, name, pr, &query); err != nil {
+		return nil, err
+	}
+
+	return &query, nil
+}
+
+// fetchRemaining pages through the PR comments, review threads and thread
+// comments the ListThreads query cut off, appending them to query so it
+// holds the whole conversation
+func (c *Client) fetchRemaining(ctx context.Context, owner, name string, pr int, query *pullRequestQuery) error {
+	pullRequest := &query.Repository.PullRequest
+
+	for after := pullRequest.Comments.PageInfo.next(); after != nil; {
+		var page struct {
+			Repository struct {
+				PullRequest struct {
+					Comments issueCommentConnection `graphql:"comments(first: 100, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+
+		variables := map[string]interface{}{
+			"owner":  graphQLString(owner),
+			"name":   graphQLString(name),
+			"number": graphQLInt(pr),
+			"after":  after,
+		}
+
+		if err := c.queryWithContext(ctx, "ListMoreComments", &page, variables); err != nil {
+			return fmt.Errorf("query comments: %w", err)
+		}
+
+		comments := page.Repository.PullRequest.Comments
+		pullRequest.Comments.Nodes = append(pullRequest.Comments.Nodes, comments.Nodes...)
+		after = comments.PageInfo.next()
+	}
+	pullRequest.Comments.PageInfo = pageInfo{}
+
+	for after := pullRequest.ReviewThreads.PageInfo.next(); after != nil; {
+		var page struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads reviewThreadConnection `graphql:"reviewThreads(first: 100, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+
+		variables := map[string]interface{}{
+			"owner":  graphQLString(owner),
+			"name":   graphQLString(name),
+			"number": graphQLInt(pr),
+			"after":  after,
+		}
+
+		if err := c.queryWithContext(ctx, "ListMoreThreads", &page, variables); err != nil {
+			return fmt.Errorf("query threads: %w", err)
+		}
+
+		threads := page.Repository.PullRequest.ReviewThreads
+		pullRequest.ReviewThreads.Nodes = append(pullRequest.ReviewThreads.Nodes, threads.Nodes...)
+		after = threads.PageInfo.next()
+	}
+	pullRequest.ReviewThreads.PageInfo = pageInfo{}
+
+	for i := range pullRequest.ReviewThreads.Nodes {
+		thread := &pullRequest.ReviewThreads.Nodes[i]
+		for after := thread.Comments.PageInfo.next(); after != nil; {
+			var page struct {
+				Node struct {
+					Thread struct {
+						Comments reviewCommentConnection `graphql:"comments(first: 50, after: $after)"`
+					} `graphql:"... on PullRequestReviewThread"`
+				} `graphql:"node(id: $id)"`
+			}
+
+			variables := map[string]interface{}{
+				"id":    graphQLID(string(thread.ID)),
+				"after": after,
+			}
+
+			if err := c.queryWithContext(ctx, "ListMoreThreadReplies", &page, variables); err != nil {
+				return fmt.Errorf("query comments of thread %s: %w", thread.ID, err)
+			}
+
+			comments := page.Node.Thread.Comments
+			thread.Comments.Nodes = append(thread.Comments.Nodes, comments.Nodes...)
+			after = comments.PageInfo.next()
+		}
+		thread.Comments.PageInfo = pageInfo{}
+	}
+
+	return nil
+}
+
+// pullRequest converts the query response to our types
+func (query *pullRequestQuery) pullRequest(repository string) *PullRequest {
+	result := &PullRequest{
+		ID:         string(query.Repository.PullRequest.ID),
+		Number:     int(query.Repository.PullRequest.Number),
+		Title:      string(query.Repository.PullRequest.Title),
+		State:      string(query.Repository.PullRequest.State),
+		URL:        string(query.Repository.PullRequest.URL),
+		Body:       string(query.Repository.PullRequest.Body),
+		Repository: repository,
+		CreatedAt:  parseTime(query.Repository.PullRequest.CreatedAt),
+		HeadRefOid: string(query.Repository.PullRequest.HeadRefOid),
+		Author:     User{Login: string(query.Repository.PullRequest.Author.Login)},
+	}
+
+	result.Comments = make([]Comment, 0, len(query.Repository.PullRequest.Comments.Nodes))
+	for _, c := range query.Repository.PullRequest.Comments.Nodes {
+		comment := Comment{
+			ID:                string(c.ID),
+			DatabaseID:        int(c.DatabaseID),
+			URL:               string(c.URL),
+			Body:              string(c.Body),
+			CreatedAt:         parseTime(c.CreatedAt),
+			IsMinimized:       bool(c.IsMinimized),
+			MinimizedReason:   string(c.MinimizedReason),
+			ViewerCanReact:    bool(c.ViewerCanReact),
+			ViewerCanMinimize: bool(c.ViewerCanMinimize),
+			Author:            User{Login: string(c.Author.Login)},
+		}
+
+		comment.ReactionGroups = reactionGroups(c.ReactionGroups)
+
+		result.Comments = append(result.Comments, comment)
+	}
+
 	threads := make([]Thread, 0, len(query.Repository.PullRequest.ReviewThreads.Nodes))
 	for _, node := range query.Repository.PullRequest.ReviewThreads.Nodes {
 		thread := Thread{
@@ -87,6 +347,12 @@ func (c *Client) ListThreads(ctx context.Context, owner, name string, pr int) ([
 		if node.StartLine != nil {
 			thread.StartLine = int(*node.StartLine)
 		}
+		if node.OriginalLine != nil {
+			thread.OriginalLine = int(*node.OriginalLine)
+		}
+		if node.OriginalStartLine != nil {
+			thread.OriginalStartLine = int(*node.OriginalStartLine)
+		}
 
 		if node.ResolvedBy != nil {
 			thread.ResolvedBy = &User{
@@ -98,33 +364,29 @@ func (c *Client) ListThreads(ctx context.Context, owner, name string, pr int) ([
 		thread.Comments = make([]Comment, 0, len(node.Comments.Nodes))
 		for _, c := range node.Comments.Nodes {
 			comment := Comment{
-				ID:   string(c.ID),
-				Body: string(c.Body),
+				ID:                string(c.ID),
+				DatabaseID:        int(c.DatabaseID),
+				URL:               string(c.URL),
+				Body:              string(c.Body),
+				Path:              string(node.Path),
+				DiffHunk:          string(c.DiffHunk),
+				IsMinimized:       bool(c.IsMinimized),
+				MinimizedReason:   string(c.MinimizedReason),
+				ViewerCanReact:    bool(c.ViewerCanReact),
+				ViewerCanMinimize: bool(c.ViewerCanMinimize),
 				Author: User{
 					Login: string(c.Author.Login),
 				},
 			}
 
-			// Parse CreatedAt
-			if c.CreatedAt != "" {
-				if t, err := time.Parse(time.RFC3339, c.CreatedAt); err == nil {
-					comment.CreatedAt = t
-				}
+			if c.OriginalCommit != nil {
+				comment.OriginalCommit = string(c.OriginalCommit.Oid)
 			}
 
+			comment.CreatedAt = parseTime(c.CreatedAt)
+
 			// Convert reaction groups
-			comment.ReactionGroups = make([]ReactionGroup, 0, len(c.ReactionGroups))
-			for _, rg := range c.ReactionGroups {
-				if rg.Users.TotalCount > 0 {
-					comment.ReactionGroups = append(comment.ReactionGroups, ReactionGroup{
-						Content:          string(rg.Content),
-						ViewerHasReacted: bool(rg.ViewerHasReacted),
-						Users: ReactionUsers{
-							TotalCount: int(rg.Users.TotalCount),
-						},
-					})
-				}
-			}
+			comment.ReactionGroups = reactionGroups(c.ReactionGroups)
 
 			thread.Comments = append(thread.Comments, comment)
 		}
@@ -132,5 +394,7 @@ func (c *Client) ListThreads(ctx context.Context, owner, name string, pr int) ([
 		threads = append(threads, thread)
 	}
 
-	return threads, nil
+	setWaitingOn(threads, result.Author.Login)
+	result.ReviewThreads = threads
+	return result
 }
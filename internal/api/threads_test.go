@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"strings"
@@ -97,6 +98,66 @@ func TestListThreads(t *testing.T) {
 	}
 }
 
+func TestGetPullRequestPages(t *testing.T) {
+	client := newTestClient(t,
+		`{"data":{"repository":{"pullRequest":{"id":"PR_1","number":1,
+			"comments":{"nodes":[{"id":"IC_1"}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}},
+			"reviewThreads":{"nodes":[{"id":"PRRT_1","path":"main.go",
+				"comments":{"nodes":[{"id":"PRRC_1"}],"pageInfo":{"hasNextPage":true,"endCursor":"r1"}}}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"t1"}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"comments":{"nodes":[{"id":"IC_2"}],"pageInfo":{"hasNextPage":false}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[{"id":"PRRT_2","path":"util.go",
+			"comments":{"nodes":[{"id":"PRRC_3"}],"pageInfo":{"hasNextPage":false}}}],"pageInfo":{"hasNextPage":false}}}}}}`,
+		`{"data":{"node":{"comments":{"nodes":[{"id":"PRRC_2"}],"pageInfo":{"hasNextPage":false}}}}}`,
+	)
+
+	pr, err := client.GetPullRequest(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+
+	var ids []string
+	for _, c := range pr.Comments {
+		ids = append(ids, c.ID)
+	}
+	for _, thread := range pr.ReviewThreads {
+		ids = append(ids, thread.ID)
+		for _, c := range thread.Comments {
+			ids = append(ids, c.ID+"@"+c.Path)
+		}
+	}
+	want := "IC_1,IC_2,PRRT_1,PRRC_1@main.go,PRRC_2@main.go,PRRT_2,PRRC_3@util.go"
+	if got := strings.Join(ids, ","); got != want {
+		t.Errorf("GetPullRequest() = %s, want %s", got, want)
+	}
+}
+
+func TestGetThreadPages(t *testing.T) {
+	client := newTestClient(t,
+		`{"data":{"node":{"id":"PRRT_1","path":"main.go","pullRequest":{"number":1,"repository":{"nameWithOwner":"o/r"}},
+			"comments":{"nodes":[{"id":"PRRC_1","reactionGroups":[]}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}`,
+		`{"data":{"node":{"comments":{"nodes":[{"id":"PRRC_2","viewerCanReact":true,"reactionGroups":[]}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"c2"}}}}}`,
+		`{"data":{"node":{"comments":{"nodes":[{"id":"PRRC_3","reactionGroups":[]}],"pageInfo":{"hasNextPage":false}}}}}`,
+	)
+
+	thread, err := client.GetThread(context.Background(), "PRRT_1")
+	if err != nil {
+		t.Fatalf("GetThread() error = %v", err)
+	}
+
+	var ids []string
+	for _, c := range thread.Comments {
+		ids = append(ids, c.ID+"@"+c.Path)
+	}
+	if got, want := strings.Join(ids, ","), "PRRC_1@main.go,PRRC_2@main.go,PRRC_3@main.go"; got != want {
+		t.Errorf("GetThread() comments = %s, want %s", got, want)
+	}
+	if !thread.Comments[1].ViewerCanReact {
+		t.Errorf("GetThread() comments = %+v, want full comments on later pages", thread.Comments)
+	}
+}
+
 func TestParseThreadID(t *testing.T) {
 	tests := []struct {
 		name    string
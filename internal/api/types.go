@@ -11,6 +11,8 @@ type Thread struct {
 	Path               string
 	Line               int
 	StartLine          int
+	OriginalLine       int
+	OriginalStartLine  int
 	DiffSide           string
 	SubjectType        string
 	ResolvedBy         *User
@@ -18,16 +20,30 @@ type Thread struct {
 	ViewerCanResolve   bool
 	ViewerCanUnresolve bool
 	ViewerCanReply     bool
+
+	// WaitingOn is computed: WaitingOnAuthor, WaitingOnReviewer, or empty
+	// for resolved threads. Acknowledged is set when the party waited on
+	// reacted 👀 to the last comment.
+	WaitingOn    string
+	Acknowledged bool
 }
 
+// Values for Thread.WaitingOn
+const (
+	WaitingOnAuthor   = "author"
+	WaitingOnReviewer = "reviewer"
+)
+
 // Comment represents a review comment or issue comment
 type Comment struct {
 	ID                string
 	DatabaseID        int
+	URL               string
 	Body              string
 	Path              string
 	Position          int
 	DiffHunk          string
+	OriginalCommit    string
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	Author            User
@@ -60,6 +76,14 @@ type ReactionGroup struct {
 	ViewerHasReacted bool
 }
 
+// Reaction is a single reaction, as returned by AddReaction and
+// RemoveReaction
+type Reaction struct {
+	ID        string
+	Content   string
+	SubjectID string
+}
+
 // ReactionUsers represents users who reacted
 type ReactionUsers struct {
 	TotalCount int
@@ -70,6 +94,7 @@ type ReactionUsers struct {
 type Issue struct {
 	ID       string
 	Number   int
+	URL      string
 	Title    string
 	State    string
 	Body     string
@@ -82,5 +107,19 @@ type PullRequest struct {
 	Number        int
 	Title         string
 	State         string
+	URL           string
+	Body          string
+	Repository    string
+	Author        User
+	CreatedAt     time.Time
+	HeadRefOid    string
+	Comments      []Comment
 	ReviewThreads []Thread
 }
+
+// ViewerPullRequests groups open pull requests by the viewer's involvement
+type ViewerPullRequests struct {
+	Authored        []PullRequest
+	Assigned        []PullRequest
+	ReviewRequested []PullRequest
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// CurrentUser returns the login of the authenticated user
+func (c *Client) CurrentUser(ctx context.Context) (string, error) {
+	var query struct {
+		Viewer struct {
+			Login graphql.String
+		}
+	}
+
+	err := c.queryWithContext(ctx, "CurrentUser", &query, nil)
+	if err != nil {
+		return "", fmt.Errorf("query viewer: %w", err)
+	}
+
+	return string(query.Viewer.Login), nil
+}
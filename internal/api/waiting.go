@@ -0,0 +1,42 @@
+package api
+
+// setWaitingOn computes WaitingOn and Acknowledged for every thread
+func setWaitingOn(threads []Thread, prAuthor string) {
+	for i := range threads {
+		threads[i].WaitingOn, threads[i].Acknowledged = waitingOn(threads[i], prAuthor)
+	}
+}
+
+// waitingOn works out whose turn it is in a thread.
+//
+// Resolved threads wait on nobody. Otherwise the thread waits on whoever
+// did not write the last comment: the reviewer if the PR author spoke
+// last, the author if anyone else did. An 👀 reaction on the last comment
+// from the side being waited on marks it as acknowledged: they have seen
+// it but not replied yet.
+func waitingOn(t Thread, prAuthor string) (string, bool) {
+	if t.IsResolved || len(t.Comments) == 0 {
+		return "", false
+	}
+
+	last := t.Comments[len(t.Comments)-1]
+
+	waiting := WaitingOnAuthor
+	if prAuthor != "" && last.Author.Login == prAuthor {
+		waiting = WaitingOnReviewer
+	}
+
+	for _, rg := range last.ReactionGroups {
+		if rg.Content != "EYES" {
+			continue
+		}
+		for _, u := range rg.Users.Nodes {
+			isAuthor := prAuthor != "" && u.Login == prAuthor
+			if isAuthor == (waiting == WaitingOnAuthor) {
+				return waiting, true
+			}
+		}
+	}
+
+	return waiting, false
+}
@@ -0,0 +1,43 @@
+package api
+
+import "testing"
+
+func TestWaitingOn(t *testing.T) {
+	reviewerComment := Comment{Author: User{Login: "reviewer"}}
+	authorComment := Comment{Author: User{Login: "author"}}
+	seenBy := func(author string, logins ...string) Comment {
+		users := ReactionUsers{TotalCount: len(logins)}
+		for _, login := range logins {
+			users.Nodes = append(users.Nodes, User{Login: login})
+		}
+		return Comment{
+			Author:         User{Login: author},
+			ReactionGroups: []ReactionGroup{{Content: "EYES", Users: users}},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		thread           Thread
+		wantWaitingOn    string
+		wantAcknowledged bool
+	}{
+		{"resolved", Thread{IsResolved: true, Comments: []Comment{reviewerComment}}, "", false},
+		{"no comments", Thread{}, "", false},
+		{"reviewer spoke last", Thread{Comments: []Comment{authorComment, reviewerComment}}, WaitingOnAuthor, false},
+		{"author replied", Thread{Comments: []Comment{reviewerComment, authorComment}}, WaitingOnReviewer, false},
+		{"acknowledged by author", Thread{Comments: []Comment{seenBy("reviewer", "author")}}, WaitingOnAuthor, true},
+		{"seen by another reviewer", Thread{Comments: []Comment{seenBy("reviewer", "reviewer", "other")}}, WaitingOnAuthor, false},
+		{"acknowledged by reviewer", Thread{Comments: []Comment{reviewerComment, seenBy("author", "reviewer")}}, WaitingOnReviewer, true},
+		{"seen by author only", Thread{Comments: []Comment{reviewerComment, seenBy("author", "author")}}, WaitingOnReviewer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ack := waitingOn(tt.thread, "author")
+			if got != tt.wantWaitingOn || ack != tt.wantAcknowledged {
+				t.Errorf("waitingOn() = %q, %v; want %q, %v", got, ack, tt.wantWaitingOn, tt.wantAcknowledged)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir returns the gh-talk cache directory.
+//
+// GH_TALK_CACHE_DIR takes precedence, otherwise the user cache directory
+// is used (~/.cache/gh-talk on Linux).
+func Dir() (string, error) {
+	if dir := os.Getenv("GH_TALK_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("determine cache directory: %w", err)
+	}
+
+	return filepath.Join(base, "gh-talk"), nil
+}
+
+// Load reads the value stored under key into v.
+// It returns false if nothing is stored under key.
+func Load(key string, v interface{}) (bool, error) {
+	path, err := filePath(key)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read cache %s: %w", key, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("decode cache %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Save stores v under key, replacing any previous value
+func Save(key string, v interface{}) error {
+	path, err := filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode cache %s: %w", key, err)
+	}
+
+	// Write to a temporary file first so readers never see partial data
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write cache %s: %w", key, err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Key builds a cache key from its parts, e.g. Key("watch", "owner", "repo", "1")
+func Key(parts ...string) string {
+	return strings.Join(parts, "-")
+}
+
+func filePath(key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	// Keep keys to a single safe file name
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	return filepath.Join(dir, safe+".json"), nil
+}
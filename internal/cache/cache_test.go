@@ -6,3 +6,42 @@ func TestPackageImport(t *testing.T) {
 	// Verify package compiles
 	t.Log("cache package imports successfully")
 }
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("GH_TALK_CACHE_DIR", t.TempDir())
+
+	type state struct {
+		Seen []string
+	}
+
+	var got state
+	found, err := Load(Key("watch", "owner", "repo", "1"), &got)
+	if err != nil || found {
+		t.Fatalf("Load() on empty cache = %v, %v; want false, nil", found, err)
+	}
+
+	want := state{Seen: []string{"PRRC_a", "PRRC_b"}}
+	if err := Save(Key("watch", "owner", "repo", "1"), want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	found, err = Load(Key("watch", "owner", "repo", "1"), &got)
+	if err != nil || !found {
+		t.Fatalf("Load() = %v, %v; want true, nil", found, err)
+	}
+	if len(got.Seen) != 2 || got.Seen[1] != "PRRC_b" {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestKeyIsSafeFileName(t *testing.T) {
+	t.Setenv("GH_TALK_CACHE_DIR", "/tmp/cache")
+
+	path, err := filePath(Key("watch", "../owner", "repo/x", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/tmp/cache/watch-.._owner-repo_x-1.json" {
+		t.Errorf("filePath() = %s", path)
+	}
+}
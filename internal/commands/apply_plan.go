@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+)
+
+var applyPlanCmd = &cobra.Command{
+	Use:   "apply-plan <plan.yaml>",
+	Short: "Apply a reviewed plan of conversation operations",
+	Long: `Apply a YAML plan of replies, reactions, resolutions and hides to a PR.
+
+Every operation is validated first, including your permissions on each
+thread and comment. The plan is then shown as a diff and, after
+confirmation, executed in order. Results are written to a JSON file.
+
+Plan format:
+  repo: owner/repo      # optional, defaults to --repo or current repo
+  pr: 123               # optional, defaults to --pr or current branch
+  operations:
+    - op: reply
+      thread: PRRT_...
+      body: Fixed in the latest commit
+    - op: react
+      comment: PRRC_...
+      reaction: 👍
+    - op: resolve
+      thread: PRRT_...
+    - op: hide
+      comment: IC_...
+      reason: outdated
+
+Operations: reply, react, unreact, resolve, unresolve, hide, unhide
+
+Examples:
+  # Review a plan without changing anything
+  gh talk apply-plan plan.yaml --dry-run
+
+  # Apply after confirmation, writing results to plan.result.json
+  gh talk apply-plan plan.yaml
+
+  # Apply without confirmation, continuing past failures
+  gh talk apply-plan plan.yaml --yes --continue-on-error`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApplyPlan,
+}
+
+func init() {
+	applyPlanCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	applyPlanCmd.Flags().String("result", "", "Result file (default: <plan>.result.json)")
+	applyPlanCmd.Flags().Bool("continue-on-error", false, "Keep going after a failed operation")
+}
+
+// planResult is the result file written after applying a plan
+type planResult struct {
+	Plan       string       `json:"plan"`
+	Repository string       `json:"repository"`
+	Number     int          `json:"number"`
+	AppliedAt  time.Time    `json:"appliedAt"`
+	Results    []ops.Result `json:"results"`
+}
+
+func runApplyPlan(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	planPath := args[0]
+
+	plan, err := ops.LoadPlan(planPath)
+	if err != nil {
+		return err
+	}
+
+	for i := range plan.Operations {
+		if err := normalizeOperation(&plan.Operations[i]); err != nil {
+			return fmt.Errorf("operation %d (%s): %w", i+1, plan.Operations[i], err)
+		}
+	}
+
+	owner, name, prNum, err := planTarget(cmd, plan)
+	if err != nil {
+		return err
+	}
+
+	client, err := newMutationClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	pr, err := client.GetPullRequest(ctx, owner, name, prNum)
+	if err != nil {
+		return err
+	}
+
+	if dryRunFormat(cmd) != "" {
+		return runDryRun(ctx, cmd, client, ops.NewIndex(pr), plan.Operations)
+	}
+
+	changes, err := ops.NewIndex(pr).CheckAll(plan.Operations)
+	if err != nil {
+		return fmt.Errorf("plan is invalid:\n%w", err)
+	}
+
+	fmt.Fprintf(factory.IOStreams.Out, "Plan: %d operations on %s/%s#%d\n\n", len(changes), owner, name, prNum)
+	printPlanChanges(factory.IOStreams.Out, changes)
+
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	if !skipConfirm {
+		p := factory.Prompter()
+		confirmed, err := p.Confirm(fmt.Sprintf("Apply %d operations?", len(changes)), false)
+		if err != nil || !confirmed {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	executor := &ops.Executor{Client: client, ContinueOnError: continueOnError}
+	results := executor.Execute(ctx, changes)
+
+	resultPath, _ := cmd.Flags().GetString("result")
+	if resultPath == "" {
+		resultPath = strings.TrimSuffix(strings.TrimSuffix(planPath, ".yaml"), ".yml") + ".result.json"
+	}
+
+	report := planResult{
+		Plan:       planPath,
+		Repository: owner + "/" + name,
+		Number:     prNum,
+		AppliedAt:  time.Now().UTC(),
+		Results:    results,
+	}
+	if err := writeJSONFile(resultPath, report); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(factory.IOStreams.Out)
+	failed := 0
+	for _, r := range results {
+		switch r.Status {
+		case ops.StatusOK:
+			fmt.Fprintf(factory.IOStreams.Out, "✓ %s\n", r.Operation)
+		case ops.StatusSkipped:
+			if r.Error == "" {
+				fmt.Fprintf(factory.IOStreams.Out, "- %s (no change needed)\n", r.Operation)
+			} else {
+				fmt.Fprintf(factory.IOStreams.Out, "- %s (%s)\n", r.Operation, r.Error)
+			}
+		default:
+			failed++
+			fmt.Fprintf(factory.IOStreams.Out, "✗ %s: %s\n", r.Operation, r.Error)
+		}
+	}
+	fmt.Fprintf(factory.IOStreams.Out, "\nResults written to %s\n", resultPath)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d operations failed", failed, len(results))
+	}
+	return nil
+}
+
+// normalizeOperation converts user-friendly values, such as emoji, to the
+// values the API expects
+func normalizeOperation(op *ops.Operation) error {
+	if op.Reaction != "" {
+		content, err := parseEmoji(op.Reaction)
+		if err != nil {
+			return err
+		}
+		op.Reaction = content
+	}
+	return nil
+}
+
+// planTarget returns the PR a plan applies to. Flags take precedence over
+// the plan, which takes precedence over the current repository and branch.
+func planTarget(cmd *cobra.Command, plan *ops.Plan) (owner, name string, pr int, err error) {
+	repoFlag, _ := cmd.Flags().GetString("repo")
+	if repoFlag == "" && plan.Repo != "" {
+		repo, err := repository.Parse(plan.Repo)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("invalid repository in plan: %s", plan.Repo)
+		}
+		owner, name = repo.Owner, repo.Name
+	} else {
+		owner, name, err = getRepository(cmd)
+		if err != nil {
+			return "", "", 0, err
+		}
+	}
+
+	prFlag, _ := cmd.Flags().GetInt("pr")
+	if prFlag == 0 && plan.PR != 0 {
+		return owner, name, plan.PR, nil
+	}
+
+	pr, err = getCurrentPR(cmd)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return owner, name, pr, nil
+}
+
+// printPlanChanges prints changes as a diff: + adds, ~ changes state,
+// = already in the requested state
+func printPlanChanges(w io.Writer, changes []ops.Change) {
+	for _, c := range changes {
+		marker := "~"
+		detail := fmt.Sprintf("%s → %s", c.Before, c.After)
+		switch {
+		case c.NoOp:
+			marker = "="
+			detail = "already " + c.After
+		case c.Op == ops.Reply:
+			marker = "+"
+			detail = fmt.Sprintf("%q", truncate(firstLine(c.Body), 60))
+		}
+
+		fmt.Fprintf(w, "  %s %-9s %-24s %-20s %s\n", marker, c.Op, c.Target(), c.Location, detail)
+	}
+}
+
+// writeJSONFile writes v to path as indented JSON
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+)
+
+func TestPrintPlanChanges(t *testing.T) {
+	changes := []ops.Change{
+		{Operation: ops.Operation{Op: ops.Reply, Thread: "PRRT_a", Body: "Fixed\nthanks"}, Location: "main.go:7"},
+		{Operation: ops.Operation{Op: ops.Resolve, Thread: "PRRT_a"}, Location: "main.go:7", Before: "open", After: "resolved"},
+		{Operation: ops.Operation{Op: ops.Resolve, Thread: "PRRT_b"}, Before: "resolved", After: "resolved", NoOp: true},
+	}
+
+	var buf bytes.Buffer
+	printPlanChanges(&buf, changes)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	wants := []string{
+		`+ reply     PRRT_a`,
+		`~ resolve   PRRT_a`,
+		`= resolve   PRRT_b`,
+	}
+	for i, want := range wants {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+	if !strings.HasSuffix(lines[0], `"Fixed"`) || !strings.HasSuffix(lines[1], "open → resolved") || !strings.HasSuffix(lines[2], "already resolved") {
+		t.Errorf("unexpected details:\n%s", buf.String())
+	}
+}
+
+func TestNormalizeOperation(t *testing.T) {
+	op := ops.Operation{Op: ops.React, Comment: "PRRC_a", Reaction: "👍"}
+	if err := normalizeOperation(&op); err != nil {
+		t.Fatal(err)
+	}
+	if op.Reaction != "THUMBS_UP" {
+		t.Errorf("Reaction = %s, want THUMBS_UP", op.Reaction)
+	}
+
+	bad := ops.Operation{Op: ops.React, Comment: "PRRC_a", Reaction: "nope"}
+	if err := normalizeOperation(&bad); err == nil {
+		t.Error("normalizeOperation() accepted invalid emoji")
+	}
+}
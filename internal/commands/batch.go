@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run NDJSON requests from stdin",
+	Long: `Read newline-delimited JSON requests from stdin and write one JSON
+result per line to stdout. One API client is shared across requests, so
+gh-talk can run as a long-lived worker for agents and scripts.
+
+Requests:
+  {"op":"reply","thread":"PRRT_...","body":"Fixed"}
+  {"op":"resolve","thread":"PRRT_..."}
+  {"op":"react","comment":"PRRC_...","reaction":"👍"}
+  {"op":"hide","comment":"IC_...","reason":"outdated"}
+  {"op":"list","repo":"owner/repo","pr":123}
+
+Operations: list, reply, react, unreact, resolve, unresolve, hide, unhide
+
+An optional "id" is echoed back in the result. When a request names a
+PR with "repo" and "pr" (or --repo and --pr are given), mutations are
+validated against it first, including your permissions. PR data is
+cached between requests and refreshed after changes.
+
+Results:
+  {"id":1,"op":"resolve","ok":true,"status":"ok"}
+  {"id":2,"op":"reply","ok":false,"error":{"code":"rejected","message":"..."}}
+
+Error codes: invalid_json, invalid_request, rejected, api_error
+
+With --dry-run, requests are checked the same way but mutations are not
+sent; each result lists the mutations it would have sent instead.
+
+Examples:
+  # Resolve two threads
+  printf '%s\n' '{"op":"resolve","thread":"PRRT_a"}' '{"op":"resolve","thread":"PRRT_b"}' | gh talk batch
+
+  # Run as a worker for a PR
+  my-agent | gh talk batch --repo owner/repo --pr 123
+
+  # Check requests without changing anything
+  gh talk batch --repo owner/repo --pr 123 --dry-run < requests.ndjson`,
+	Args: cobra.NoArgs,
+	RunE: runBatch,
+}
+
+// batchRequest is one line of batch input
+type batchRequest struct {
+	ID json.RawMessage `json:"id,omitempty"`
+	ops.Operation
+	Repo string `json:"repo,omitempty"`
+	PR   int    `json:"pr,omitempty"`
+}
+
+// batchError is a structured error in a batch result
+type batchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// batchResponse is one line of batch output
+type batchResponse struct {
+	ID        json.RawMessage `json:"id,omitempty"`
+	Op        ops.Kind        `json:"op,omitempty"`
+	OK        bool            `json:"ok"`
+	Status    string          `json:"status,omitempty"`
+	Threads   []jsonThread    `json:"threads,omitempty"`
+	Mutations []api.Mutation  `json:"mutations,omitempty"`
+	Error     *batchError     `json:"error,omitempty"`
+}
+
+// batchClient is the part of the API client batch uses
+type batchClient interface {
+	ops.Mutator
+	GetPullRequest(ctx context.Context, owner, name string, pr int) (*api.PullRequest, error)
+}
+
+// batchRunner executes batch requests with a shared client and PR cache.
+// For --dry-run, client records mutations instead of sending them and
+// mutations returns what it has recorded so far.
+type batchRunner struct {
+	client      batchClient
+	mutations   func() []api.Mutation
+	defaultRepo string
+	defaultPR   int
+	cache       map[prRef]*api.PullRequest
+}
+
+// prRef identifies a pull request in a batch request
+type prRef struct {
+	Repo   string
+	Number int
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	if format := dryRunFormat(cmd); format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("invalid --dry-run format: %s\n\nValid formats: text, json", format)
+	}
+
+	client, err := newMutationClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	runner := &batchRunner{client: client, cache: make(map[prRef]*api.PullRequest)}
+	if dryRunFormat(cmd) != "" {
+		runner.mutations = client.Mutations
+	}
+
+	repoFlag, _ := cmd.Flags().GetString("repo")
+	if repoFlag != "" {
+		owner, name, err := getRepository(cmd)
+		if err != nil {
+			return err
+		}
+		runner.defaultRepo = owner + "/" + name
+	}
+	runner.defaultPR, _ = cmd.Flags().GetInt("pr")
+
+	return runner.run(context.Background(), factory.IOStreams.In, factory.IOStreams.Out)
+}
+
+// run processes requests until in is exhausted
+func (r *batchRunner) run(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := encoder.Encode(r.handle(ctx, line)); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read requests: %w", err)
+	}
+	return nil
+}
+
+// handle executes a single request line
+func (r *batchRunner) handle(ctx context.Context, line []byte) batchResponse {
+	var req batchRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return batchFailure(nil, "", "invalid_json", err)
+	}
+
+	resp := batchResponse{ID: req.ID, Op: req.Op}
+	ref, hasPR := r.prRef(req)
+
+	if req.Op == "list" {
+		if !hasPR {
+			return batchFailure(req.ID, req.Op, "invalid_request", fmt.Errorf("list needs repo and pr"))
+		}
+		pr, err := r.pullRequest(ctx, ref)
+		if err != nil {
+			return batchFailure(req.ID, req.Op, "api_error", err)
+		}
+		resp.OK, resp.Status = true, ops.StatusOK
+		resp.Threads = threadsToJSON(pr.ReviewThreads)
+		if resp.Threads == nil {
+			resp.Threads = []jsonThread{}
+		}
+		return resp
+	}
+
+	op := req.Operation
+	if err := normalizeOperation(&op); err != nil {
+		return batchFailure(req.ID, req.Op, "invalid_request", err)
+	}
+	if err := op.Validate(); err != nil {
+		return batchFailure(req.ID, req.Op, "invalid_request", err)
+	}
+
+	change := ops.Change{Operation: op}
+	if hasPR {
+		pr, err := r.pullRequest(ctx, ref)
+		if err != nil {
+			return batchFailure(req.ID, req.Op, "api_error", err)
+		}
+		change, err = ops.NewIndex(pr).Check(op)
+		if err != nil {
+			return batchFailure(req.ID, req.Op, "rejected", err)
+		}
+	}
+
+	var recorded int
+	if r.mutations != nil {
+		recorded = len(r.mutations())
+	}
+
+	result := (&ops.Executor{Client: r.client}).Execute(ctx, []ops.Change{change})[0]
+	if result.Status == ops.StatusFailed {
+		return batchFailure(req.ID, req.Op, "api_error", fmt.Errorf("%s", result.Error))
+	}
+
+	if r.mutations != nil {
+		// Nothing changed, so cached data is still current
+		resp.Mutations = r.mutations()[recorded:]
+	} else if result.Status == ops.StatusOK {
+		// The PR changed, so cached data is stale
+		if hasPR {
+			delete(r.cache, ref)
+		} else {
+			r.cache = make(map[prRef]*api.PullRequest)
+		}
+	}
+
+	resp.OK, resp.Status = true, result.Status
+	return resp
+}
+
+// prRef returns the pull request a request refers to, if any
+func (r *batchRunner) prRef(req batchRequest) (prRef, bool) {
+	ref := prRef{Repo: req.Repo, Number: req.PR}
+	if ref.Repo == "" {
+		ref.Repo = r.defaultRepo
+	}
+	if ref.Number == 0 {
+		ref.Number = r.defaultPR
+	}
+	return ref, ref.Repo != "" && ref.Number != 0
+}
+
+// pullRequest returns the pull request, fetching it if not cached
+func (r *batchRunner) pullRequest(ctx context.Context, ref prRef) (*api.PullRequest, error) {
+	if pr, ok := r.cache[ref]; ok {
+		return pr, nil
+	}
+
+	repo, err := repository.Parse(ref.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository: %s", ref.Repo)
+	}
+
+	pr, err := r.client.GetPullRequest(ctx, repo.Owner, repo.Name, ref.Number)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[ref] = pr
+	return pr, nil
+}
+
+func batchFailure(id json.RawMessage, op ops.Kind, code string, err error) batchResponse {
+	return batchResponse{
+		ID:    id,
+		Op:    op,
+		OK:    false,
+		Error: &batchError{Code: code, Message: err.Error()},
+	}
+}
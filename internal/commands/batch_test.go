@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// fakeBatchClient serves one PR and records mutations
+type fakeBatchClient struct {
+	pr      *api.PullRequest
+	fetches int
+	calls   []string
+}
+
+func (f *fakeBatchClient) GetPullRequest(ctx context.Context, owner, name string, pr int) (*api.PullRequest, error) {
+	f.fetches++
+	return f.pr, nil
+}
+
+func (f *fakeBatchClient) record(call string) error {
+	f.calls = append(f.calls, call)
+	if strings.Contains(call, "PRRT_fail") {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (f *fakeBatchClient) ReplyToThread(ctx context.Context, threadID, body string) (*api.Comment, error) {
+	return nil, f.record("reply " + threadID + " " + body)
+}
+func (f *fakeBatchClient) ResolveThread(ctx context.Context, threadID string) (*api.Thread, error) {
+	return nil, f.record("resolve " + threadID)
+}
+func (f *fakeBatchClient) UnresolveThread(ctx context.Context, threadID string) (*api.Thread, error) {
+	return nil, f.record("unresolve " + threadID)
+}
+func (f *fakeBatchClient) AddReaction(ctx context.Context, subjectID, content string) (*api.Reaction, error) {
+	return nil, f.record("react " + subjectID + " " + content)
+}
+func (f *fakeBatchClient) RemoveReaction(ctx context.Context, subjectID, content string) (*api.Reaction, error) {
+	return nil, f.record("unreact " + subjectID + " " + content)
+}
+func (f *fakeBatchClient) MinimizeComment(ctx context.Context, commentID, classifier string) (*api.Comment, error) {
+	return nil, f.record("hide " + commentID)
+}
+func (f *fakeBatchClient) UnminimizeComment(ctx context.Context, commentID string) (*api.Comment, error) {
+	return nil, f.record("unhide " + commentID)
+}
+
+func TestBatchRunner(t *testing.T) {
+	client := &fakeBatchClient{pr: &api.PullRequest{
+		ReviewThreads: []api.Thread{
+			{ID: "PRRT_a", Path: "main.go", Line: 3, ViewerCanResolve: true},
+			{ID: "PRRT_b", ViewerCanResolve: false},
+		},
+	}}
+	runner := &batchRunner{client: client, cache: make(map[prRef]*api.PullRequest)}
+
+	input := strings.Join([]string{
+		`{"id":1,"op":"list","repo":"owner/repo","pr":1}`,
+		`{"id":2,"op":"resolve","thread":"PRRT_a","repo":"owner/repo","pr":1}`,
+		`{"id":3,"op":"resolve","thread":"PRRT_b","repo":"owner/repo","pr":1}`,
+		`{"id":"x","op":"react","comment":"PRRC_c","reaction":"🚀"}`,
+		`{"op":"reply","thread":"PRRT_fail","body":"hi"}`,
+		`{"op":"reply","thread":"PRRT_a"}`,
+		`not json`,
+		``,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := runner.run(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []batchResponse
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var r batchResponse
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+
+	type want struct {
+		id   string
+		ok   bool
+		code string
+	}
+	wants := []want{
+		{`1`, true, ""},
+		{`2`, true, ""},
+		{`3`, false, "rejected"},
+		{`"x"`, true, ""},
+		{``, false, "api_error"},
+		{``, false, "invalid_request"},
+		{``, false, "invalid_json"},
+	}
+
+	if len(results) != len(wants) {
+		t.Fatalf("got %d results, want %d:\n%s", len(results), len(wants), out.String())
+	}
+	for i, w := range wants {
+		r := results[i]
+		if string(r.ID) != w.id || r.OK != w.ok {
+			t.Errorf("result %d = id %s ok %v, want id %s ok %v", i, r.ID, r.OK, w.id, w.ok)
+		}
+		if w.code != "" && (r.Error == nil || r.Error.Code != w.code) {
+			t.Errorf("result %d error = %+v, want code %s", i, r.Error, w.code)
+		}
+	}
+
+	if len(results[0].Threads) != 2 {
+		t.Errorf("list returned %d threads, want 2", len(results[0].Threads))
+	}
+
+	wantCalls := []string{"resolve PRRT_a", "react PRRC_c ROCKET", "reply PRRT_fail hi"}
+	if strings.Join(client.calls, ",") != strings.Join(wantCalls, ",") {
+		t.Errorf("calls = %v, want %v", client.calls, wantCalls)
+	}
+
+	// list and resolve share the cached PR; the resolve invalidates it
+	// before the next request fetches again
+	if client.fetches != 2 {
+		t.Errorf("fetches = %d, want 2", client.fetches)
+	}
+}
+
+func TestBatchDryRun(t *testing.T) {
+	gh := newFakeGitHub(t, nil)
+	f, out, _ := newTestFactory(t, gh)
+	f.IOStreams.In = strings.NewReader(strings.Join([]string{
+		`{"id":1,"op":"resolve","thread":"PRRT_a"}`,
+		`{"id":2,"op":"react","comment":"PRRC_1","reaction":"👍"}`,
+	}, "\n"))
+
+	if err := runCommand(t, f, "batch", "--dry-run"); err != nil {
+		t.Fatalf("batch --dry-run: %v", err)
+	}
+
+	if ops := gh.operations(); len(ops) != 0 {
+		t.Errorf("sent %v, want nothing", ops)
+	}
+
+	var names []string
+	decoder := json.NewDecoder(out)
+	for decoder.More() {
+		var r batchResponse
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("decode result: %v", err)
+		}
+		if !r.OK || len(r.Mutations) != 1 {
+			t.Errorf("result %s = %+v, want ok with one mutation", r.ID, r)
+			continue
+		}
+		names = append(names, r.Mutations[0].Name)
+	}
+	if got := strings.Join(names, ","); got != "ResolveThread,AddReaction" {
+		t.Errorf("mutations = %s, want ResolveThread,AddReaction", got)
+	}
+}
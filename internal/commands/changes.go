@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// lineRange is an inclusive range of line numbers
+type lineRange struct {
+	Start int
+	End   int
+}
+
+// overlaps reports whether two ranges share at least one line
+func (r lineRange) overlaps(o lineRange) bool {
+	return r.Start <= o.End && o.Start <= r.End
+}
+
+// hunkHeaderPattern matches unified diff hunk headers (@@ -a,b +c,d @@)
+var hunkHeaderPattern = regexp.MustCompile(`(?m)^@@ -(\d+)(?:,(\d+))? \+\d+(?:,\d+)? @@`)
+
+// parseDiffHunks returns the old-side line ranges touched by a unified diff.
+//
+// Pure insertions (-a,0) are reported as the line they follow so that code
+// added inside a commented range still counts as a change to it.
+func parseDiffHunks(diff string) []lineRange {
+	var ranges []lineRange
+	for _, m := range hunkHeaderPattern.FindAllStringSubmatch(diff, -1) {
+		start, _ := strconv.Atoi(m[1])
+		count := 1
+		if m[2] != "" {
+			count, _ = strconv.Atoi(m[2])
+		}
+
+		if count == 0 {
+			ranges = append(ranges, lineRange{Start: start, End: start})
+			continue
+		}
+		ranges = append(ranges, lineRange{Start: start, End: start + count - 1})
+	}
+	return ranges
+}
+
+// changeDetector decides whether code under a thread changed after the
+// thread was started, comparing the thread's original commit with the PR
+// head in the local git repository.
+type changeDetector struct {
+	head   string
+	useGit bool
+	warned bool
+}
+
+// newChangeDetector creates a detector for the given PR head commit.
+//
+// When the head commit is not available locally, detection falls back to
+// GitHub's IsOutdated flag.
+func newChangeDetector(head string) *changeDetector {
+	d := &changeDetector{head: head}
+	if head != "" && gitHasCommit(head) {
+		d.useGit = true
+	} else {
+		d.warn("PR head is not available locally; using GitHub's outdated flag instead")
+	}
+	return d
+}
+
+// changed reports whether the thread's lines were touched after commenting
+func (d *changeDetector) changed(t api.Thread) bool {
+	if !d.useGit || len(t.Comments) == 0 || t.DiffSide == "LEFT" {
+		return t.IsOutdated
+	}
+
+	original := t.Comments[0].OriginalCommit
+	if original == "" || !gitHasCommit(original) {
+		d.warn("some original commits are missing locally (try 'git fetch'); using GitHub's outdated flag for those threads")
+		return t.IsOutdated
+	}
+
+	diff, err := runGit("diff", "--unified=0", original, d.head, "--", t.Path)
+	if err != nil {
+		return t.IsOutdated
+	}
+
+	hunks := parseDiffHunks(diff)
+
+	// File-level threads change with any edit to the file
+	if t.SubjectType == "FILE" || t.OriginalLine == 0 {
+		return len(hunks) > 0
+	}
+
+	commented := lineRange{Start: t.OriginalLine, End: t.OriginalLine}
+	if t.OriginalStartLine > 0 {
+		commented.Start = t.OriginalStartLine
+	}
+
+	for _, h := range hunks {
+		if h.overlaps(commented) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *changeDetector) warn(msg string) {
+	if d.warned {
+		return
+	}
+	d.warned = true
+	fmt.Fprintf(factory.IOStreams.ErrOut, "! %s\n", msg)
+}
+
+// gitHasCommit reports whether a commit exists in the local repository
+func gitHasCommit(sha string) bool {
+	_, err := runGit("cat-file", "-e", strings.TrimSpace(sha)+"^{commit}")
+	return err == nil
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiffHunks(t *testing.T) {
+	diff := `diff --git a/src/api.go b/src/api.go
+index 1111111..2222222 100644
+--- a/src/api.go
++++ b/src/api.go
+@@ -10,3 +10,4 @@ func main() {
+@@ -20 +21 @@ func other() {
+@@ -30,0 +32,2 @@ func added() {
+`
+
+	got := parseDiffHunks(diff)
+	want := []lineRange{
+		{Start: 10, End: 12},
+		{Start: 20, End: 20},
+		{Start: 30, End: 30},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiffHunks() = %v, want %v", got, want)
+	}
+}
+
+func TestLineRangeOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b lineRange
+		want bool
+	}{
+		{"same line", lineRange{5, 5}, lineRange{5, 5}, true},
+		{"contained", lineRange{1, 10}, lineRange{4, 6}, true},
+		{"touching edge", lineRange{1, 5}, lineRange{5, 8}, true},
+		{"before", lineRange{1, 4}, lineRange{5, 8}, false},
+		{"after", lineRange{9, 12}, lineRange{5, 8}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.overlaps(tt.b); got != tt.want {
+				t.Errorf("overlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
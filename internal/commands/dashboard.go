@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show unresolved conversations across your PRs",
+	Long: `Show open pull requests you authored, are assigned to, or have been
+asked to review, with their unresolved review threads.
+
+For each PR the dashboard shows:
+  - Your role (author, assignee, reviewer)
+  - Unresolved thread count
+  - Whose turn it is, based on the last comment in unresolved threads
+  - Age of the oldest unresolved thread
+
+By default the current repository is searched. Use --org for a whole
+organization, or pass several repositories to --repo separated by commas.
+
+Examples:
+  # PRs in the current repository
+  gh talk dashboard
+
+  # All PRs in an organization
+  gh talk dashboard --org my-org
+
+  # Several repositories
+  gh talk dashboard --repo owner/api,owner/web
+
+  # Everywhere, as JSON
+  gh talk dashboard --everywhere --format json`,
+	Args: cobra.NoArgs,
+	RunE: runDashboard,
+}
+
+func init() {
+	dashboardCmd.Flags().String("org", "", "Search all repositories in an organization")
+	dashboardCmd.Flags().Bool("everywhere", false, "Search all repositories")
+	dashboardCmd.Flags().Int("limit", 50, "Maximum PRs per role")
+	dashboardCmd.Flags().String("format", "", "Output format (table, json, tsv)")
+
+	dashboardCmd.MarkFlagsMutuallyExclusive("org", "everywhere")
+}
+
+// dashboardRow summarizes one pull request on the dashboard
+type dashboardRow struct {
+	Repository   string    `json:"repository"`
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	Author       string    `json:"author"`
+	Roles        []string  `json:"roles"`
+	Unresolved   int       `json:"unresolved"`
+	WaitingOn    string    `json:"waitingOn,omitempty"`
+	OldestOpenAt time.Time `json:"oldestOpenAt,omitempty"`
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	scope, err := dashboardScope(cmd)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 1 || limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	prs, err := client.SearchViewerPullRequests(ctx, scope, limit)
+	if err != nil {
+		return err
+	}
+
+	rows := buildDashboard(prs)
+	if len(rows) == 0 {
+		fmt.Fprintln(factory.IOStreams.Out, "No open pull requests found")
+		return nil
+	}
+
+	return outputDashboard(cmd, rows)
+}
+
+// dashboardScope builds the search qualifier from --org, --repo or the
+// current repository
+func dashboardScope(cmd *cobra.Command) (string, error) {
+	everywhere, _ := cmd.Flags().GetBool("everywhere")
+	if everywhere {
+		return "", nil
+	}
+
+	org, _ := cmd.Flags().GetString("org")
+	if org != "" {
+		return "org:" + org, nil
+	}
+
+	repoFlag, _ := cmd.Flags().GetString("repo")
+	if repoFlag != "" {
+		var qualifiers []string
+		for _, r := range strings.Split(repoFlag, ",") {
+			repo, err := repository.Parse(strings.TrimSpace(r))
+			if err != nil {
+				return "", fmt.Errorf("invalid repository format: %s", r)
+			}
+			qualifiers = append(qualifiers, fmt.Sprintf("repo:%s/%s", repo.Owner, repo.Name))
+		}
+		return strings.Join(qualifiers, " "), nil
+	}
+
+	repo, err := factory.BaseRepo()
+	if err != nil {
+		return "", fmt.Errorf("could not determine repository\n\nRun this from a git repository, or use --repo, --org or --everywhere")
+	}
+	return fmt.Sprintf("repo:%s/%s", repo.Owner, repo.Name), nil
+}
+
+// buildDashboard merges search results into one row per PR
+func buildDashboard(prs *api.ViewerPullRequests) []dashboardRow {
+	byID := make(map[string]*dashboardRow)
+	var order []string
+
+	add := func(results []api.PullRequest, role string) {
+		for _, pr := range results {
+			if row, ok := byID[pr.ID]; ok {
+				row.Roles = append(row.Roles, role)
+				continue
+			}
+			row := summarizePullRequest(pr)
+			row.Roles = []string{role}
+			byID[pr.ID] = &row
+			order = append(order, pr.ID)
+		}
+	}
+
+	add(prs.Authored, "author")
+	add(prs.Assigned, "assignee")
+	add(prs.ReviewRequested, "reviewer")
+
+	rows := make([]dashboardRow, 0, len(order))
+	for _, id := range order {
+		rows = append(rows, *byID[id])
+	}
+
+	// PRs with the oldest open conversations first
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].OldestOpenAt, rows[j].OldestOpenAt
+		if a.IsZero() != b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b)
+	})
+
+	return rows
+}
+
+// summarizePullRequest counts unresolved threads and works out whose turn it is
+func summarizePullRequest(pr api.PullRequest) dashboardRow {
+	row := dashboardRow{
+		Repository: pr.Repository,
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.URL,
+		Author:     pr.Author.Login,
+	}
+
+	// The thread with the most recent comment decides whose turn it is
+	var lastActivity time.Time
+	for _, t := range pr.ReviewThreads {
+		if t.IsResolved || len(t.Comments) == 0 {
+			continue
+		}
+		row.Unresolved++
+
+		first := t.Comments[0]
+		if row.OldestOpenAt.IsZero() || first.CreatedAt.Before(row.OldestOpenAt) {
+			row.OldestOpenAt = first.CreatedAt
+		}
+
+		latest := t.Comments[len(t.Comments)-1]
+		if row.WaitingOn == "" || latest.CreatedAt.After(lastActivity) {
+			lastActivity = latest.CreatedAt
+			row.WaitingOn = t.WaitingOn
+		}
+	}
+
+	return row
+}
+
+func outputDashboard(cmd *cobra.Command, rows []dashboardRow) error {
+	format, _ := cmd.Flags().GetString("format")
+	ios := factory.IOStreams
+
+	if format == "" {
+		if ios.IsTerminal {
+			format = "table"
+		} else {
+			format = "tsv"
+		}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(ios.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case "table", "tsv":
+		isTTY := format == "table"
+		width := 0
+		if isTTY {
+			width = ios.Width
+		}
+		t := tableprinter.New(ios.Out, isTTY, width)
+
+		t.AddField("PR")
+		t.AddField("Title")
+		t.AddField("Role")
+		t.AddField("Unresolved")
+		t.AddField("Waiting On")
+		t.AddField("Oldest")
+		t.EndRow()
+
+		for _, row := range rows {
+			t.AddField(fmt.Sprintf("%s#%d", row.Repository, row.Number))
+			t.AddField(row.Title)
+			t.AddField(strings.Join(row.Roles, ","))
+			t.AddField(fmt.Sprintf("%d", row.Unresolved))
+			t.AddField(row.WaitingOn)
+			age := ""
+			if !row.OldestOpenAt.IsZero() {
+				age = formatAge(time.Since(row.OldestOpenAt))
+			}
+			t.AddField(age)
+			t.EndRow()
+		}
+
+		return t.Render()
+	default:
+		return fmt.Errorf("unknown format: %s\n\nValid formats: table, json, tsv", format)
+	}
+}
+
+// formatAge formats a duration as a compact age such as 3d or 5h
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
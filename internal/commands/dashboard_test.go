@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+const dashboardSearchResponse = `{"data":{
+  "authored":{"nodes":[
+    {"id":"PR_1","number":1,"title":"Add API","url":"https://github.com/o/r/pull/1","createdAt":"2025-11-01T00:00:00Z",
+     "author":{"login":"me"},"repository":{"nameWithOwner":"o/r"},
+     "reviewThreads":{"nodes":[
+       {"id":"PRRT_a","isResolved":false,"firstComment":{"nodes":[
+         {"id":"PRRC_1","createdAt":"2025-11-02T00:00:00Z","author":{"login":"rev"}}
+       ]},"lastComment":{"nodes":[
+         {"id":"PRRC_1","createdAt":"2025-11-02T00:00:00Z","author":{"login":"rev"}}
+       ]}},
+       {"id":"PRRT_b","isResolved":true,"firstComment":{"nodes":[
+         {"id":"PRRC_2","createdAt":"2025-11-01T00:00:00Z","author":{"login":"rev"}}
+       ]},"lastComment":{"nodes":[
+         {"id":"PRRC_2","createdAt":"2025-11-01T00:00:00Z","author":{"login":"rev"}}
+       ]}}
+     ]}}
+  ]},
+  "assigned":{"nodes":[
+    {"id":"PR_1","number":1,"title":"Add API","url":"https://github.com/o/r/pull/1","createdAt":"2025-11-01T00:00:00Z",
+     "author":{"login":"me"},"repository":{"nameWithOwner":"o/r"},"reviewThreads":{"nodes":[]}}
+  ]},
+  "reviewRequested":{"nodes":[
+    {"id":"PR_2","number":2,"title":"Fix DB","url":"https://github.com/o/r/pull/2","createdAt":"2025-10-01T00:00:00Z",
+     "author":{"login":"other"},"repository":{"nameWithOwner":"o/r"},
+     "reviewThreads":{"nodes":[
+       {"id":"PRRT_c","isResolved":false,"firstComment":{"nodes":[
+         {"id":"PRRC_3","createdAt":"2025-10-02T00:00:00Z","author":{"login":"me"}}
+       ]},"lastComment":{"nodes":[
+         {"id":"PRRC_4","createdAt":"2025-10-03T00:00:00Z","author":{"login":"other"}}
+       ]}}
+     ]}},
+    {}
+  ]}
+}}`
+
+func TestBuildDashboard(t *testing.T) {
+	gh := newFakeGitHub(t, map[string]string{"SearchViewerPullRequests": dashboardSearchResponse})
+	client, err := api.NewClientWithOptions(gh.clientOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prs, err := client.SearchViewerPullRequests(context.Background(), "repo:o/r", 10)
+	if err != nil {
+		t.Fatalf("SearchViewerPullRequests() error = %v", err)
+	}
+
+	rows := buildDashboard(prs)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %+v", rows)
+	}
+
+	// Oldest open conversation first
+	if rows[0].Number != 2 || rows[0].WaitingOn != "reviewer" || rows[0].Roles[0] != "reviewer" {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if !rows[0].OldestOpenAt.Equal(time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("OldestOpenAt = %v", rows[0].OldestOpenAt)
+	}
+
+	if rows[1].Number != 1 || rows[1].Unresolved != 1 || rows[1].WaitingOn != "author" {
+		t.Errorf("unexpected second row: %+v", rows[1])
+	}
+	if len(rows[1].Roles) != 2 || rows[1].Roles[1] != "assignee" {
+		t.Errorf("roles = %v, want [author assignee]", rows[1].Roles)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Minute, "30m"},
+		{5 * time.Hour, "5h"},
+		{73 * time.Hour, "3d"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.d); got != tt.want {
+			t.Errorf("formatAge(%v) = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
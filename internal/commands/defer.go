@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/format"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+)
+
+var deferCmd = &cobra.Command{
+	Use:   "defer <thread-id...>",
+	Short: "Track review threads in follow-up issues",
+	Long: `Create a GitHub issue for each review thread, reply "Tracked in #N" on
+the thread and resolve it.
+
+Each issue has the thread's file and line, a permalink, the diff hunk and
+the comments. With --combined, all threads go into one issue.
+
+Arguments:
+  thread-id...  Thread IDs (PRRT_...), discussion URLs or handles (r123)
+
+Examples:
+  # One issue per thread
+  gh talk defer PRRT_abc123 PRRT_def456
+
+  # One issue for several threads, labelled and assigned to yourself
+  gh talk defer r101 r205 --combined --label tech-debt --assignee @me
+
+  # Into a milestone, with a custom title
+  gh talk defer PRRT_abc123 --milestone v2.0 --title "Cache widget lookups"
+
+  # Show the mutations that would be sent
+  gh talk defer PRRT_abc123 --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDefer,
+}
+
+func init() {
+	deferCmd.Flags().Bool("combined", false, "Create one issue for all threads")
+	deferCmd.Flags().String("title", "", "Issue title (default from the first comment)")
+	deferCmd.Flags().StringSliceP("label", "l", nil, "Add labels by name (repeatable)")
+	deferCmd.Flags().StringSliceP("assignee", "a", nil, "Assign people by login, \"@me\" for yourself (repeatable)")
+	deferCmd.Flags().StringP("milestone", "m", "", "Add to a milestone by title or number")
+	deferCmd.Flags().BoolP("yes", "y", false, "Skip confirmation for multiple issues")
+	deferCmd.Flags().Bool("json", false, "Output the issues and thread states as JSON")
+}
+
+// deferredIssue is an issue to create and the threads it tracks
+type deferredIssue struct {
+	Title   string
+	Body    string
+	Threads []api.Thread
+}
+
+// deferOutput is the --json output of defer, one per issue
+type deferOutput struct {
+	Issue   jsonIssue          `json:"issue"`
+	Threads []*jsonThreadState `json:"threads"`
+}
+
+// jsonIssue is an issue created by a command
+type jsonIssue struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	URL    string `json:"url,omitempty"`
+	Title  string `json:"title"`
+}
+
+func runDefer(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	owner, name, err := getRepository(cmd)
+	if err != nil {
+		return err
+	}
+	prNum, err := getCurrentPR(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newMutationClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	pr, err := client.GetPullRequest(ctx, owner, name, prNum)
+	if err != nil {
+		return err
+	}
+
+	var threads []api.Thread
+	seen := make(map[string]bool)
+	for _, ref := range args {
+		thread, err := resolveThreadRef(ref, pr.ReviewThreads)
+		if err != nil {
+			return err
+		}
+		if seen[thread.ID] {
+			continue
+		}
+		seen[thread.ID] = true
+		threads = append(threads, *thread)
+	}
+
+	// Check replying to and resolving every thread, including the
+	// viewer's permissions, before creating any issue
+	ix := ops.NewIndex(pr)
+	var checks []ops.Operation
+	for _, t := range threads {
+		checks = append(checks,
+			ops.Operation{Op: ops.Reply, Thread: t.ID, Body: trackedMessage(&api.Issue{})},
+			ops.Operation{Op: ops.Resolve, Thread: t.ID},
+		)
+	}
+	if _, err := ix.CheckAll(checks); err != nil {
+		return err
+	}
+
+	combined, _ := cmd.Flags().GetBool("combined")
+	title, _ := cmd.Flags().GetString("title")
+	issues := deferredIssues(pr, threads, combined, title)
+
+	labels, _ := cmd.Flags().GetStringSlice("label")
+	assignees, _ := cmd.Flags().GetStringSlice("assignee")
+	milestone, _ := cmd.Flags().GetString("milestone")
+	meta, err := client.LookupIssueMetadata(ctx, owner, name, labels, assignees, milestone)
+	if err != nil {
+		return err
+	}
+
+	if dryRunFormat(cmd) != "" {
+		var operations []ops.Operation
+		for _, issue := range issues {
+			created, err := client.CreateIssue(ctx, meta, issue.Title, issue.Body)
+			if err != nil {
+				return err
+			}
+			for _, t := range issue.Threads {
+				operations = append(operations,
+					ops.Operation{Op: ops.Reply, Thread: t.ID, Body: trackedMessage(created)},
+					ops.Operation{Op: ops.Resolve, Thread: t.ID},
+				)
+			}
+		}
+		return runDryRun(ctx, cmd, client, ix, operations)
+	}
+
+	if len(issues) > 1 {
+		skipConfirm, _ := cmd.Flags().GetBool("yes")
+		if !skipConfirm {
+			p := factory.Prompter()
+			confirmed, err := p.Confirm(fmt.Sprintf("Create %d issues?", len(issues)), false)
+			if err != nil || !confirmed {
+				return fmt.Errorf("cancelled")
+			}
+		}
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	outputs := make([]deferOutput, 0, len(issues))
+	for _, issue := range issues {
+		created, err := client.CreateIssue(ctx, meta, issue.Title, issue.Body)
+		if err != nil {
+			return err
+		}
+		if !jsonOutput {
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Created issue #%d %s\n", created.Number, created.URL)
+		}
+
+		output := deferOutput{Issue: jsonIssue{ID: created.ID, Number: created.Number, URL: created.URL, Title: created.Title}}
+		for _, t := range issue.Threads {
+			reply, err := client.ReplyToThread(ctx, t.ID, trackedMessage(created))
+			if err != nil {
+				return fmt.Errorf("created issue #%d but failed to reply to %s: %w", created.Number, t.ID, err)
+			}
+			resolved, err := client.ResolveThread(ctx, t.ID)
+			if err != nil {
+				return fmt.Errorf("created issue #%d but failed to resolve %s: %w", created.Number, t.ID, err)
+			}
+			state := threadStateToJSON(resolved)
+			state.Reply = commentToJSON(reply)
+			output.Threads = append(output.Threads, state)
+
+			if !jsonOutput {
+				fmt.Fprintf(factory.IOStreams.Out, "✓ Resolved %s\n", t.ID)
+			}
+		}
+		outputs = append(outputs, output)
+	}
+
+	if jsonOutput {
+		return printJSON(outputs)
+	}
+	return nil
+}
+
+// deferredIssues groups threads into the issues to create: one per
+// thread, or a single one when combined. A non-empty title replaces the
+// default titles.
+func deferredIssues(pr *api.PullRequest, threads []api.Thread, combined bool, title string) []deferredIssue {
+	if combined {
+		if title == "" {
+			title = fmt.Sprintf("Follow-ups from review of #%d", pr.Number)
+			if pr.Title != "" {
+				title += ": " + pr.Title
+			}
+		}
+		return []deferredIssue{{Title: title, Body: format.FollowUpIssue(pr, threads), Threads: threads}}
+	}
+
+	issues := make([]deferredIssue, len(threads))
+	for i, t := range threads {
+		issueTitle := title
+		if issueTitle == "" {
+			issueTitle = followUpTitle(t)
+		}
+		issues[i] = deferredIssue{
+			Title:   issueTitle,
+			Body:    format.FollowUpIssue(pr, []api.Thread{t}),
+			Threads: []api.Thread{t},
+		}
+	}
+	return issues
+}
+
+// followUpTitle is the default title of an issue tracking one thread:
+// the first line of its first comment
+func followUpTitle(t api.Thread) string {
+	summary := ""
+	if len(t.Comments) > 0 {
+		summary, _, _ = strings.Cut(strings.TrimSpace(t.Comments[0].Body), "\n")
+	}
+	if summary == "" {
+		return fmt.Sprintf("Follow up on %s", t.Path)
+	}
+	return "Follow up: " + truncate(summary, 72)
+}
+
+// trackedMessage is the reply posted on a deferred thread. Issues created
+// by a dry-run client have no number yet.
+func trackedMessage(issue *api.Issue) string {
+	if issue.Number == 0 {
+		return "Tracked in #NEW"
+	}
+	return fmt.Sprintf("Tracked in #%d", issue.Number)
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const deferThreads = `{"data":{"repository":{"pullRequest":{"id":"PR_1","number":1,"title":"Add widgets","url":"https://github.com/owner/repo/pull/1","reviewThreads":{"nodes":[
+  {"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"viewerCanResolve":true,"viewerCanReply":true,"comments":{"nodes":[
+    {"id":"PRRC_1","databaseId":101,"url":"https://github.com/owner/repo/pull/1#discussion_r101","diffHunk":"@@ -1,2 +1,3 @@\n+const x = 7","body":"Use a constant\nfor the size","author":{"login":"reviewer"},"reactionGroups":[]}
+  ]}},
+  {"id":"PRRT_b","isResolved":false,"path":"util.go","line":3,"viewerCanResolve":true,"viewerCanReply":true,"comments":{"nodes":[
+    {"id":"PRRC_3","databaseId":103,"body":"Typo","author":{"login":"reviewer"},"reactionGroups":[]}
+  ]}}
+]}}}}}`
+
+func deferResponses() map[string]string {
+	return map[string]string{
+		"ListThreads":   deferThreads,
+		"IssueMetadata": `{"data":{"repository":{"id":"R_1","milestones":{"nodes":[{"id":"MI_1","number":2,"title":"v2.0"}]}}}}`,
+		"LabelID":       `{"data":{"repository":{"label":{"id":"LA_1"}}}}`,
+		"ViewerID":      `{"data":{"viewer":{"id":"U_me"}}}`,
+		"CreateIssue":   `{"data":{"createIssue":{"issue":{"id":"I_1","number":12,"url":"https://github.com/owner/repo/issues/12","title":"Follow up","state":"OPEN"}}}}`,
+		"AddReply":      `{"data":{"addPullRequestReviewThreadReply":{"comment":{"id":"PRRC_9","body":"Tracked in #12"}}}}`,
+		"ResolveThread": `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":true}}}}`,
+	}
+}
+
+// mutationInputs returns the input variables of each request for an
+// operation, in order
+func mutationInputs(gh *fakeGitHub, operation string) []map[string]interface{} {
+	var inputs []map[string]interface{}
+	for _, r := range gh.requests {
+		if r.Operation == operation {
+			input, _ := r.Variables["input"].(map[string]interface{})
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+func TestDefer(t *testing.T) {
+	t.Run("per thread", func(t *testing.T) {
+		gh := newFakeGitHub(t, deferResponses())
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "defer", "PRRT_a", "r103", "--label", "tech-debt", "--assignee", "@me", "--milestone", "v2.0"); err != nil {
+			t.Fatalf("defer: %v", err)
+		}
+
+		want := "ListThreads,IssueMetadata,LabelID,ViewerID,CreateIssue,AddReply,ResolveThread,CreateIssue,AddReply,ResolveThread"
+		if got := strings.Join(gh.operations(), ","); got != want {
+			t.Errorf("operations = %s, want %s", got, want)
+		}
+
+		issues := mutationInputs(gh, "CreateIssue")
+		if len(issues) != 2 {
+			t.Fatalf("created %d issues, want 2", len(issues))
+		}
+		first := issues[0]
+		if first["title"] != "Follow up: Use a constant" {
+			t.Errorf("title = %v", first["title"])
+		}
+		body, _ := first["body"].(string)
+		for _, s := range []string{"main.go:7", "#discussion_r101", "```diff\n@@ -1,2 +1,3 @@\n+const x = 7\n```", "@reviewer", "Use a constant"} {
+			if !strings.Contains(body, s) {
+				t.Errorf("body missing %q:\n%s", s, body)
+			}
+		}
+		if first["repositoryId"] != "R_1" || first["milestoneId"] != "MI_1" ||
+			first["labelIds"].([]interface{})[0] != "LA_1" || first["assigneeIds"].([]interface{})[0] != "U_me" {
+			t.Errorf("input = %v", first)
+		}
+		if issues[1]["title"] != "Follow up: Typo" {
+			t.Errorf("second title = %v", issues[1]["title"])
+		}
+
+		for _, reply := range mutationInputs(gh, "AddReply") {
+			if reply["body"] != "Tracked in #12" {
+				t.Errorf("reply body = %v", reply["body"])
+			}
+		}
+		if !strings.Contains(out.String(), "✓ Created issue #12 https://github.com/owner/repo/issues/12") {
+			t.Errorf("output = %s", out.String())
+		}
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		gh := newFakeGitHub(t, deferResponses())
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "defer", "PRRT_a", "PRRT_b", "--combined", "--json"); err != nil {
+			t.Fatalf("defer --combined: %v", err)
+		}
+
+		issues := mutationInputs(gh, "CreateIssue")
+		if len(issues) != 1 {
+			t.Fatalf("created %d issues, want 1", len(issues))
+		}
+		if issues[0]["title"] != "Follow-ups from review of #1: Add widgets" {
+			t.Errorf("title = %v", issues[0]["title"])
+		}
+		body, _ := issues[0]["body"].(string)
+		if !strings.Contains(body, "## main.go:7") || !strings.Contains(body, "## util.go:3") {
+			t.Errorf("body missing a thread:\n%s", body)
+		}
+		if _, ok := issues[0]["labelIds"]; ok {
+			t.Errorf("labelIds sent without --label: %v", issues[0])
+		}
+
+		var output []deferOutput
+		if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+			t.Fatalf("parse output: %v\n%s", err, out.String())
+		}
+		if len(output) != 1 || output[0].Issue.Number != 12 || len(output[0].Threads) != 2 || output[0].Threads[0].Reply == nil {
+			t.Errorf("output = %s", out.String())
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		gh := newFakeGitHub(t, deferResponses())
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "defer", "PRRT_a", "--dry-run=json"); err != nil {
+			t.Fatalf("defer --dry-run: %v", err)
+		}
+		if got := strings.Join(gh.operations(), ","); got != "ListThreads,IssueMetadata" {
+			t.Errorf("operations = %s", got)
+		}
+
+		var report dryRunReport
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatalf("parse output: %v\n%s", err, out.String())
+		}
+		var names []string
+		for _, m := range report.Mutations {
+			names = append(names, m.Name)
+		}
+		if got := strings.Join(names, ","); got != "CreateIssue,AddReply,ResolveThread" {
+			t.Errorf("mutations = %s", got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		gh := newFakeGitHub(t, deferResponses())
+		f, _, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "defer", "PRRT_missing"); err == nil || !strings.Contains(err.Error(), "thread not found") {
+			t.Errorf("unknown thread: error = %v", err)
+		}
+		gh.respond("LabelID", `{"data":{"repository":{"label":null}}}`)
+		if err := runCommand(t, f, "defer", "PRRT_a", "--label", "nope"); err == nil || !strings.Contains(err.Error(), "label not found") {
+			t.Errorf("unknown label: error = %v", err)
+		}
+		if ops := mutationInputs(gh, "CreateIssue"); len(ops) != 0 {
+			t.Errorf("created issues despite errors: %v", ops)
+		}
+	})
+
+	t.Run("cannot resolve", func(t *testing.T) {
+		for _, args := range [][]string{{"defer", "PRRT_a", "PRRT_b"}, {"defer", "PRRT_a", "PRRT_b", "--dry-run"}} {
+			gh := newFakeGitHub(t, deferResponses())
+			gh.respond("ListThreads", strings.Replace(deferThreads, `"line":3,"viewerCanResolve":true`, `"line":3`, 1))
+			f, _, _ := newTestFactory(t, gh)
+
+			err := runCommand(t, f, args...)
+			if err == nil || !strings.Contains(err.Error(), "you cannot resolve PRRT_b") {
+				t.Errorf("%v: error = %v", args, err)
+			}
+			if got := strings.Join(gh.operations(), ","); got != "ListThreads" {
+				t.Errorf("%v: operations = %s, want nothing created", args, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-snapshot> [<new-snapshot> | --live]",
+	Short: "Show what changed between two snapshots",
+	Long: `Compare two snapshots of a PR conversation, or a snapshot with the
+live PR, and report what changed:
+  - Threads added, resolved and unresolved
+  - Replies and top-level comments added
+  - Comments edited, hidden and unhidden
+  - Reactions added and removed
+
+With --live, the repository and PR number are taken from the old
+snapshot unless --repo and --pr are given.
+
+Examples:
+  # What changed in review since yesterday's snapshot
+  gh talk diff yesterday.json --live
+
+  # Compare two saved snapshots as JSON
+  gh talk diff before.json after.json --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().Bool("live", false, "Compare with the current state of the PR")
+	diffCmd.Flags().String("format", "text", "Output format (text, json)")
+}
+
+// diffReport is the JSON output of diff
+type diffReport struct {
+	Repository string       `json:"repository"`
+	Number     int          `json:"number"`
+	From       time.Time    `json:"from,omitempty"`
+	To         time.Time    `json:"to,omitempty"`
+	Events     []diff.Event `json:"events"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format: %s\n\nValid formats: text, json", format)
+	}
+
+	live, _ := cmd.Flags().GetBool("live")
+	if live == (len(args) == 2) {
+		return fmt.Errorf("specify either a new snapshot or --live")
+	}
+
+	old, err := api.LoadSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+
+	var current *api.Snapshot
+	if live {
+		current, err = liveSnapshot(ctx, cmd, old)
+	} else {
+		current, err = api.LoadSnapshot(args[1])
+	}
+	if err != nil {
+		return err
+	}
+
+	oldPR, newPR := old.PullRequest(), current.PullRequest()
+	if !strings.EqualFold(oldPR.Repository, newPR.Repository) || oldPR.Number != newPR.Number {
+		return fmt.Errorf("cannot compare different pull requests: %s#%d and %s#%d",
+			oldPR.Repository, oldPR.Number, newPR.Repository, newPR.Number)
+	}
+
+	report := diffReport{
+		Repository: newPR.Repository,
+		Number:     newPR.Number,
+		From:       old.CapturedAt,
+		To:         current.CapturedAt,
+		Events:     diff.ComparePullRequests(oldPR, newPR),
+	}
+	if report.Events == nil {
+		report.Events = []diff.Event{}
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(factory.IOStreams.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	return printDiffReport(factory.IOStreams.Out, report)
+}
+
+// liveSnapshot fetches the PR the old snapshot was taken from
+func liveSnapshot(ctx context.Context, cmd *cobra.Command, old *api.Snapshot) (*api.Snapshot, error) {
+	pr := old.PullRequest()
+
+	repoFlag, _ := cmd.Flags().GetString("repo")
+	if repoFlag != "" || pr.Repository == "" {
+		owner, name, err := getRepository(cmd)
+		if err != nil {
+			return nil, err
+		}
+		pr.Repository = owner + "/" + name
+	}
+
+	prNum, _ := cmd.Flags().GetInt("pr")
+	if prNum == 0 {
+		prNum = pr.Number
+	}
+	if prNum == 0 {
+		return nil, fmt.Errorf("snapshot does not record a PR number\n\nUse --pr NUMBER with --live")
+	}
+
+	repo, err := repository.Parse(pr.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository in snapshot: %s", pr.Repository)
+	}
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Snapshot(ctx, repo.Owner, repo.Name, prNum)
+}
+
+// printDiffReport prints a digest of changes grouped by thread
+func printDiffReport(w io.Writer, report diffReport) error {
+	fmt.Fprintf(w, "Changes in %s#%d", report.Repository, report.Number)
+	if !report.From.IsZero() {
+		fmt.Fprintf(w, " since %s", report.From.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	if len(report.Events) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return nil
+	}
+
+	counts := make(map[diff.EventType]int)
+	for _, e := range report.Events {
+		counts[e.Type]++
+		fmt.Fprintf(w, "  %s\n", describeEvent(e))
+	}
+
+	fmt.Fprintf(w, "\nSummary: %d new threads, %d replies, %d resolved, %d unresolved, %d edited, %d hidden, %d reaction changes\n",
+		counts[diff.ThreadAdded],
+		counts[diff.CommentAdded],
+		counts[diff.ThreadResolved],
+		counts[diff.ThreadUnresolved],
+		counts[diff.CommentEdited],
+		counts[diff.CommentMinimized],
+		counts[diff.ReactionAdded]+counts[diff.ReactionRemoved],
+	)
+	return nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/diff"
+)
+
+func TestPrintDiffReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []diff.Event
+		want   []string
+	}{
+		{
+			name: "no changes",
+			want: []string{"Changes in owner/repo#7", "No changes"},
+		},
+		{
+			name: "changes",
+			events: []diff.Event{
+				{Type: diff.ThreadResolved, ThreadID: "PRRT_a", Path: "main.go", Line: 3, Author: "alice"},
+				{Type: diff.CommentMinimized, CommentID: "IC_1", Reason: "OUTDATED"},
+			},
+			want: []string{
+				"✓ @alice resolved main.go:3 (PRRT_a)",
+				"⊘ Hidden IC_1 on the conversation (outdated)",
+				"0 new threads, 0 replies, 1 resolved, 0 unresolved, 0 edited, 1 hidden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			report := diffReport{Repository: "owner/repo", Number: 7, Events: tt.events}
+			if err := printDiffReport(&buf, report); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDiffRejectsDifferentPullRequests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(file, repository string, number int) string {
+		path := filepath.Join(dir, file)
+		data := fmt.Sprintf(`{"repository":%q,"number":%d,"data":{}}`, repository, number)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	old := write("old.json", "owner/repo", 7)
+
+	tests := []struct {
+		name    string
+		current string
+		wantErr string
+	}{
+		{"same", write("same.json", "Owner/Repo", 7), ""},
+		{"other number", write("number.json", "owner/repo", 8), "cannot compare different pull requests: owner/repo#7 and owner/repo#8"},
+		{"other repository", write("repo.json", "owner/fork", 7), "cannot compare different pull requests: owner/repo#7 and owner/fork#7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, nil)
+			f, _, _ := newTestFactory(t, gh)
+
+			err := runCommand(t, f, "diff", old, tt.current)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("diff error = %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("diff error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
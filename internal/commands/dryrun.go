@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+)
+
+// dryRunFormat returns the --dry-run output format (text or json), or ""
+// when mutations should be sent
+func dryRunFormat(cmd *cobra.Command) string {
+	format, _ := cmd.Flags().GetString("dry-run")
+	return format
+}
+
+// newMutationClient creates an API client for a command that changes
+// conversations. With --dry-run, the client records mutations instead of
+// sending them.
+func newMutationClient(cmd *cobra.Command) (*api.Client, error) {
+	if dryRunFormat(cmd) != "" {
+		return hostClient(factory.DryRunClient, commandHost(cmd))
+	}
+	return newClient(cmd)
+}
+
+// dryRunReport is the JSON output of --dry-run
+type dryRunReport struct {
+	Changes   []ops.Change   `json:"changes"`
+	Mutations []api.Mutation `json:"mutations"`
+}
+
+// runDryRun validates operations, including the viewer's permissions, and
+// prints the mutations they would send. client must come from
+// newMutationClient. ix may be nil, in which case each target is looked up.
+func runDryRun(ctx context.Context, cmd *cobra.Command, client *api.Client, ix *ops.Index, operations []ops.Operation) error {
+	format := dryRunFormat(cmd)
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid --dry-run format: %s\n\nValid formats: text, json", format)
+	}
+
+	report, err := dryRun(ctx, client, ix, operations)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(factory.IOStreams.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printDryRun(factory.IOStreams.Out, report)
+	return nil
+}
+
+// dryRun validates operations and runs them against a dry-run client,
+// returning the changes and the mutations recorded
+func dryRun(ctx context.Context, client *api.Client, ix *ops.Index, operations []ops.Operation) (dryRunReport, error) {
+	if ix == nil {
+		var err error
+		ix, err = lookupTargets(ctx, client, operations)
+		if err != nil {
+			return dryRunReport{}, err
+		}
+	}
+
+	changes, err := ix.CheckAll(operations)
+	if err != nil {
+		return dryRunReport{}, fmt.Errorf("dry run failed:\n%w", err)
+	}
+
+	executor := &ops.Executor{Client: client}
+	for _, result := range executor.Execute(ctx, changes) {
+		if result.Status == ops.StatusFailed {
+			return dryRunReport{}, fmt.Errorf("dry run failed: %s: %s", result.Operation, result.Error)
+		}
+	}
+
+	report := dryRunReport{Changes: changes, Mutations: client.Mutations()}
+	if report.Mutations == nil {
+		report.Mutations = []api.Mutation{}
+	}
+	return report, nil
+}
+
+// lookupTargets fetches the thread or comment of each operation into an
+// index for validation
+func lookupTargets(ctx context.Context, client *api.Client, operations []ops.Operation) (*ops.Index, error) {
+	ix := ops.NewIndex(&api.PullRequest{})
+	seen := make(map[string]bool)
+
+	for _, op := range operations {
+		if err := op.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		id := op.Target()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		switch op.Op {
+		case ops.Reply, ops.Resolve, ops.Unresolve:
+			thread, err := client.GetThread(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			ix.AddThread(thread)
+		default:
+			comment, err := client.GetComment(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			indexComment(ix, comment)
+		}
+	}
+
+	return ix, nil
+}
+
+// indexComment adds a comment fetched on its own to an index, located at
+// its file or in the conversation
+func indexComment(ix *ops.Index, comment *api.Comment) {
+	location := "conversation"
+	if comment.Path != "" {
+		location = comment.Path
+	}
+	ix.AddComment(comment, location)
+}
+
+// printDryRun prints the changes and the mutations that would be sent
+func printDryRun(w io.Writer, report dryRunReport) {
+	fmt.Fprintf(w, "Dry run: %d changes, nothing was sent\n\n", len(report.Changes))
+	printPlanChanges(w, report.Changes)
+
+	fmt.Fprintf(w, "\nMutations (%d):\n", len(report.Mutations))
+	for i, m := range report.Mutations {
+		fmt.Fprintf(w, "\n%d. %s\n", i+1, m.Name)
+		fmt.Fprintf(w, "   %s\n", m.Query)
+		fmt.Fprintf(w, "   variables: %s\n", m.Variables)
+	}
+}
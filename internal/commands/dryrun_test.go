@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+)
+
+func TestDryRun(t *testing.T) {
+	thread := `{"data":{"node":{"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,
+		"viewerCanResolve":true,"viewerCanReply":true,"comments":{"nodes":[{"id":"PRRC_1","author":{"login":"reviewer"}}]}}}}`
+	comment := `{"data":{"node":{"id":"PRRC_1","path":"main.go","viewerCanReact":true,
+		"reactionGroups":[{"content":"THUMBS_UP","users":{"totalCount":1},"viewerHasReacted":true}]}}}`
+
+	gh := newFakeGitHub(t, map[string]string{"GetThread": thread, "GetComment": comment})
+	client, err := api.NewDryRunClientWithOptions(gh.clientOptions())
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	report, err := dryRun(context.Background(), client, nil, []ops.Operation{
+		{Op: ops.Reply, Thread: "PRRT_a", Body: "Fixed"},
+		{Op: ops.React, Comment: "PRRC_1", Reaction: "THUMBS_UP"},
+		{Op: ops.Resolve, Thread: "PRRT_a"},
+	})
+	if err != nil {
+		t.Fatalf("dryRun() error = %v", err)
+	}
+
+	if len(report.Changes) != 3 {
+		t.Fatalf("got %d changes, want 3", len(report.Changes))
+	}
+	if !report.Changes[1].NoOp {
+		t.Error("react should be a no-op when already reacted")
+	}
+
+	// The no-op reaction sends nothing
+	var names []string
+	for _, m := range report.Mutations {
+		names = append(names, m.Name)
+	}
+	if got := strings.Join(names, ","); got != "AddReply,ResolveThread" {
+		t.Errorf("mutations = %s, want AddReply,ResolveThread", got)
+	}
+	if got := string(report.Mutations[0].Variables); got != `{"input":{"pullRequestReviewThreadId":"PRRT_a","body":"Fixed"}}` {
+		t.Errorf("variables = %s", got)
+	}
+
+	var buf bytes.Buffer
+	printDryRun(&buf, report)
+	for _, want := range []string{"nothing was sent", "= react", "Mutations (2)", "mutation ResolveThread("} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDryRunPermissions(t *testing.T) {
+	thread := `{"data":{"node":{"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"viewerCanResolve":false}}}`
+
+	gh := newFakeGitHub(t, map[string]string{"GetThread": thread})
+	client, err := api.NewDryRunClientWithOptions(gh.clientOptions())
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = dryRun(context.Background(), client, nil, []ops.Operation{{Op: ops.Resolve, Thread: "PRRT_a"}})
+	if err == nil || !strings.Contains(err.Error(), "you cannot resolve PRRT_a") {
+		t.Errorf("dryRun() error = %v, want permission error", err)
+	}
+	if len(client.Mutations()) != 0 {
+		t.Error("no mutations should be recorded after a failed check")
+	}
+}
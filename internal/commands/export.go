@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hamishmorgan/gh-talk/internal/format"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a PR conversation as a document",
+	Long: `Export the full conversation on a pull request as a self-contained
+document, for archiving or attaching to a design record.
+
+The export includes the top-level PR comments followed by every review
+thread (file, line, diff hunk, comments with authors, timestamps and
+reactions, and resolution state).
+
+Hidden (minimized) comments are marked with their reason. Use
+--redact-hidden to leave their bodies out.
+
+Examples:
+  # Markdown for the current PR
+  gh talk export
+
+  # HTML file for a specific PR
+  gh talk export --pr 123 --format html -o pr-123.html
+
+  # JSON without hidden comment bodies
+  gh talk export --format json --redact-hidden`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().String("format", "markdown", "Output format (markdown, html, json)")
+	exportCmd.Flags().StringP("output", "o", "", "Write to file instead of stdout")
+	exportCmd.Flags().Bool("redact-hidden", false, "Omit the bodies of hidden comments")
+	addSnapshotFlag(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	formatName, _ := cmd.Flags().GetString("format")
+	render, ok := format.Renderers[formatName]
+	if !ok {
+		return fmt.Errorf("unknown format: %s\n\nValid formats: markdown, html, json", formatName)
+	}
+
+	pr, err := fetchPullRequest(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	redact, _ := cmd.Flags().GetBool("redact-hidden")
+	opts := format.Options{RedactHidden: redact}
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return render(factory.IOStreams.Out, pr, opts)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", output, err)
+	}
+	if err := render(f, pr, opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", output, err)
+	}
+
+	fmt.Fprintf(factory.IOStreams.ErrOut, "✓ Exported %s#%d to %s\n", pr.Repository, pr.Number, output)
+	return nil
+}
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	gh "github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/config"
+)
+
+// IOStreams are the input and outputs commands read and write
+type IOStreams struct {
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+
+	// IsTerminal reports whether Out is a terminal; Width is its width in
+	// columns, or 0 when unknown
+	IsTerminal bool
+	Width      int
+}
+
+// systemIOStreams returns the process's standard streams
+func systemIOStreams() *IOStreams {
+	terminal := term.FromEnv()
+	width, _, _ := terminal.Size()
+	return &IOStreams{
+		In:         os.Stdin,
+		Out:        terminal.Out(),
+		ErrOut:     terminal.ErrOut(),
+		IsTerminal: terminal.IsTerminalOutput(),
+		Width:      width,
+	}
+}
+
+// Prompter asks the user questions. prompter.Prompter from go-gh
+// implements it.
+type Prompter interface {
+	Select(prompt, defaultValue string, options []string) (int, error)
+	MultiSelect(prompt string, defaultValues, options []string) ([]int, error)
+	Input(prompt, defaultValue string) (string, error)
+	Confirm(prompt string, defaultValue bool) (bool, error)
+}
+
+// Factory holds the dependencies commands share, so they can be replaced
+// with fakes in tests
+type Factory struct {
+	IOStreams *IOStreams
+
+	// Client creates an API client for a host, "" meaning the default
+	// host; DryRunClient creates one that records mutations instead of
+	// sending them
+	Client       func(host string) (*api.Client, error)
+	DryRunClient func(host string) (*api.Client, error)
+
+	// Config loads the gh-talk config file
+	Config func() (*config.Config, error)
+
+	Prompter func() Prompter
+
+	// Git runs git and returns trimmed stdout; GH runs gh and returns stdout
+	Git func(args ...string) (string, error)
+	GH  func(args ...string) ([]byte, error)
+
+	// BaseRepo returns the repository of the current directory
+	BaseRepo func() (repository.Repository, error)
+}
+
+// NewFactory returns a factory talking to GitHub, the terminal and local
+// git and gh executables
+func NewFactory() *Factory {
+	f := &Factory{
+		IOStreams:    systemIOStreams(),
+		Client:       api.NewClientForHost,
+		DryRunClient: api.NewDryRunClientForHost,
+		Config:       config.Load,
+		Git:          execGit,
+		GH:           execGH,
+		BaseRepo:     repository.Current,
+	}
+	f.Prompter = func() Prompter {
+		return newPrompter(f.IOStreams)
+	}
+	return f
+}
+
+// newPrompter returns a prompter on ios. go-gh prompts need file streams,
+// so any other streams get a prompter that refuses to ask.
+func newPrompter(ios *IOStreams) Prompter {
+	in, inFile := ios.In.(prompter.FileReader)
+	out, outFile := ios.Out.(prompter.FileWriter)
+	errOut, errFile := ios.ErrOut.(prompter.FileWriter)
+	if !inFile || !outFile || !errFile {
+		return noPrompter{}
+	}
+	return prompter.New(in, out, errOut)
+}
+
+// noPrompter fails every prompt
+type noPrompter struct{}
+
+var errNoPrompt = errors.New("cannot prompt without a terminal")
+
+func (noPrompter) Select(string, string, []string) (int, error)          { return 0, errNoPrompt }
+func (noPrompter) MultiSelect(string, []string, []string) ([]int, error) { return nil, errNoPrompt }
+func (noPrompter) Input(string, string) (string, error)                  { return "", errNoPrompt }
+func (noPrompter) Confirm(string, bool) (bool, error)                    { return false, errNoPrompt }
+
+// factory is used by all commands
+var factory = NewFactory()
+
+// execGit runs git with the given arguments and returns trimmed stdout
+func execGit(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// execGH runs gh with the given arguments and returns stdout
+func execGH(args ...string) ([]byte, error) {
+	stdout, _, err := gh.Exec(args...)
+	if err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
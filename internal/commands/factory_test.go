@@ -0,0 +1,347 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// fakeGitHub is a GraphQL server answering each operation with a canned
+// response and recording the requests it receives
+type fakeGitHub struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]string
+	requests  []fakeRequest
+}
+
+// fakeRequest is a GraphQL request received by fakeGitHub
+type fakeRequest struct {
+	Operation string
+	Query     string
+	Variables map[string]interface{}
+}
+
+// newFakeGitHub starts a server answering operations by name, e.g.
+// {"ListThreads": `{"data":...}`}
+func newFakeGitHub(t *testing.T, responses map[string]string) *fakeGitHub {
+	t.Helper()
+
+	f := &fakeGitHub{responses: maps.Clone(responses)}
+	f.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		name := graphQLOperation(payload.Query)
+		f.mu.Lock()
+		f.requests = append(f.requests, fakeRequest{Operation: name, Query: payload.Query, Variables: payload.Variables})
+		response, ok := f.responses[name]
+		f.mu.Unlock()
+
+		if !ok {
+			response = `{"errors":[{"message":"unexpected operation ` + name + `"}]}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// respond changes the response to an operation, e.g. to show a new state
+// to the next poll
+func (f *fakeGitHub) respond(operation, response string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[operation] = response
+}
+
+// operations returns the names of the operations received, in order
+func (f *fakeGitHub) operations() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for _, r := range f.requests {
+		names = append(names, r.Operation)
+	}
+	return names
+}
+
+func (f *fakeGitHub) clientOptions() ghapi.ClientOptions {
+	return ghapi.ClientOptions{
+		Host:         strings.TrimPrefix(f.server.URL, "https://"),
+		AuthToken:    "test-token",
+		Transport:    f.server.Client().Transport,
+		LogIgnoreEnv: true,
+	}
+}
+
+// graphQLOperation returns the operation name of a query, e.g. "ListThreads"
+func graphQLOperation(query string) string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return r == ' ' || r == '(' || r == '{'
+	})
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// fakePrompter answers prompts with fixed values. Inputs are answered by
+// prompt text, with the default value for other prompts.
+type fakePrompter struct {
+	selected int
+	multi    []int
+	inputs   map[string]string
+	confirm  bool
+}
+
+func (p *fakePrompter) Select(string, string, []string) (int, error) {
+	return p.selected, nil
+}
+
+func (p *fakePrompter) MultiSelect(string, []string, []string) ([]int, error) {
+	return p.multi, nil
+}
+
+func (p *fakePrompter) Input(prompt, defaultValue string) (string, error) {
+	if value, ok := p.inputs[prompt]; ok {
+		return value, nil
+	}
+	return defaultValue, nil
+}
+
+func (p *fakePrompter) Confirm(string, bool) (bool, error) {
+	return p.confirm, nil
+}
+
+// newTestFactory returns a factory talking to a fake GitHub, with
+// captured output, repository owner/repo and PR 1 for the current branch
+func newTestFactory(t *testing.T, gh *fakeGitHub) (*Factory, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	prompter := &fakePrompter{confirm: true}
+	return &Factory{
+		IOStreams: &IOStreams{In: strings.NewReader(""), Out: out, ErrOut: errOut},
+		Client: func(string) (*api.Client, error) {
+			return api.NewClientWithOptions(gh.clientOptions())
+		},
+		DryRunClient: func(string) (*api.Client, error) {
+			return api.NewDryRunClientWithOptions(gh.clientOptions())
+		},
+		Config: func() (*config.Config, error) {
+			return &config.Config{}, nil
+		},
+		Prompter: func() Prompter { return prompter },
+		Git: func(args ...string) (string, error) {
+			return "", errors.New("git is not available in tests")
+		},
+		GH: func(args ...string) ([]byte, error) {
+			return []byte(`{"number":1}`), nil
+		},
+		BaseRepo: func() (repository.Repository, error) {
+			return repository.Repository{Host: "github.com", Owner: "owner", Name: "repo"}, nil
+		},
+	}, out, errOut
+}
+
+// runCommand runs gh-talk with args against f, starting from default
+// flag values
+func runCommand(t *testing.T, f *Factory, args ...string) error {
+	t.Helper()
+
+	saved := factory
+	factory = f
+	t.Cleanup(func() { factory = saved })
+
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(f.IOStreams.Out)
+	rootCmd.SetErr(f.IOStreams.ErrOut)
+	_, err := rootCmd.ExecuteC()
+	return err
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// since commands are package variables shared by all tests
+func resetFlags(cmd *cobra.Command) {
+	reset := func(fl *pflag.Flag) {
+		if slice, ok := fl.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(nil)
+		} else {
+			_ = fl.Value.Set(fl.DefValue)
+		}
+		fl.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// assertGolden compares got with testdata/<name>.golden, rewriting the
+// file instead when run with -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+const goldenThreads = `{"data":{"repository":{"pullRequest":{"number":1,"title":"Add widgets","headRefOid":"abc1234","reviewThreads":{"nodes":[
+  {"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"comments":{"nodes":[
+    {"id":"PRRC_1","databaseId":101,"body":"Use a constant","createdAt":"2024-01-02T03:04:05Z","author":{"login":"reviewer"},"viewerCanReact":true,"reactionGroups":[
+      {"content":"THUMBS_UP","users":{"totalCount":1},"viewerHasReacted":false}
+    ]},
+    {"id":"PRRC_2","databaseId":102,"body":"Done","createdAt":"2024-01-02T04:04:05Z","author":{"login":"author"},"viewerCanReact":true,"reactionGroups":[]}
+  ]}},
+  {"id":"PRRT_b","isResolved":true,"path":"util.go","line":3,"resolvedBy":{"login":"author"},"comments":{"nodes":[
+    {"id":"PRRC_3","databaseId":103,"body":"Typo","createdAt":"2024-01-03T03:04:05Z","author":{"login":"reviewer"},"viewerCanReact":true,"reactionGroups":[]}
+  ]}}
+]}}}}}`
+
+const goldenThread = `{"data":{"node":{"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"viewerCanResolve":true,"viewerCanReply":true,"comments":{"nodes":[
+  {"id":"PRRC_1","databaseId":101,"body":"Use a constant","author":{"login":"reviewer"}}
+]}}}}`
+
+const goldenComment = `{"data":{"node":{"id":"PRRC_1","databaseId":101,"body":"Use a constant","path":"main.go","author":{"login":"reviewer"},"viewerCanReact":true,"reactionGroups":[]}}}`
+
+func TestCommandsGolden(t *testing.T) {
+	responses := map[string]string{
+		"ListThreads":       goldenThreads,
+		"GetThread":         goldenThread,
+		"GetComment":        goldenComment,
+		"ResolveThread":     `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":true,"resolvedBy":{"login":"author"}}}}}`,
+		"UnresolveThread":   `{"data":{"unresolveReviewThread":{"thread":{"id":"PRRT_b","isResolved":false,"resolvedBy":null}}}}`,
+		"AddReply":          `{"data":{"addPullRequestReviewThreadReply":{"comment":{"id":"PRRC_9","databaseId":109,"url":"https://github.com/owner/repo/pull/1#discussion_r109","body":"Fixed","createdAt":"2024-01-04T03:04:05Z","author":{"login":"author"}}}}}`,
+		"AddReaction":       `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"THUMBS_UP"},"subject":{"id":"PRRC_1"}}}}`,
+		"MinimizeComment":   `{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true,"minimizedReason":"OUTDATED"}}}}`,
+		"UnminimizeComment": `{"data":{"unminimizeComment":{"unminimizedComment":{"isMinimized":false,"minimizedReason":""}}}}`,
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		operations []string
+	}{
+		{"list_threads_tsv", []string{"list", "threads", "--repo", "owner/repo", "--pr", "1"}, []string{"ListThreads"}},
+		{"list_threads_json", []string{"list", "threads", "--pr", "1", "--json", "id,isResolved,path"}, []string{"ListThreads"}},
+		{"resolve_json", []string{"resolve", "PRRT_a", "--json"}, []string{"ResolveThread"}},
+		{"resolve", []string{"resolve", "PRRT_a"}, []string{"ResolveThread"}},
+		{"unresolve", []string{"unresolve", "PRRT_b"}, []string{"UnresolveThread"}},
+		{"reply_json", []string{"reply", "PRRT_a", "Fixed", "--json"}, []string{"AddReply"}},
+		{"react", []string{"react", "PRRC_1", "👍"}, []string{"GetComment", "AddReaction"}},
+		{"hide", []string{"hide", "PRRC_1", "--reason", "outdated"}, []string{"MinimizeComment"}},
+		{"unhide_json", []string{"unhide", "PRRC_1", "--json"}, []string{"UnminimizeComment"}},
+		{"resolve_dry_run", []string{"resolve", "PRRT_a", "--dry-run"}, []string{"GetThread"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, responses)
+			f, out, _ := newTestFactory(t, gh)
+
+			if err := runCommand(t, f, tt.args...); err != nil {
+				t.Fatalf("gh talk %s: %v", strings.Join(tt.args, " "), err)
+			}
+
+			assertGolden(t, tt.name, out.String())
+
+			if got := strings.Join(gh.operations(), ","); got != strings.Join(tt.operations, ",") {
+				t.Errorf("operations = %s, want %s", got, strings.Join(tt.operations, ","))
+			}
+		})
+	}
+}
+
+func TestRunCommandResetsFlags(t *testing.T) {
+	gh := newFakeGitHub(t, map[string]string{"ResolveThread": `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":true}}}}`})
+
+	f, _, _ := newTestFactory(t, gh)
+	if err := runCommand(t, f, "resolve", "PRRT_a", "--json"); err != nil {
+		t.Fatal(err)
+	}
+
+	f, out, _ := newTestFactory(t, gh)
+	if err := runCommand(t, f, "resolve", "PRRT_a"); err != nil {
+		t.Fatal(err)
+	}
+	if json.Valid(out.Bytes()) {
+		t.Errorf("--json carried over from the previous run: %q", out.String())
+	}
+}
+
+func TestNewPrompter(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	tests := []struct {
+		name     string
+		ios      *IOStreams
+		wantTerm bool
+	}{
+		{"files", &IOStreams{In: devNull, Out: os.Stdout, ErrOut: os.Stderr}, true},
+		{"buffers", &IOStreams{In: strings.NewReader(""), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}, false},
+		{"redirected output", &IOStreams{In: devNull, Out: &bytes.Buffer{}, ErrOut: os.Stderr}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPrompter(tt.ios)
+			if _, refuses := p.(noPrompter); refuses == tt.wantTerm {
+				t.Errorf("newPrompter() = %T", p)
+			}
+			if !tt.wantTerm {
+				if _, err := p.Confirm("Continue?", false); !errors.Is(err, errNoPrompt) {
+					t.Errorf("Confirm() error = %v, want %v", err, errNoPrompt)
+				}
+			}
+		})
+	}
+}
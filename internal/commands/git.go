@@ -0,0 +1,57 @@
+package commands
+
+import "strings"
+
+// gitCommit is a commit read from the local git log
+type gitCommit struct {
+	SHA     string
+	Subject string
+	Message string
+}
+
+// runGit runs git with the given arguments and returns trimmed stdout
+func runGit(args ...string) (string, error) {
+	return factory.Git(args...)
+}
+
+// gitLog returns commits in the given revision range, oldest first
+func gitLog(revRange string) ([]gitCommit, error) {
+	// Unit/record separators keep multi-line messages intact
+	out, err := runGit("log", "--reverse", "--format=%H%x1f%s%x1f%B%x1e", revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGitLog(out), nil
+}
+
+// parseGitLog parses output of git log --format=%H%x1f%s%x1f%B%x1e
+func parseGitLog(out string) []gitCommit {
+	var commits []gitCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		commits = append(commits, gitCommit{
+			SHA:     fields[0],
+			Subject: fields[1],
+			Message: strings.TrimSpace(fields[2]),
+		})
+	}
+	return commits
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -3,10 +3,12 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
-	gh "github.com/cli/go-gh/v2"
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +24,7 @@ func getRepository(cmd *cobra.Command) (owner, name string, err error) {
 		return repo.Owner, repo.Name, nil
 	}
 
-	repo, err := repository.Current()
+	repo, err := factory.BaseRepo()
 	if err != nil {
 		return "", "", fmt.Errorf("could not determine repository\n\nRun this from a git repository or use --repo OWNER/REPO")
 	}
@@ -38,7 +40,7 @@ func getCurrentPR(cmd *cobra.Command) (int, error) {
 	}
 
 	// Try to get PR for current branch using gh
-	stdout, _, err := gh.Exec("pr", "view", "--json", "number")
+	stdout, err := factory.GH("pr", "view", "--json", "number")
 	if err != nil {
 		return 0, fmt.Errorf("no PR found for current branch\n\nUse --pr NUMBER to specify a PR")
 	}
@@ -46,7 +48,7 @@ func getCurrentPR(cmd *cobra.Command) (int, error) {
 	var result struct {
 		Number int `json:"number"`
 	}
-	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+	if err := json.Unmarshal(stdout, &result); err != nil {
 		return 0, fmt.Errorf("failed to parse PR number")
 	}
 
@@ -71,6 +73,64 @@ func parseThreadID(arg string) (string, error) {
 	return "", fmt.Errorf("invalid thread ID format: %s\n\nExpected format: PRRT_kwDOQN97u85gQeTN", arg)
 }
 
+// discussionURLPattern matches review comment URLs (...#discussion_r123)
+var discussionURLPattern = regexp.MustCompile(`^https?://\S+#discussion_r(\d+)$`)
+
+// handlePattern matches short thread handles (r123)
+var handlePattern = regexp.MustCompile(`^r(\d+)$`)
+
+// threadHandle returns the short handle for a thread.
+//
+// Handles use the database ID of the thread's first comment, the same
+// number GitHub puts in #discussion_r... URLs, so they stay stable and
+// unambiguous without any local caching.
+func threadHandle(t api.Thread) string {
+	if len(t.Comments) == 0 || t.Comments[0].DatabaseID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("r%d", t.Comments[0].DatabaseID)
+}
+
+// resolveThreadRef finds the thread a reference points to.
+//
+// Supported references are full thread IDs (PRRT_...), discussion URLs
+// (https://github.com/.../pull/1#discussion_r123) and short handles (r123).
+// URLs and handles match any comment in the thread.
+func resolveThreadRef(ref string, threads []api.Thread) (*api.Thread, error) {
+	if strings.HasPrefix(ref, "PRRT_") {
+		for i := range threads {
+			if threads[i].ID == ref {
+				return &threads[i], nil
+			}
+		}
+		return nil, fmt.Errorf("thread not found: %s", ref)
+	}
+
+	var digits string
+	if m := discussionURLPattern.FindStringSubmatch(ref); m != nil {
+		digits = m[1]
+	} else if m := handlePattern.FindStringSubmatch(ref); m != nil {
+		digits = m[1]
+	} else {
+		return nil, fmt.Errorf("invalid thread reference: %s\n\nSupported formats:\n  - Full ID: PRRT_kwDOQN97u85gQeTN\n  - URL: https://github.com/owner/repo/pull/123#discussion_r456\n  - Handle: r456", ref)
+	}
+
+	databaseID, err := strconv.Atoi(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid thread reference: %s", ref)
+	}
+
+	for i := range threads {
+		for _, c := range threads[i].Comments {
+			if c.DatabaseID == databaseID {
+				return &threads[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no thread contains comment %d (%s)", databaseID, ref)
+}
+
 // truncate truncates a string to maxLen with "..." if needed
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
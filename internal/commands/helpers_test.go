@@ -2,6 +2,8 @@ package commands
 
 import (
 	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
 )
 
 func TestParseThreadID(t *testing.T) {
@@ -125,3 +127,48 @@ func TestContentToEmoji(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveThreadRef(t *testing.T) {
+	threads := []api.Thread{
+		{
+			ID: "PRRT_kwDOQN97u85gQeTN",
+			Comments: []api.Comment{
+				{ID: "PRRC_kwDOQN97u86UHqK7", DatabaseID: 2485035707},
+				{ID: "PRRC_kwDOQN97u86UHqOo", DatabaseID: 2485035944},
+			},
+		},
+		{
+			ID: "PRRT_kwDOQN97u85gQecu",
+			Comments: []api.Comment{
+				{ID: "PRRC_kwDOQN97u86UHqXX", DatabaseID: 2485036000},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"full ID", "PRRT_kwDOQN97u85gQecu", "PRRT_kwDOQN97u85gQecu", false},
+		{"unknown full ID", "PRRT_missing", "", true},
+		{"discussion URL", "https://github.com/owner/repo/pull/1#discussion_r2485035707", "PRRT_kwDOQN97u85gQeTN", false},
+		{"URL of reply", "https://github.com/owner/repo/pull/1#discussion_r2485035944", "PRRT_kwDOQN97u85gQeTN", false},
+		{"short handle", "r2485036000", "PRRT_kwDOQN97u85gQecu", false},
+		{"unknown handle", "r1", "", true},
+		{"invalid", "abc", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveThreadRef(tt.ref, threads)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveThreadRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != tt.want {
+				t.Errorf("resolveThreadRef() = %v, want %v", got.ID, tt.want)
+			}
+		})
+	}
+}
@@ -3,19 +3,30 @@ package commands
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/format"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
 	"github.com/spf13/cobra"
 )
 
 var hideCmd = &cobra.Command{
-	Use:   "hide <comment-id...>",
+	Use:   "hide [comment-or-thread...]",
 	Short: "Minimize/hide comments",
 	Long: `Minimize (hide) one or more comments with a reason.
 
+Threads can be given instead of comments; --target chooses which of
+their comments are hidden (first by default).
+
+Instead of IDs, --author and --match select conversation and review
+comments on the pull request, by author login and a regular expression
+on the body. --keep-latest leaves the newest selected comment visible.
+
 Arguments:
-  comment-id...  One or more comment IDs (PRRC_... or IC_...)
+  comment-or-thread...  Comment IDs (PRRC_... or IC_...), thread IDs
+                        (PRRT_...), discussion URLs or handles (r123)
 
 Examples:
   # Hide single comment as spam
@@ -25,40 +36,85 @@ Examples:
   gh talk hide PRRC_aaa PRRC_bbb PRRC_ccc --reason resolved
 
   # Hide as off-topic
-  gh talk hide PRRC_kwDOQN97u86UHqK7 --reason off-topic`,
-	Args: cobra.MinimumNArgs(1),
+  gh talk hide PRRC_kwDOQN97u86UHqK7 --reason off-topic
+
+  # Hide your own replies in a thread as outdated
+  gh talk hide PRRT_kwDOQN97u85gQeTN --target mine --reason outdated
+
+  # Clean up a CI bot's reports, leaving the newest one
+  gh talk hide --author 'codecov[bot]' --match 'Coverage report' --keep-latest --reason outdated
+
+  # Check permissions and show the mutation without hiding
+  gh talk hide IC_kwDOQN97u87PVA8l --reason spam --dry-run`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runHide,
 }
 
 var unhideCmd = &cobra.Command{
-	Use:   "unhide <comment-id>",
-	Short: "Unhide a comment",
-	Long: `Unhide (unminimize) a previously hidden comment.
+	Use:   "unhide [comment-id...]",
+	Short: "Unhide comments",
+	Long: `Unhide (unminimize) one or more previously hidden comments.
+
+Without comment IDs, choose from the hidden comments on the pull
+request.
 
 Arguments:
-  comment-id  Comment ID (PRRC_... or IC_...)
+  comment-id...  Comment IDs (PRRC_... or IC_...), or omit for
+                 interactive selection
 
 Examples:
   # Unhide a comment
-  gh talk unhide IC_kwDOQN97u87PVA8l`,
-	Args: cobra.ExactArgs(1),
+  gh talk unhide IC_kwDOQN97u87PVA8l
+
+  # Unhide several comments
+  gh talk unhide IC_kwDOQN97u87PVA8l PRRC_kwDOQN97u86UHqK7
+
+  # Choose from the PR's hidden comments
+  gh talk unhide --pr 123`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runUnhide,
 }
 
 func init() {
 	hideCmd.Flags().String("reason", "off-topic", "Reason (spam, abuse, off-topic, outdated, duplicate, resolved)")
+	hideCmd.Flags().Bool("json", false, "Output the hidden comments as JSON")
+	addTargetFlag(hideCmd)
+	hideCmd.Flags().String("author", "", "Hide the PR's comments by this author")
+	hideCmd.Flags().String("match", "", "Hide the PR's comments whose body matches this regular expression")
+	hideCmd.Flags().Bool("keep-latest", false, "Leave the newest comment selected by --author or --match visible")
+	hideCmd.Flags().BoolP("yes", "y", false, "Skip confirmation when hiding comments selected by --author or --match")
+
+	unhideCmd.Flags().Bool("json", false, "Output the unhidden comments as JSON")
 }
 
 func runHide(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	commentIDs := args
+	author, _ := cmd.Flags().GetString("author")
+	match, _ := cmd.Flags().GetString("match")
+	keepLatest, _ := cmd.Flags().GetBool("keep-latest")
+	bulk := author != "" || match != ""
 
-	// Validate all comment IDs
-	for _, commentID := range commentIDs {
-		if !strings.HasPrefix(commentID, "PRRC_") && !strings.HasPrefix(commentID, "IC_") {
-			return fmt.Errorf("invalid comment ID %s - expected format: PRRC_ or IC_", commentID)
+	switch {
+	case bulk && len(args) > 0:
+		return fmt.Errorf("cannot combine comment or thread IDs with --author or --match")
+	case !bulk && len(args) == 0:
+		return fmt.Errorf("comment or thread required\n\nOr select the PR's comments with --author and --match")
+	case keepLatest && !bulk:
+		return fmt.Errorf("--keep-latest needs --author or --match")
+	}
+
+	if err := checkTargetRefs(args); err != nil {
+		return err
+	}
+
+	var pattern *regexp.Regexp
+	if match != "" {
+		p, err := regexp.Compile(match)
+		if err != nil {
+			return fmt.Errorf("invalid --match: %w", err)
 		}
+		pattern = p
 	}
 
 	// Parse reason
@@ -69,49 +125,266 @@ func runHide(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create client
-	client, err := api.NewClient()
+	client, err := newMutationClient(cmd)
 	if err != nil {
 		return err
 	}
 
+	var commentIDs []string
+	var ix *ops.Index
+	if bulk {
+		owner, name, err := getRepository(cmd)
+		if err != nil {
+			return err
+		}
+		prNum, err := getCurrentPR(cmd)
+		if err != nil {
+			return err
+		}
+		pr, err := client.ListPullRequestComments(ctx, owner, name, prNum)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range matchingComments(pr, author, pattern, keepLatest) {
+			commentIDs = append(commentIDs, c.ID)
+		}
+		if len(commentIDs) == 0 {
+			fmt.Fprintf(factory.IOStreams.ErrOut, "No visible comments to hide in %s#%d\n", pr.Repository, pr.Number)
+			return nil
+		}
+		ix = ops.NewIndex(pr)
+	} else {
+		targets, err := resolveTargetComments(ctx, cmd, client, args)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			commentIDs = append(commentIDs, target.ID)
+		}
+	}
+
+	operations := make([]ops.Operation, len(commentIDs))
+	for i, id := range commentIDs {
+		operations[i] = ops.Operation{Op: ops.Hide, Comment: id, Reason: classifier}
+	}
+
+	if dryRunFormat(cmd) != "" {
+		return runDryRun(ctx, cmd, client, ix, operations)
+	}
+
+	if bulk {
+		// Check every comment, including the viewer's permissions, before
+		// hiding any of them
+		if _, err := ix.CheckAll(operations); err != nil {
+			return err
+		}
+
+		skipConfirm, _ := cmd.Flags().GetBool("yes")
+		if !skipConfirm {
+			p := factory.Prompter()
+			confirmed, err := p.Confirm(fmt.Sprintf("Hide %d comments?", len(commentIDs)), false)
+			if err != nil || !confirmed {
+				return fmt.Errorf("cancelled")
+			}
+		}
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
 	// Hide each comment
+	hidden := make([]*jsonComment, 0, len(commentIDs))
 	for _, commentID := range commentIDs {
-		err = client.MinimizeComment(ctx, commentID, classifier)
+		comment, err := client.MinimizeComment(ctx, commentID, classifier)
 		if err != nil {
 			return fmt.Errorf("failed to hide %s: %w", commentID, err)
 		}
-		fmt.Printf("✓ Hidden comment %s (reason: %s)\n", commentID, strings.ToLower(classifier))
+		hidden = append(hidden, commentToJSON(comment))
+		if !jsonOutput {
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Hidden comment %s (reason: %s)\n", commentID, strings.ToLower(classifier))
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(hidden)
 	}
 
 	if len(commentIDs) > 1 {
-		fmt.Printf("\n✓ Hidden %d comments\n", len(commentIDs))
+		fmt.Fprintf(factory.IOStreams.Out, "\n✓ Hidden %d comments\n", len(commentIDs))
 	}
 
 	return nil
 }
 
+// matchingComments returns the visible conversation and review comments
+// of a pull request by author whose bodies match pattern; an empty author
+// or nil pattern matches any. With keepLatest, the newest match is left
+// out. Bot logins match with or without the "[bot]" suffix, since GraphQL
+// reports them without it.
+func matchingComments(pr *api.PullRequest, author string, pattern *regexp.Regexp, keepLatest bool) []api.Comment {
+	author = strings.TrimSuffix(author, "[bot]")
+
+	var matches []api.Comment
+	consider := func(c api.Comment) {
+		if c.IsMinimized {
+			return
+		}
+		if author != "" && !strings.EqualFold(strings.TrimSuffix(c.Author.Login, "[bot]"), author) {
+			return
+		}
+		if pattern != nil && !pattern.MatchString(c.Body) {
+			return
+		}
+		matches = append(matches, c)
+	}
+	for _, c := range pr.Comments {
+		consider(c)
+	}
+	for _, t := range pr.ReviewThreads {
+		for _, c := range t.Comments {
+			consider(c)
+		}
+	}
+
+	latest := -1
+	if keepLatest {
+		for i, c := range matches {
+			if latest < 0 || c.CreatedAt.After(matches[latest].CreatedAt) {
+				latest = i
+			}
+		}
+	}
+
+	visible := make([]api.Comment, 0, len(matches))
+	for i, c := range matches {
+		if i != latest {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
 func runUnhide(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	commentID := args[0]
-
-	// Validate comment ID
-	if !strings.HasPrefix(commentID, "PRRC_") && !strings.HasPrefix(commentID, "IC_") {
-		return fmt.Errorf("invalid comment ID %s - expected format: PRRC_ or IC_", commentID)
+	// Validate comment IDs
+	for _, id := range args {
+		if !isCommentID(id) {
+			return fmt.Errorf("invalid comment ID %s - expected format: PRRC_ or IC_", id)
+		}
 	}
 
 	// Create client
-	client, err := api.NewClient()
+	client, err := newMutationClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	// Unhide comment
-	err = client.UnminimizeComment(ctx, commentID)
-	if err != nil {
-		return err
+	commentIDs := args
+	var ix *ops.Index
+	if len(commentIDs) == 0 {
+		// Interactive selection
+		pr, ids, err := selectHiddenComments(ctx, cmd, client)
+		if err != nil {
+			return err
+		}
+		commentIDs = ids
+		ix = ops.NewIndex(pr)
+	}
+
+	if dryRunFormat(cmd) != "" {
+		operations := make([]ops.Operation, len(commentIDs))
+		for i, id := range commentIDs {
+			operations[i] = ops.Operation{Op: ops.Unhide, Comment: id}
+		}
+		return runDryRun(ctx, cmd, client, ix, operations)
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	// Unhide each comment
+	unhidden := make([]*jsonComment, 0, len(commentIDs))
+	for _, commentID := range commentIDs {
+		comment, err := client.UnminimizeComment(ctx, commentID)
+		if err != nil {
+			return fmt.Errorf("failed to unhide %s: %w", commentID, err)
+		}
+		unhidden = append(unhidden, commentToJSON(comment))
+		if !jsonOutput {
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Unhidden comment %s\n", commentID)
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(unhidden)
+	}
+
+	if len(commentIDs) > 1 {
+		fmt.Fprintf(factory.IOStreams.Out, "\n✓ Unhidden %d comments\n", len(commentIDs))
 	}
 
-	fmt.Printf("✓ Unhidden comment %s\n", commentID)
 	return nil
 }
+
+// selectHiddenComments prompts for hidden conversation and review comments
+// on the current pull request to unhide
+func selectHiddenComments(ctx context.Context, cmd *cobra.Command, client *api.Client) (*api.PullRequest, []string, error) {
+	owner, name, err := getRepository(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	prNum, err := getCurrentPR(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pr, err := client.GetPullRequest(ctx, owner, name, prNum)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hidden []api.Comment
+	for _, c := range pr.Comments {
+		if c.IsMinimized {
+			hidden = append(hidden, c)
+		}
+	}
+	for _, t := range pr.ReviewThreads {
+		for _, c := range t.Comments {
+			if c.IsMinimized {
+				hidden = append(hidden, c)
+			}
+		}
+	}
+
+	if len(hidden) == 0 {
+		return nil, nil, fmt.Errorf("no hidden comments found in %s#%d", pr.Repository, pr.Number)
+	}
+
+	// Build options
+	options := make([]string, len(hidden))
+	for i, c := range hidden {
+		where := "conversation"
+		if c.Path != "" {
+			where = c.Path
+		}
+		options[i] = fmt.Sprintf("@%s on %s (%s) - %s", c.Author.Login, where, format.HiddenReason(c), truncate(c.Body, 50))
+	}
+
+	p := factory.Prompter()
+	indices, err := p.MultiSelect("Select comments to unhide:", nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(indices) == 0 {
+		return nil, nil, fmt.Errorf("no comments selected")
+	}
+
+	ids := make([]string, len(indices))
+	for i, idx := range indices {
+		ids[i] = hidden[idx].ID
+	}
+
+	return pr, ids, nil
+}
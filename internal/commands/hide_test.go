@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestMatchingComments(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 1, 1, hour, 0, 0, 0, time.UTC) }
+	pr := &api.PullRequest{
+		Comments: []api.Comment{
+			{ID: "IC_1", Author: api.User{Login: "codecov"}, Body: "## Coverage report\n80%", CreatedAt: at(1)},
+			{ID: "IC_2", Author: api.User{Login: "alice"}, Body: "Coverage report looks fine", CreatedAt: at(2)},
+			{ID: "IC_3", Author: api.User{Login: "codecov"}, Body: "## Coverage report\n81%", CreatedAt: at(3), IsMinimized: true},
+			{ID: "IC_4", Author: api.User{Login: "codecov"}, Body: "Bundle size", CreatedAt: at(4)},
+			{ID: "IC_5", Author: api.User{Login: "codecov"}, Body: "## Coverage report\n82%", CreatedAt: at(6)},
+		},
+		ReviewThreads: []api.Thread{{Comments: []api.Comment{
+			{ID: "PRRC_1", Author: api.User{Login: "codecov"}, Body: "Coverage report: line not covered", CreatedAt: at(5)},
+		}}},
+	}
+
+	tests := []struct {
+		name       string
+		author     string
+		match      string
+		keepLatest bool
+		want       string
+	}{
+		{"author", "codecov[bot]", "", false, "IC_1,IC_4,IC_5,PRRC_1"},
+		{"author and match", "codecov[bot]", "Coverage report", false, "IC_1,IC_5,PRRC_1"},
+		{"keep latest", "codecov", "Coverage report", true, "IC_1,PRRC_1"},
+		{"keep latest skips hidden", "codecov", "Coverage report\n8[01]", true, ""},
+		{"match only", "", "^Coverage", false, "IC_2,PRRC_1"},
+		{"case-insensitive author", "CodeCov", "Bundle", false, "IC_4"},
+		{"no match", "dependabot", "", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pattern *regexp.Regexp
+			if tt.match != "" {
+				pattern = regexp.MustCompile(tt.match)
+			}
+			var ids []string
+			for _, c := range matchingComments(pr, tt.author, pattern, tt.keepLatest) {
+				ids = append(ids, c.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("matchingComments() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+const hidePullRequest = `{"data":{"repository":{"pullRequest":{"id":"PR_1","url":"https://github.com/owner/repo/pull/1",
+  "comments":{"nodes":[
+    {"id":"IC_1","body":"Coverage report: 80%","createdAt":"2024-01-01T01:00:00Z","author":{"login":"codecov"},"viewerCanMinimize":true},
+    {"id":"IC_2","body":"Looks good","createdAt":"2024-01-01T02:00:00Z","author":{"login":"reviewer"},"viewerCanMinimize":true},
+    {"id":"IC_3","body":"Coverage report: 82%","createdAt":"2024-01-01T03:00:00Z","author":{"login":"codecov"},"viewerCanMinimize":true}
+  ],"pageInfo":{"hasNextPage":false}}}}}}`
+
+func TestHideBulk(t *testing.T) {
+	responses := map[string]string{
+		"ListPullRequestComments": hidePullRequest,
+		"ListThreadComments":      `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}}}`,
+		"MinimizeComment":         `{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true,"minimizedReason":"OUTDATED"}}}}`,
+	}
+
+	t.Run("keep latest", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "hide", "--author", "codecov[bot]", "--match", "Coverage report", "--keep-latest", "--reason", "outdated"); err != nil {
+			t.Fatalf("hide --author: %v", err)
+		}
+		inputs := mutationInputs(gh, "MinimizeComment")
+		if len(inputs) != 1 || inputs[0]["subjectId"] != "IC_1" || inputs[0]["classifier"] != "OUTDATED" {
+			t.Errorf("MinimizeComment inputs = %v, want IC_1 as OUTDATED", inputs)
+		}
+		if !strings.Contains(out.String(), "✓ Hidden comment IC_1 (reason: outdated)") {
+			t.Errorf("output = %s", out.String())
+		}
+	})
+
+	t.Run("cannot hide", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		gh.respond("ListPullRequestComments", strings.Replace(hidePullRequest, `"author":{"login":"codecov"},"viewerCanMinimize":true`, `"author":{"login":"codecov"}`, 1))
+		f, _, _ := newTestFactory(t, gh)
+
+		err := runCommand(t, f, "hide", "--author", "codecov", "--yes")
+		if err == nil || !strings.Contains(err.Error(), "you cannot hide or unhide IC_1") {
+			t.Errorf("hide without permission: error = %v", err)
+		}
+		if n := len(mutationInputs(gh, "MinimizeComment")); n != 0 {
+			t.Errorf("hid %d comments without permission to hide them all", n)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTestFactory(t, gh)
+		f.Prompter = func() Prompter { return &fakePrompter{confirm: false} }
+
+		if err := runCommand(t, f, "hide", "--author", "codecov"); err == nil || err.Error() != "cancelled" {
+			t.Errorf("hide without confirmation: error = %v", err)
+		}
+		if n := len(mutationInputs(gh, "MinimizeComment")); n != 0 {
+			t.Errorf("hid %d comments after cancelling", n)
+		}
+	})
+
+	t.Run("nothing to hide", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, errOut := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "hide", "--author", "dependabot"); err != nil {
+			t.Fatalf("hide --author dependabot: %v", err)
+		}
+		if !strings.Contains(errOut.String(), "No visible comments to hide") {
+			t.Errorf("stderr = %s", errOut.String())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTestFactory(t, gh)
+
+		for _, args := range [][]string{
+			{"hide"},
+			{"hide", "IC_1", "--author", "codecov"},
+			{"hide", "IC_1", "--keep-latest"},
+			{"hide", "--match", "("},
+		} {
+			if err := runCommand(t, f, args...); err == nil {
+				t.Errorf("gh talk %s: error = nil", strings.Join(args, " "))
+			}
+		}
+		if len(gh.operations()) != 0 {
+			t.Errorf("operations = %v, want none", gh.operations())
+		}
+	})
+}
+
+func TestUnhide(t *testing.T) {
+	responses := map[string]string{
+		"ListThreads":       hiddenThreads,
+		"UnminimizeComment": `{"data":{"unminimizeComment":{"unminimizedComment":{"isMinimized":false}}}}`,
+	}
+
+	t.Run("multiple IDs", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "unhide", "IC_1", "PRRC_4"); err != nil {
+			t.Fatalf("unhide: %v", err)
+		}
+		var ids []string
+		for _, input := range mutationInputs(gh, "UnminimizeComment") {
+			ids = append(ids, input["subjectId"].(string))
+		}
+		if got := strings.Join(ids, ","); got != "IC_1,PRRC_4" {
+			t.Errorf("unhid %s, want IC_1,PRRC_4", got)
+		}
+		if !strings.Contains(out.String(), "✓ Unhidden 2 comments") {
+			t.Errorf("output = %s", out.String())
+		}
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTestFactory(t, gh)
+		// Hidden comments are offered in order: IC_1, PRRC_1, PRRC_4
+		f.Prompter = func() Prompter { return &fakePrompter{multi: []int{1, 2}} }
+
+		if err := runCommand(t, f, "unhide"); err != nil {
+			t.Fatalf("unhide: %v", err)
+		}
+		var ids []string
+		for _, input := range mutationInputs(gh, "UnminimizeComment") {
+			ids = append(ids, input["subjectId"].(string))
+		}
+		if got := strings.Join(ids, ","); got != "PRRC_1,PRRC_4" {
+			t.Errorf("unhid %s, want PRRC_1,PRRC_4", got)
+		}
+	})
+
+	t.Run("nothing hidden", func(t *testing.T) {
+		gh := newFakeGitHub(t, map[string]string{"ListThreads": goldenThreads})
+		f, _, _ := newTestFactory(t, gh)
+
+		err := runCommand(t, f, "unhide")
+		if err == nil || !strings.Contains(err.Error(), "no hidden comments") {
+			t.Errorf("unhide error = %v, want no hidden comments", err)
+		}
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "unhide", "IC_1", "PRRT_a"); err == nil {
+			t.Error("unhide PRRT_a: error = nil")
+		}
+		if len(gh.operations()) != 0 {
+			t.Errorf("operations = %v, want none", gh.operations())
+		}
+	})
+}
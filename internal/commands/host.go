@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// commandHost returns the GitHub host a command talks to, in order of
+// precedence: the host in --repo HOST/OWNER/REPO, the host of a URL
+// argument, GH_HOST, and the current repository's host. As in gh, an
+// explicit --repo OWNER/REPO means the default host rather than the
+// current repository's. "" means the default host.
+func commandHost(cmd *cobra.Command) string {
+	repoFlag, _ := cmd.Flags().GetString("repo")
+	if strings.Count(repoFlag, "/") == 2 {
+		if repo, err := repository.Parse(repoFlag); err == nil {
+			return repo.Host
+		}
+	}
+
+	for _, arg := range cmd.Flags().Args() {
+		if host := urlHost(arg); host != "" {
+			return host
+		}
+	}
+
+	if host := os.Getenv("GH_HOST"); host != "" {
+		return host
+	}
+
+	if repoFlag != "" {
+		return ""
+	}
+
+	if repo, err := factory.BaseRepo(); err == nil {
+		return repo.Host
+	}
+	return ""
+}
+
+// urlHost returns the host of an http(s) URL, or "" if s is not one
+func urlHost(s string) string {
+	if !strings.HasPrefix(s, "https://") && !strings.HasPrefix(s, "http://") {
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// newClient creates an API client for the command's host
+func newClient(cmd *cobra.Command) (*api.Client, error) {
+	return hostClient(factory.Client, commandHost(cmd))
+}
+
+// hostClient creates a client with create and limits it to the reactions
+// and hide reasons the host's config section allows
+func hostClient(create func(host string) (*api.Client, error), host string) (*api.Client, error) {
+	cfg, err := factory.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := create(host)
+	if err != nil {
+		return nil, err
+	}
+
+	hc := cfg.Host(host)
+	client.SetHostFeatures(api.HostFeatures{Reactions: hc.Reactions, Classifiers: hc.Classifiers})
+	return client, nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestCommandHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		ghHost   string
+		baseRepo string
+		want     string
+	}{
+		{"repo flag with host", []string{"--repo", "ghe.example.com/o/r", "https://other.example.com/o/r/pull/1#discussion_r1"}, "env.example.com", "github.com", "ghe.example.com"},
+		{"repo flag without host", []string{"--repo", "o/r"}, "", "base.example.com", ""},
+		{"repo flag without host and GH_HOST", []string{"--repo", "o/r"}, "env.example.com", "base.example.com", "env.example.com"},
+		{"URL argument", []string{"https://ghe.example.com/o/r/pull/1#discussion_r1"}, "env.example.com", "github.com", "ghe.example.com"},
+		{"GH_HOST", []string{"PRRT_a"}, "env.example.com", "github.com", "env.example.com"},
+		{"current repository", []string{"PRRT_a"}, "", "base.example.com", "base.example.com"},
+		{"no repository", nil, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_HOST", tt.ghHost)
+
+			f, _, _ := newTestFactory(t, newFakeGitHub(t, nil))
+			f.BaseRepo = func() (repository.Repository, error) {
+				if tt.baseRepo == "" {
+					return repository.Repository{}, errors.New("not a git repository")
+				}
+				return repository.Repository{Host: tt.baseRepo, Owner: "o", Name: "r"}, nil
+			}
+			saved := factory
+			factory = f
+			t.Cleanup(func() { factory = saved })
+
+			cmd := &cobra.Command{}
+			cmd.Flags().String("repo", "", "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := commandHost(cmd); got != tt.want {
+				t.Errorf("commandHost(%v) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostConfigLimitsFeatures(t *testing.T) {
+	gh := newFakeGitHub(t, map[string]string{
+		"GetComment":  goldenComment,
+		"AddReaction": `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"THUMBS_UP"},"subject":{"id":"PRRC_1"}}}}`,
+	})
+	f, _, _ := newTestFactory(t, gh)
+
+	var hosts []string
+	f.Client = func(host string) (*api.Client, error) {
+		hosts = append(hosts, host)
+		return api.NewClientWithOptions(gh.clientOptions())
+	}
+	f.Config = func() (*config.Config, error) {
+		return &config.Config{Hosts: map[string]config.HostConfig{
+			"ghe.example.com": {Reactions: []string{"THUMBS_UP", "THUMBS_DOWN"}},
+		}}, nil
+	}
+
+	err := runCommand(t, f, "react", "PRRC_1", "🚀", "--repo", "ghe.example.com/owner/repo")
+	if err == nil || !strings.Contains(err.Error(), "reaction ROCKET is not supported") || !strings.Contains(err.Error(), "Supported: THUMBS_UP, THUMBS_DOWN") {
+		t.Fatalf("react 🚀 error = %v, want unsupported reaction", err)
+	}
+	if len(gh.operations()) != 0 {
+		t.Errorf("operations = %v, want nothing sent", gh.operations())
+	}
+
+	if err := runCommand(t, f, "react", "PRRC_1", "👍", "--repo", "ghe.example.com/owner/repo"); err != nil {
+		t.Fatalf("react 👍 error = %v", err)
+	}
+	if strings.Join(hosts, ",") != "ghe.example.com,ghe.example.com" {
+		t.Errorf("clients created for hosts %v, want ghe.example.com", hosts)
+	}
+}
+
+func TestRepoFlagIgnoresCheckoutHost(t *testing.T) {
+	t.Setenv("GH_HOST", "")
+
+	gh := newFakeGitHub(t, map[string]string{"ListThreads": goldenThreads})
+	f, _, _ := newTestFactory(t, gh)
+	f.BaseRepo = func() (repository.Repository, error) {
+		return repository.Repository{Host: "ghe.example.com", Owner: "corp", Name: "app"}, nil
+	}
+	var hosts []string
+	f.Client = func(host string) (*api.Client, error) {
+		hosts = append(hosts, host)
+		return api.NewClientWithOptions(gh.clientOptions())
+	}
+
+	if err := runCommand(t, f, "list", "threads", "--repo", "cli/cli", "--pr", "1"); err != nil {
+		t.Fatalf("list --repo cli/cli: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0] != "" {
+		t.Errorf("clients created for hosts %q, want the default host", hosts)
+	}
+}
@@ -7,8 +7,8 @@ import (
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/tableprinter"
-	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/format"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +25,10 @@ var listThreadsCmd = &cobra.Command{
 By default, shows only unresolved threads. Use --all to see
 both resolved and unresolved threads.
 
+Threads whose first comment is hidden are left out unless
+--include-hidden is given. Hidden comments are shown collapsed, with
+the reason they were hidden.
+
 Examples:
   # List unresolved threads in current PR
   gh talk list threads
@@ -33,7 +37,22 @@ Examples:
   gh talk list threads --pr 123 --all
 
   # List threads on specific file
-  gh talk list threads --file src/api.go`,
+  gh talk list threads --file src/api.go
+
+  # List threads whose code changed after the comment was made
+  gh talk list threads --changed-since-comment
+
+  # List threads waiting on you
+  gh talk list threads --waiting-on me
+
+  # List threads with a comment alice reacted to
+  gh talk list threads --all --reacted-by alice
+
+  # List threads with hidden comments
+  gh talk list threads --all --only-hidden
+
+  # List threads from a saved snapshot, offline
+  gh talk list threads --all --from-snapshot review.json`,
 	RunE: runListThreads,
 }
 
@@ -46,6 +65,12 @@ func init() {
 	listThreadsCmd.Flags().Bool("all", false, "Show all threads")
 	listThreadsCmd.Flags().String("author", "", "Filter by author")
 	listThreadsCmd.Flags().String("file", "", "Filter by file path")
+	listThreadsCmd.Flags().String("reacted-by", "", "Filter by a user who reacted to a comment")
+	listThreadsCmd.Flags().Bool("changed-since-comment", false, "Show only threads whose lines changed after commenting")
+	listThreadsCmd.Flags().String("waiting-on", "", "Filter by whose turn it is (me, author, reviewer)")
+	listThreadsCmd.Flags().Bool("include-hidden", false, "Include threads whose first comment is hidden")
+	listThreadsCmd.Flags().Bool("only-hidden", false, "Show only threads with hidden comments")
+	addSnapshotFlag(listThreadsCmd)
 
 	// Output flags
 	listThreadsCmd.Flags().String("format", "", "Output format (table, json, tsv)")
@@ -53,40 +78,62 @@ func init() {
 
 	// Make resolution flags mutually exclusive
 	listThreadsCmd.MarkFlagsMutuallyExclusive("unresolved", "resolved", "all")
+	listThreadsCmd.MarkFlagsMutuallyExclusive("include-hidden", "only-hidden")
 }
 
 func runListThreads(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	// Get repository context
-	owner, name, err := getRepository(cmd)
+	// Fetch threads
+	pr, err := fetchPullRequest(ctx, cmd)
 	if err != nil {
 		return err
 	}
 
-	// Get PR number
-	prNum, err := getCurrentPR(cmd)
-	if err != nil {
-		return err
+	// Snapshots are read offline, so they keep the users listings fetch
+	reactedBy, _ := cmd.Flags().GetString("reacted-by")
+	if reactedBy != "" && fromSnapshot(cmd) == "" {
+		client, err := newClient(cmd)
+		if err != nil {
+			return err
+		}
+		if err := fetchMissingReactors(ctx, client, pr.ReviewThreads, reactedBy); err != nil {
+			return err
+		}
 	}
 
-	// Create API client
-	client, err := api.NewClient()
-	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
-	}
+	// Apply filters
+	threads := filterThreads(cmd, pr.ReviewThreads)
 
-	// Fetch threads
-	threads, err := client.ListThreads(ctx, owner, name, prNum)
-	if err != nil {
-		return err
+	changedOnly, _ := cmd.Flags().GetBool("changed-since-comment")
+	if changedOnly {
+		threads = filterChangedThreads(threads, newChangeDetector(pr.HeadRefOid))
 	}
 
-	// Apply filters
-	threads = filterThreads(cmd, threads)
+	waitingOn, _ := cmd.Flags().GetString("waiting-on")
+	if waitingOn != "" {
+		viewer := ""
+		if waitingOn == "me" {
+			if fromSnapshot(cmd) != "" {
+				return fmt.Errorf("--waiting-on me needs to look up the current user\n\nUse --waiting-on author or reviewer with --from-snapshot")
+			}
+			client, err := newClient(cmd)
+			if err != nil {
+				return err
+			}
+			viewer, err = client.CurrentUser(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		threads, err = filterWaitingOn(threads, waitingOn, pr.Author.Login, viewer)
+		if err != nil {
+			return err
+		}
+	}
 
 	if len(threads) == 0 {
-		fmt.Printf("No threads found in %s/%s#%d\n", owner, name, prNum)
+		fmt.Fprintf(factory.IOStreams.Out, "No threads found in %s#%d\n", pr.Repository, pr.Number)
 		return nil
 	}
 
@@ -100,6 +147,9 @@ func filterThreads(cmd *cobra.Command, threads []api.Thread) []api.Thread {
 	all, _ := cmd.Flags().GetBool("all")
 	author, _ := cmd.Flags().GetString("author")
 	file, _ := cmd.Flags().GetString("file")
+	reactedBy, _ := cmd.Flags().GetString("reacted-by")
+	includeHidden, _ := cmd.Flags().GetBool("include-hidden")
+	onlyHidden, _ := cmd.Flags().GetBool("only-hidden")
 
 	// Default to unresolved if no filter specified
 	if !unresolved && !resolved && !all {
@@ -117,17 +167,8 @@ func filterThreads(cmd *cobra.Command, threads []api.Thread) []api.Thread {
 		}
 
 		// Author filter
-		if author != "" {
-			hasAuthor := false
-			for _, c := range t.Comments {
-				if c.Author.Login == author {
-					hasAuthor = true
-					break
-				}
-			}
-			if !hasAuthor {
-				continue
-			}
+		if author != "" && !threadHasAuthor(t, author) {
+			continue
 		}
 
 		// File filter
@@ -135,16 +176,176 @@ func filterThreads(cmd *cobra.Command, threads []api.Thread) []api.Thread {
 			continue
 		}
 
+		// Reaction filter
+		if reactedBy != "" && !threadHasReactionBy(t, reactedBy) {
+			continue
+		}
+
+		// Hidden filter
+		if onlyHidden && hiddenComments(t) == 0 {
+			continue
+		}
+		if !includeHidden && !onlyHidden && threadIsHidden(t) {
+			continue
+		}
+
 		filtered = append(filtered, t)
 	}
 
 	return filtered
 }
 
+// filterChangedThreads keeps threads whose lines changed after commenting
+func filterChangedThreads(threads []api.Thread, detector *changeDetector) []api.Thread {
+	changed := make([]api.Thread, 0, len(threads))
+	for _, t := range threads {
+		if detector.changed(t) {
+			changed = append(changed, t)
+		}
+	}
+	return changed
+}
+
+// filterWaitingOn keeps threads waiting on the given party.
+//
+// "me" means threads waiting on the viewer: as PR author, threads waiting
+// on the author; as a reviewer, threads waiting on a reviewer that the
+// viewer has commented in.
+func filterWaitingOn(threads []api.Thread, who, prAuthor, viewer string) ([]api.Thread, error) {
+	if who != "me" && who != api.WaitingOnAuthor && who != api.WaitingOnReviewer {
+		return nil, fmt.Errorf("invalid --waiting-on value: %s\n\nValid values: me, author, reviewer", who)
+	}
+
+	filtered := make([]api.Thread, 0, len(threads))
+	for _, t := range threads {
+		switch {
+		case who != "me":
+			if t.WaitingOn != who {
+				continue
+			}
+		case viewer == prAuthor:
+			if t.WaitingOn != api.WaitingOnAuthor {
+				continue
+			}
+		default:
+			if t.WaitingOn != api.WaitingOnReviewer || !threadHasAuthor(t, viewer) {
+				continue
+			}
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered, nil
+}
+
+// threadHasAuthor reports whether login wrote any comment in the thread
+func threadHasAuthor(t api.Thread, login string) bool {
+	for _, c := range t.Comments {
+		if c.Author.Login == login {
+			return true
+		}
+	}
+	return false
+}
+
+// threadHasReactionBy reports whether login reacted to any comment in the
+// thread, among the users fetched for each reaction
+func threadHasReactionBy(t api.Thread, login string) bool {
+	login = strings.TrimPrefix(login, "@")
+	for _, c := range t.Comments {
+		for _, rg := range c.ReactionGroups {
+			for _, u := range rg.Users.Nodes {
+				if strings.EqualFold(u.Login, login) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// fetchMissingReactors completes the users of reactions that listings
+// cut short, on threads login is not yet known to have reacted in, so
+// --reacted-by does not miss popular reactions
+func fetchMissingReactors(ctx context.Context, client *api.Client, threads []api.Thread, login string) error {
+nextThread:
+	for i := range threads {
+		t := &threads[i]
+		for j := range t.Comments {
+			c := &t.Comments[j]
+			for k := range c.ReactionGroups {
+				if threadHasReactionBy(*t, login) {
+					continue nextThread
+				}
+				rg := &c.ReactionGroups[k]
+				if rg.Users.TotalCount <= len(rg.Users.Nodes) {
+					continue
+				}
+				users, err := client.ListReactors(ctx, c.ID, rg.Content)
+				if err != nil {
+					return err
+				}
+				rg.Users.Nodes = users
+			}
+		}
+	}
+	return nil
+}
+
+// threadIsHidden reports whether a thread's first comment is hidden, which
+// leaves the thread out of listings by default
+func threadIsHidden(t api.Thread) bool {
+	return len(t.Comments) > 0 && t.Comments[0].IsMinimized
+}
+
+// hiddenComments counts the hidden comments in a thread
+func hiddenComments(t api.Thread) int {
+	n := 0
+	for _, c := range t.Comments {
+		if c.IsMinimized {
+			n++
+		}
+	}
+	return n
+}
+
+// commentPreview is the body shown for a comment in listings; hidden
+// comments are collapsed to their reason
+func commentPreview(c api.Comment) string {
+	if c.IsMinimized {
+		return fmt.Sprintf("Hidden (%s)", format.HiddenReason(c))
+	}
+	return c.Body
+}
+
+// threadPreview is the preview of a thread's first comment
+func threadPreview(t api.Thread) string {
+	if len(t.Comments) == 0 {
+		return ""
+	}
+	return commentPreview(t.Comments[0])
+}
+
+// formatCommentCount formats a thread's comment count for tables, noting
+// hidden comments
+func formatCommentCount(t api.Thread) string {
+	if hidden := hiddenComments(t); hidden > 0 {
+		return fmt.Sprintf("%d (%d hidden)", len(t.Comments), hidden)
+	}
+	return fmt.Sprintf("%d", len(t.Comments))
+}
+
+// formatWaitingOn formats a thread's WaitingOn for tables
+func formatWaitingOn(t api.Thread) string {
+	if t.Acknowledged {
+		return t.WaitingOn + " 👀"
+	}
+	return t.WaitingOn
+}
+
 func outputThreads(cmd *cobra.Command, threads []api.Thread) error {
 	format, _ := cmd.Flags().GetString("format")
 	jsonFields, _ := cmd.Flags().GetStringSlice("json")
-	terminal := term.FromEnv()
+	ios := factory.IOStreams
 
 	// If --json flag specified, use JSON format
 	if len(jsonFields) > 0 {
@@ -153,7 +354,7 @@ func outputThreads(cmd *cobra.Command, threads []api.Thread) error {
 
 	// Auto-detect format if not specified
 	if format == "" {
-		if terminal.IsTerminalOutput() {
+		if ios.IsTerminal {
 			format = "table"
 		} else {
 			format = "tsv"
@@ -162,24 +363,24 @@ func outputThreads(cmd *cobra.Command, threads []api.Thread) error {
 
 	switch format {
 	case "table":
-		return outputThreadsTable(threads, terminal)
+		return outputThreadsTable(threads, ios)
 	case "tsv":
-		return outputThreadsTSV(threads, terminal)
+		return outputThreadsTSV(threads, ios)
 	case "json":
-		return outputThreadsJSON(threads, terminal)
+		return outputThreadsJSON(threads, ios)
 	default:
 		return fmt.Errorf("unknown format: %s\n\nValid formats: table, json, tsv", format)
 	}
 }
 
-func outputThreadsTable(threads []api.Thread, terminal term.Term) error {
-	width, _, _ := terminal.Size()
-	t := tableprinter.New(terminal.Out(), true, width)
+func outputThreadsTable(threads []api.Thread, ios *IOStreams) error {
+	t := tableprinter.New(ios.Out, true, ios.Width)
 
 	// Header
 	t.AddField("ID")
 	t.AddField("File:Line")
 	t.AddField("Status")
+	t.AddField("Waiting On")
 	t.AddField("Comments")
 	t.AddField("Reactions")
 	t.AddField("Preview")
@@ -204,19 +405,18 @@ func outputThreadsTable(threads []api.Thread, terminal term.Term) error {
 		}
 		t.AddField(status)
 
+		// Whose turn
+		t.AddField(formatWaitingOn(thread))
+
 		// Comment count
-		t.AddField(fmt.Sprintf("%d", len(thread.Comments)))
+		t.AddField(formatCommentCount(thread))
 
 		// Reactions (show non-zero only)
 		reactions := formatReactions(thread)
 		t.AddField(reactions)
 
 		// Preview (first comment body, truncated)
-		preview := ""
-		if len(thread.Comments) > 0 {
-			preview = truncate(thread.Comments[0].Body, 50)
-		}
-		t.AddField(preview)
+		t.AddField(truncate(threadPreview(thread), 50))
 
 		t.EndRow()
 	}
@@ -224,8 +424,8 @@ func outputThreadsTable(threads []api.Thread, terminal term.Term) error {
 	return t.Render()
 }
 
-func outputThreadsTSV(threads []api.Thread, terminal term.Term) error {
-	t := tableprinter.New(terminal.Out(), false, 0)
+func outputThreadsTSV(threads []api.Thread, ios *IOStreams) error {
+	t := tableprinter.New(ios.Out, false, 0)
 
 	// Header
 	t.AddField("ID")
@@ -234,6 +434,7 @@ func outputThreadsTSV(threads []api.Thread, terminal term.Term) error {
 	t.AddField("IsResolved")
 	t.AddField("CommentCount")
 	t.AddField("Preview")
+	t.AddField("WaitingOn")
 	t.EndRow()
 
 	// Rows
@@ -243,44 +444,47 @@ func outputThreadsTSV(threads []api.Thread, terminal term.Term) error {
 		t.AddField(fmt.Sprintf("%d", thread.Line))
 		t.AddField(fmt.Sprintf("%t", thread.IsResolved))
 		t.AddField(fmt.Sprintf("%d", len(thread.Comments)))
-
-		preview := ""
-		if len(thread.Comments) > 0 {
-			preview = thread.Comments[0].Body
-		}
-		t.AddField(preview)
+		t.AddField(threadPreview(thread))
+		t.AddField(thread.WaitingOn)
 		t.EndRow()
 	}
 
 	return t.Render()
 }
 
-func outputThreadsJSON(threads []api.Thread, terminal term.Term) error {
-	type JSONThread struct {
-		ID           string   `json:"id"`
-		Path         string   `json:"path"`
-		Line         int      `json:"line"`
-		IsResolved   bool     `json:"isResolved"`
-		IsOutdated   bool     `json:"isOutdated,omitempty"`
-		CommentCount int      `json:"commentCount"`
-		Preview      string   `json:"preview,omitempty"`
-		ResolvedBy   string   `json:"resolvedBy,omitempty"`
-		Comments     []string `json:"comments,omitempty"`
-	}
+// jsonThread is the JSON representation of a thread in listings
+type jsonThread struct {
+	ID           string   `json:"id"`
+	Path         string   `json:"path"`
+	Line         int      `json:"line"`
+	IsResolved   bool     `json:"isResolved"`
+	IsOutdated   bool     `json:"isOutdated,omitempty"`
+	WaitingOn    string   `json:"waitingOn,omitempty"`
+	Acknowledged bool     `json:"acknowledged,omitempty"`
+	CommentCount int      `json:"commentCount"`
+	HiddenCount  int      `json:"hiddenCount,omitempty"`
+	Preview      string   `json:"preview,omitempty"`
+	ResolvedBy   string   `json:"resolvedBy,omitempty"`
+	Comments     []string `json:"comments,omitempty"`
+}
 
-	jsonThreads := make([]JSONThread, len(threads))
+func threadsToJSON(threads []api.Thread) []jsonThread {
+	jsonThreads := make([]jsonThread, len(threads))
 	for i, t := range threads {
-		jt := JSONThread{
+		jt := jsonThread{
 			ID:           t.ID,
 			Path:         t.Path,
 			Line:         t.Line,
 			IsResolved:   t.IsResolved,
 			IsOutdated:   t.IsOutdated,
+			WaitingOn:    t.WaitingOn,
+			Acknowledged: t.Acknowledged,
 			CommentCount: len(t.Comments),
+			HiddenCount:  hiddenComments(t),
 		}
 
 		if len(t.Comments) > 0 {
-			jt.Preview = truncate(t.Comments[0].Body, 100)
+			jt.Preview = truncate(threadPreview(t), 100)
 			jt.Comments = make([]string, len(t.Comments))
 			for j, c := range t.Comments {
 				jt.Comments[j] = c.ID
@@ -293,10 +497,13 @@ func outputThreadsJSON(threads []api.Thread, terminal term.Term) error {
 
 		jsonThreads[i] = jt
 	}
+	return jsonThreads
+}
 
-	encoder := json.NewEncoder(terminal.Out())
+func outputThreadsJSON(threads []api.Thread, ios *IOStreams) error {
+	encoder := json.NewEncoder(ios.Out)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(jsonThreads)
+	return encoder.Encode(threadsToJSON(threads))
 }
 
 func formatReactions(thread api.Thread) string {
@@ -327,24 +534,5 @@ func formatReactions(thread api.Thread) string {
 }
 
 func contentToEmoji(content string) string {
-	switch content {
-	case "THUMBS_UP":
-		return "👍"
-	case "THUMBS_DOWN":
-		return "👎"
-	case "LAUGH":
-		return "😄"
-	case "HOORAY":
-		return "🎉"
-	case "CONFUSED":
-		return "😕"
-	case "HEART":
-		return "❤️"
-	case "ROCKET":
-		return "🚀"
-	case "EYES":
-		return "👀"
-	default:
-		return content
-	}
+	return format.ReactionEmoji(content)
 }
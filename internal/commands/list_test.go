@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestFilterWaitingOn(t *testing.T) {
+	threads := []api.Thread{
+		{ID: "PRRT_a", WaitingOn: api.WaitingOnAuthor, Comments: []api.Comment{{Author: api.User{Login: "alice"}}}},
+		{ID: "PRRT_b", WaitingOn: api.WaitingOnReviewer, Comments: []api.Comment{{Author: api.User{Login: "alice"}}, {Author: api.User{Login: "author"}}}},
+		{ID: "PRRT_c", WaitingOn: api.WaitingOnReviewer, Comments: []api.Comment{{Author: api.User{Login: "bob"}}, {Author: api.User{Login: "author"}}}},
+		{ID: "PRRT_d", IsResolved: true},
+	}
+
+	tests := []struct {
+		name    string
+		who     string
+		viewer  string
+		want    []string
+		wantErr bool
+	}{
+		{"author", "author", "", []string{"PRRT_a"}, false},
+		{"reviewer", "reviewer", "", []string{"PRRT_b", "PRRT_c"}, false},
+		{"me as PR author", "me", "author", []string{"PRRT_a"}, false},
+		{"me as reviewer", "me", "alice", []string{"PRRT_b"}, false},
+		{"invalid", "nobody", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterWaitingOn(threads, tt.who, "author", tt.viewer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterWaitingOn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterWaitingOn() returned %d threads, want %d", len(got), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got[i].ID != id {
+					t.Errorf("thread %d = %s, want %s", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestThreadHasReactionBy(t *testing.T) {
+	thread := api.Thread{Comments: []api.Comment{
+		{ReactionGroups: []api.ReactionGroup{{Content: "THUMBS_UP", Users: api.ReactionUsers{TotalCount: 2, Nodes: []api.User{{Login: "alice"}, {Login: "bob"}}}}}},
+		{ReactionGroups: []api.ReactionGroup{{Content: "EYES", Users: api.ReactionUsers{TotalCount: 1, Nodes: []api.User{{Login: "carol"}}}}}},
+	}}
+
+	tests := []struct {
+		login string
+		want  bool
+	}{
+		{"alice", true},
+		{"Carol", true},
+		{"@bob", true},
+		{"dave", false},
+	}
+
+	for _, tt := range tests {
+		if got := threadHasReactionBy(thread, tt.login); got != tt.want {
+			t.Errorf("threadHasReactionBy(%q) = %v, want %v", tt.login, got, tt.want)
+		}
+	}
+}
+
+const hiddenThreads = `{"data":{"repository":{"pullRequest":{"number":1,"title":"Add widgets",
+  "comments":{"nodes":[
+    {"id":"IC_1","body":"Coverage report: 80%","author":{"login":"codecov"},"isMinimized":true,"minimizedReason":"OUTDATED"},
+    {"id":"IC_2","body":"Looks good","author":{"login":"reviewer"}}
+  ]},
+  "reviewThreads":{"nodes":[
+    {"id":"PRRT_a","path":"main.go","line":7,"comments":{"nodes":[
+      {"id":"PRRC_1","body":"Buy cheap watches","author":{"login":"spammer"},"isMinimized":true,"minimizedReason":"SPAM"},
+      {"id":"PRRC_2","body":"Reported","author":{"login":"author"}}
+    ]}},
+    {"id":"PRRT_b","path":"util.go","line":3,"comments":{"nodes":[
+      {"id":"PRRC_3","body":"Typo","author":{"login":"reviewer"}},
+      {"id":"PRRC_4","body":"Old suggestion","author":{"login":"reviewer"},"isMinimized":true,"minimizedReason":"OUTDATED"}
+    ]}},
+    {"id":"PRRT_c","path":"api.go","line":12,"comments":{"nodes":[
+      {"id":"PRRC_5","body":"Add a test","author":{"login":"reviewer"}}
+    ]}}
+  ]}}}}}`
+
+func TestListThreadsHidden(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		want string
+	}{
+		{"default", "", "PRRT_b,PRRT_c"},
+		{"include hidden", "--include-hidden", "PRRT_a,PRRT_b,PRRT_c"},
+		{"only hidden", "--only-hidden", "PRRT_a,PRRT_b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, map[string]string{"ListThreads": hiddenThreads})
+			f, out, _ := newTestFactory(t, gh)
+
+			args := []string{"list", "threads", "--format", "json"}
+			if tt.flag != "" {
+				args = append(args, tt.flag)
+			}
+			if err := runCommand(t, f, args...); err != nil {
+				t.Fatalf("list threads: %v", err)
+			}
+
+			var threads []jsonThread
+			if err := json.Unmarshal(out.Bytes(), &threads); err != nil {
+				t.Fatalf("decode output: %v\n%s", err, out.String())
+			}
+			ids := make([]string, len(threads))
+			for i, thread := range threads {
+				ids[i] = thread.ID
+				if strings.Contains(thread.Preview, "watches") {
+					t.Errorf("preview of %s shows a hidden comment: %q", thread.ID, thread.Preview)
+				}
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("threads = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThreadPreview(t *testing.T) {
+	tests := []struct {
+		name      string
+		thread    api.Thread
+		preview   string
+		count     string
+		hiddenTop bool
+	}{
+		{
+			name:    "visible",
+			thread:  api.Thread{Comments: []api.Comment{{Body: "Use a constant"}}},
+			preview: "Use a constant",
+			count:   "1",
+		},
+		{
+			name: "first comment hidden",
+			thread: api.Thread{Comments: []api.Comment{
+				{Body: "spam", IsMinimized: true, MinimizedReason: "SPAM"},
+				{Body: "Reported"},
+			}},
+			preview:   "Hidden (spam)",
+			count:     "2 (1 hidden)",
+			hiddenTop: true,
+		},
+		{
+			name: "reply hidden",
+			thread: api.Thread{Comments: []api.Comment{
+				{Body: "Typo"},
+				{Body: "Old", IsMinimized: true, MinimizedReason: "OFF_TOPIC"},
+			}},
+			preview: "Typo",
+			count:   "2 (1 hidden)",
+		},
+		{
+			name:   "empty",
+			thread: api.Thread{},
+			count:  "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := threadPreview(tt.thread); got != tt.preview {
+				t.Errorf("threadPreview() = %q, want %q", got, tt.preview)
+			}
+			if got := formatCommentCount(tt.thread); got != tt.count {
+				t.Errorf("formatCommentCount() = %q, want %q", got, tt.count)
+			}
+			if got := threadIsHidden(tt.thread); got != tt.hiddenTop {
+				t.Errorf("threadIsHidden() = %v, want %v", got, tt.hiddenTop)
+			}
+		})
+	}
+}
@@ -0,0 +1,552 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/format"
+	"github.com/hamishmorgan/gh-talk/internal/mcp"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run an MCP server exposing conversation tools",
+	Long: `Run a Model Context Protocol server on stdin/stdout so coding agents
+can read and act on review conversations.
+
+Tools:
+  list_threads    List review threads (same filters as 'list threads')
+  show_thread     Show a thread with its comments
+  reply           Reply to a thread
+  react           Add or remove a reaction on a comment
+  resolve         Resolve a thread
+  unresolve       Unresolve a thread
+  hide            Hide a comment
+  status          Summarize review status of a PR
+
+Tool inputs mirror the command flags. Tools that read a PR take "repo"
+and "pr", defaulting to the repository and branch the server runs in.
+
+Example client configuration:
+  {"command": "gh", "args": ["talk", "mcp"]}`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Tools report what they changed, so a server that changes nothing
+	// would mislead its clients
+	if dryRunFormat(cmd) != "" {
+		return fmt.Errorf("mcp does not support --dry-run")
+	}
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	return newMCPServer(client).Serve(ctx, factory.IOStreams.In, factory.IOStreams.Out)
+}
+
+// mcpArg is a required positional argument of a tool
+type mcpArg struct {
+	name        string
+	description string
+}
+
+// mcpTool exposes a command as an MCP tool. Its input schema is derived
+// from the listed flags of source, so tools stay in step with the CLI.
+type mcpTool struct {
+	name        string
+	description string
+	source      *cobra.Command
+	flags       []string
+	args        []mcpArg
+	withPR      bool
+	run         func(ctx context.Context, client *api.Client, in *toolInput) (string, error)
+}
+
+// toolInput holds tool arguments: flags are set on cmd so the same
+// helpers as the CLI can read them
+type toolInput struct {
+	cmd  *cobra.Command
+	args map[string]string
+}
+
+func mcpTools() []mcpTool {
+	return []mcpTool{
+		{
+			name:        "list_threads",
+			description: "List review threads on a pull request. Unresolved threads are shown unless resolved or all is set, and threads whose first comment is hidden unless include-hidden is set.",
+			source:      listThreadsCmd,
+			flags:       []string{"unresolved", "resolved", "all", "author", "file", "waiting-on", "include-hidden", "only-hidden"},
+			withPR:      true,
+			run:         mcpListThreads,
+		},
+		{
+			name:        "show_thread",
+			description: "Show a review thread with all of its comments and reactions.",
+			args:        []mcpArg{{"thread", "Thread ID (PRRT_...), discussion URL or handle (r123)"}},
+			withPR:      true,
+			run:         mcpShowThread,
+		},
+		{
+			name:        "reply",
+			description: "Reply to a review thread.",
+			source:      replyCmd,
+			flags:       []string{"resolve"},
+			args:        []mcpArg{{"thread", "Thread ID (PRRT_...)"}, {"body", "Reply text (Markdown)"}},
+			run:         mcpReply,
+		},
+		{
+			name:        "react",
+			description: "Add an emoji reaction to a comment, or remove it.",
+			source:      reactCmd,
+			flags:       []string{"remove"},
+			args:        []mcpArg{{"comment", "Comment ID (PRRC_... or IC_...)"}, {"reaction", "Emoji or name (👍, THUMBS_UP, +1, ...)"}},
+			run:         mcpReact,
+		},
+		{
+			name:        "resolve",
+			description: "Resolve a review thread, optionally posting a message first.",
+			source:      resolveCmd,
+			flags:       []string{"message"},
+			args:        []mcpArg{{"thread", "Thread ID (PRRT_...)"}},
+			run:         mcpResolve,
+		},
+		{
+			name:        "unresolve",
+			description: "Unresolve (reopen) a review thread.",
+			args:        []mcpArg{{"thread", "Thread ID (PRRT_...)"}},
+			run:         mcpUnresolve,
+		},
+		{
+			name:        "hide",
+			description: "Hide (minimize) a comment with a reason.",
+			source:      hideCmd,
+			flags:       []string{"reason"},
+			args:        []mcpArg{{"comment", "Comment ID (PRRC_... or IC_...)"}},
+			run:         mcpHide,
+		},
+		{
+			name:        "status",
+			description: "Summarize review thread status for a pull request.",
+			withPR:      true,
+			run:         mcpStatus,
+		},
+	}
+}
+
+// newMCPServer builds the MCP server with every tool bound to client
+func newMCPServer(client *api.Client) *mcp.Server {
+	server := &mcp.Server{Name: "gh-talk", Version: "0.1.0"}
+
+	for _, tool := range mcpTools() {
+		tool := tool
+		server.AddTool(mcp.Tool{
+			ToolInfo: mcp.ToolInfo{
+				Name:        tool.name,
+				Description: tool.description,
+				InputSchema: tool.schema(),
+			},
+			Handler: func(ctx context.Context, arguments json.RawMessage) (string, error) {
+				in, err := tool.input(arguments)
+				if err != nil {
+					return "", err
+				}
+				return tool.run(ctx, client, in)
+			},
+		})
+	}
+
+	return server
+}
+
+// sourceFlags returns the flags a tool exposes
+func (t mcpTool) sourceFlags() []*pflag.Flag {
+	var flags []*pflag.Flag
+	if t.withPR {
+		flags = append(flags, rootCmd.PersistentFlags().Lookup("repo"), rootCmd.PersistentFlags().Lookup("pr"))
+	}
+	for _, name := range t.flags {
+		flags = append(flags, t.source.Flags().Lookup(name))
+	}
+	return flags
+}
+
+// schema derives the tool's input schema from its arguments and flags
+func (t mcpTool) schema() *mcp.Schema {
+	s := &mcp.Schema{Type: "object", Properties: make(map[string]*mcp.Schema)}
+
+	for _, arg := range t.args {
+		s.Properties[arg.name] = &mcp.Schema{Type: "string", Description: arg.description}
+		s.Required = append(s.Required, arg.name)
+	}
+	for _, flag := range t.sourceFlags() {
+		s.Properties[flag.Name] = mcp.FlagSchema(flag)
+	}
+
+	return s
+}
+
+// input parses tool arguments, setting flags on a fresh command
+func (t mcpTool) input(arguments json.RawMessage) (*toolInput, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(arguments, &values); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	in := &toolInput{cmd: &cobra.Command{}, args: make(map[string]string)}
+	for _, flag := range t.sourceFlags() {
+		cloneFlag(in.cmd.Flags(), flag)
+	}
+
+	for _, arg := range t.args {
+		v, ok := values[arg.name].(string)
+		if !ok || v == "" {
+			return nil, fmt.Errorf("%s is required", arg.name)
+		}
+		in.args[arg.name] = v
+		delete(values, arg.name)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		flag := in.cmd.Flags().Lookup(name)
+		if flag == nil {
+			return nil, fmt.Errorf("unknown argument: %s", name)
+		}
+		if err := flag.Value.Set(flagString(values[name])); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		flag.Changed = true
+	}
+
+	return in, nil
+}
+
+// cloneFlag defines a flag like f, with its own value, on fs
+func cloneFlag(fs *pflag.FlagSet, f *pflag.Flag) {
+	switch f.Value.Type() {
+	case "bool":
+		fs.Bool(f.Name, f.DefValue == "true", f.Usage)
+	case "int":
+		def, _ := strconv.Atoi(f.DefValue)
+		fs.Int(f.Name, def, f.Usage)
+	case "stringSlice":
+		fs.StringSlice(f.Name, nil, f.Usage)
+	case "duration":
+		def, _ := time.ParseDuration(f.DefValue)
+		fs.Duration(f.Name, def, f.Usage)
+	default:
+		fs.String(f.Name, f.DefValue, f.Usage)
+	}
+}
+
+// flagString formats a JSON value as flag text
+func flagString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, p := range v {
+			parts[i] = flagString(p)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// mcpPullRequest fetches the PR named by the repo and pr arguments
+func mcpPullRequest(ctx context.Context, client *api.Client, in *toolInput) (*api.PullRequest, error) {
+	owner, name, err := getRepository(in.cmd)
+	if err != nil {
+		return nil, err
+	}
+	prNum, err := getCurrentPR(in.cmd)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetPullRequest(ctx, owner, name, prNum)
+}
+
+func mcpListThreads(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	pr, err := mcpPullRequest(ctx, client, in)
+	if err != nil {
+		return "", err
+	}
+
+	threads := filterThreads(in.cmd, pr.ReviewThreads)
+
+	waitingOn, _ := in.cmd.Flags().GetString("waiting-on")
+	if waitingOn != "" {
+		viewer := ""
+		if waitingOn == "me" {
+			if viewer, err = client.CurrentUser(ctx); err != nil {
+				return "", err
+			}
+		}
+		if threads, err = filterWaitingOn(threads, waitingOn, pr.Author.Login, viewer); err != nil {
+			return "", err
+		}
+	}
+
+	return mcpJSON(threadsToJSON(threads))
+}
+
+// threadDetail is the JSON shape of show_thread
+type threadDetail struct {
+	ID         string          `json:"id"`
+	Handle     string          `json:"handle,omitempty"`
+	Path       string          `json:"path"`
+	Line       int             `json:"line"`
+	IsResolved bool            `json:"isResolved"`
+	IsOutdated bool            `json:"isOutdated,omitempty"`
+	ResolvedBy string          `json:"resolvedBy,omitempty"`
+	WaitingOn  string          `json:"waitingOn,omitempty"`
+	Comments   []commentDetail `json:"comments"`
+}
+
+type commentDetail struct {
+	ID           string         `json:"id"`
+	Author       string         `json:"author"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	Body         string         `json:"body"`
+	IsMinimized  bool           `json:"isMinimized,omitempty"`
+	HiddenReason string         `json:"hiddenReason,omitempty"`
+	Reactions    map[string]int `json:"reactions,omitempty"`
+}
+
+func mcpShowThread(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	pr, err := mcpPullRequest(ctx, client, in)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := resolveThreadRef(in.args["thread"], pr.ReviewThreads)
+	if err != nil {
+		return "", err
+	}
+
+	detail := threadDetail{
+		ID:         t.ID,
+		Handle:     threadHandle(*t),
+		Path:       t.Path,
+		Line:       t.Line,
+		IsResolved: t.IsResolved,
+		IsOutdated: t.IsOutdated,
+		WaitingOn:  t.WaitingOn,
+		Comments:   make([]commentDetail, 0, len(t.Comments)),
+	}
+	if t.ResolvedBy != nil {
+		detail.ResolvedBy = t.ResolvedBy.Login
+	}
+	for _, c := range t.Comments {
+		cd := commentDetail{
+			ID:          c.ID,
+			Author:      c.Author.Login,
+			CreatedAt:   c.CreatedAt,
+			Body:        c.Body,
+			IsMinimized: c.IsMinimized,
+		}
+		if c.IsMinimized {
+			cd.HiddenReason = format.HiddenReason(c)
+		}
+		for _, rg := range c.ReactionGroups {
+			if cd.Reactions == nil {
+				cd.Reactions = make(map[string]int)
+			}
+			cd.Reactions[rg.Content] = rg.Users.TotalCount
+		}
+		detail.Comments = append(detail.Comments, cd)
+	}
+
+	return mcpJSON(detail)
+}
+
+func mcpReply(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	threadID, err := parseThreadID(in.args["thread"])
+	if err != nil {
+		return "", err
+	}
+
+	comment, err := client.ReplyToThread(ctx, threadID, in.args["body"])
+	if err != nil {
+		return "", err
+	}
+
+	resolve, _ := in.cmd.Flags().GetBool("resolve")
+	if resolve {
+		if _, err := client.ResolveThread(ctx, threadID); err != nil {
+			return "", fmt.Errorf("replied, but failed to resolve: %w", err)
+		}
+		return fmt.Sprintf("Replied to and resolved %s (new comment %s)", threadID, comment.ID), nil
+	}
+	return fmt.Sprintf("Replied to %s (new comment %s)", threadID, comment.ID), nil
+}
+
+func mcpReact(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	commentID := in.args["comment"]
+	if !strings.HasPrefix(commentID, "PRRC_") && !strings.HasPrefix(commentID, "IC_") {
+		return "", fmt.Errorf("invalid comment ID %s - expected format: PRRC_ or IC_", commentID)
+	}
+
+	content, err := parseEmoji(in.args["reaction"])
+	if err != nil {
+		return "", err
+	}
+	if err := client.CheckReaction(content); err != nil {
+		return "", err
+	}
+
+	mode := reactAdd
+	if remove, _ := in.cmd.Flags().GetBool("remove"); remove {
+		mode = reactRemove
+	}
+
+	comment, err := client.GetComment(ctx, commentID)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := mcpExecute(ctx, client, comment, reactOperations(comment, content, mode))
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, r := range results {
+		switch {
+		case r.Op == ops.Unreact && r.Status == ops.StatusSkipped:
+			lines = append(lines, fmt.Sprintf("No %s reaction on %s to remove", r.Reaction, r.Comment))
+		case r.Op == ops.Unreact:
+			lines = append(lines, fmt.Sprintf("Removed %s reaction from %s", r.Reaction, r.Comment))
+		case r.Status == ops.StatusSkipped:
+			lines = append(lines, fmt.Sprintf("Already reacted %s to %s", r.Reaction, r.Comment))
+		default:
+			lines = append(lines, fmt.Sprintf("Added %s reaction to %s", r.Reaction, r.Comment))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func mcpResolve(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	threadID, err := parseThreadID(in.args["thread"])
+	if err != nil {
+		return "", err
+	}
+
+	message, _ := in.cmd.Flags().GetString("message")
+	if message != "" {
+		if _, err := client.ReplyToThread(ctx, threadID, message); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := client.ResolveThread(ctx, threadID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Resolved %s", threadID), nil
+}
+
+func mcpUnresolve(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	threadID, err := parseThreadID(in.args["thread"])
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := client.UnresolveThread(ctx, threadID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Unresolved %s", threadID), nil
+}
+
+func mcpHide(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	commentID := in.args["comment"]
+	if !strings.HasPrefix(commentID, "PRRC_") && !strings.HasPrefix(commentID, "IC_") {
+		return "", fmt.Errorf("invalid comment ID %s - expected format: PRRC_ or IC_", commentID)
+	}
+
+	reason, _ := in.cmd.Flags().GetString("reason")
+	classifier, err := api.ParseClassifier(reason)
+	if err != nil {
+		return "", err
+	}
+
+	comment, err := client.GetComment(ctx, commentID)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := mcpExecute(ctx, client, comment, []ops.Operation{{Op: ops.Hide, Comment: commentID, Reason: classifier}})
+	if err != nil {
+		return "", err
+	}
+	if results[0].Status == ops.StatusSkipped {
+		return fmt.Sprintf("%s is already hidden", commentID), nil
+	}
+	return fmt.Sprintf("Hidden %s (reason: %s)", commentID, strings.ToLower(classifier)), nil
+}
+
+// mcpExecute checks operations on a comment, including the viewer's
+// permissions, then runs them, skipping those with nothing to change.
+// It returns the first failure as an error.
+func mcpExecute(ctx context.Context, client *api.Client, comment *api.Comment, operations []ops.Operation) ([]ops.Result, error) {
+	ix := ops.NewIndex(&api.PullRequest{})
+	indexComment(ix, comment)
+
+	changes, err := ix.CheckAll(operations)
+	if err != nil {
+		return nil, err
+	}
+
+	results := (&ops.Executor{Client: client}).Execute(ctx, changes)
+	for _, r := range results {
+		if r.Status == ops.StatusFailed {
+			return nil, fmt.Errorf("%s: %s", r.Operation, r.Error)
+		}
+	}
+	return results, nil
+}
+
+func mcpStatus(ctx context.Context, client *api.Client, in *toolInput) (string, error) {
+	pr, err := mcpPullRequest(ctx, client, in)
+	if err != nil {
+		return "", err
+	}
+	return mcpJSON(summarizeStatus(pr))
+}
+
+// mcpJSON formats tool output as indented JSON
+func mcpJSON(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
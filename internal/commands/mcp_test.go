@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/mcp"
+)
+
+const mcpThreadsResponse = `{"data":{"repository":{"pullRequest":{"number":7,"author":{"login":"author"},"reviewThreads":{"nodes":[
+  {"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"comments":{"nodes":[
+    {"id":"PRRC_1","databaseId":101,"body":"Use a constant","author":{"login":"reviewer"},"reactionGroups":[]}
+  ]}},
+  {"id":"PRRT_b","isResolved":true,"path":"util.go","line":3,"comments":{"nodes":[
+    {"id":"PRRC_2","databaseId":102,"body":"Typo","author":{"login":"reviewer"},"reactionGroups":[]}
+  ]}}
+]}}}}}`
+
+// connectMCP starts a server on a client of gh and connects to it
+func connectMCP(t *testing.T, gh *fakeGitHub) *mcp.Client {
+	t.Helper()
+
+	apiClient, err := api.NewClientWithOptions(gh.clientOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	client, stop := mcp.Connect(ctx, newMCPServer(apiClient))
+	t.Cleanup(stop)
+
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return client
+}
+
+func TestMCPToolSchemas(t *testing.T) {
+	client := connectMCP(t, newFakeGitHub(t, nil))
+
+	tools, err := client.ListTools(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]mcp.ToolInfo)
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+
+	for _, name := range []string{"list_threads", "show_thread", "reply", "react", "resolve", "unresolve", "hide", "status"} {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("missing tool %s", name)
+		}
+	}
+
+	list := byName["list_threads"].InputSchema
+	if list.Properties["all"].Type != "boolean" || list.Properties["pr"].Type != "integer" || list.Properties["waiting-on"].Type != "string" {
+		t.Errorf("list_threads schema = %+v", list.Properties)
+	}
+
+	hide := byName["hide"].InputSchema
+	if hide.Properties["reason"].Default != "off-topic" || len(hide.Required) != 1 || hide.Required[0] != "comment" {
+		t.Errorf("hide schema = %+v", hide)
+	}
+}
+
+func TestMCPListAndShowThreads(t *testing.T) {
+	client := connectMCP(t, newFakeGitHub(t, map[string]string{"ListThreads": mcpThreadsResponse}))
+	ctx := context.Background()
+
+	result, err := client.CallTool(ctx, "list_threads", map[string]interface{}{"repo": "owner/repo", "pr": 7, "all": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("list_threads error: %s", result.Text())
+	}
+
+	var threads []jsonThread
+	if err := json.Unmarshal([]byte(result.Text()), &threads); err != nil {
+		t.Fatalf("decode list_threads: %v", err)
+	}
+	if len(threads) != 2 {
+		t.Errorf("got %d threads, want 2", len(threads))
+	}
+
+	result, err = client.CallTool(ctx, "show_thread", map[string]interface{}{"repo": "owner/repo", "pr": 7, "thread": "r102"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var detail threadDetail
+	if err := json.Unmarshal([]byte(result.Text()), &detail); err != nil {
+		t.Fatalf("decode show_thread: %v\n%s", err, result.Text())
+	}
+	if detail.ID != "PRRT_b" || len(detail.Comments) != 1 || detail.Comments[0].Body != "Typo" {
+		t.Errorf("show_thread = %+v", detail)
+	}
+}
+
+func TestMCPMutations(t *testing.T) {
+	client := connectMCP(t, newFakeGitHub(t, map[string]string{
+		"ResolveThread": `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":true}}}}`,
+	}))
+	ctx := context.Background()
+
+	result, err := client.CallTool(ctx, "resolve", map[string]interface{}{"thread": "PRRT_a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || result.Text() != "Resolved PRRT_a" {
+		t.Errorf("resolve = %+v", result)
+	}
+
+	tests := []struct {
+		tool    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"resolve", map[string]interface{}{}, "thread is required"},
+		{"resolve", map[string]interface{}{"thread": "bad"}, "invalid thread ID"},
+		{"react", map[string]interface{}{"comment": "PRRC_1", "reaction": "nope"}, "invalid emoji"},
+		{"hide", map[string]interface{}{"comment": "PRRC_1", "reason": "boring"}, "invalid reason"},
+		{"unresolve", map[string]interface{}{"thread": "PRRT_a", "force": true}, "unknown argument: force"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantErr, func(t *testing.T) {
+			result, err := client.CallTool(ctx, tt.tool, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.IsError || !strings.Contains(result.Text(), tt.wantErr) {
+				t.Errorf("%s = %+v, want error %q", tt.tool, result, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMCPRejectsDryRun(t *testing.T) {
+	gh := newFakeGitHub(t, nil)
+	f, _, _ := newTestFactory(t, gh)
+
+	err := runCommand(t, f, "mcp", "--dry-run")
+	if err == nil || !strings.Contains(err.Error(), "does not support --dry-run") {
+		t.Errorf("mcp --dry-run error = %v", err)
+	}
+}
+
+func TestMCPReactAndHideCheckComment(t *testing.T) {
+	comment := func(fields string) string {
+		return `{"data":{"node":{"id":"PRRC_1","body":"Use a constant","author":{"login":"reviewer"},` + fields + `}}}`
+	}
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]interface{}
+		comment string
+		want    string
+		wantErr string
+		wantOps []string
+	}{
+		{
+			name:    "react",
+			tool:    "react",
+			args:    map[string]interface{}{"comment": "PRRC_1", "reaction": "👍"},
+			comment: comment(`"viewerCanReact":true,"reactionGroups":[]`),
+			want:    "Added THUMBS_UP reaction to PRRC_1",
+			wantOps: []string{"GetComment", "AddReaction"},
+		},
+		{
+			name:    "already reacted",
+			tool:    "react",
+			args:    map[string]interface{}{"comment": "PRRC_1", "reaction": "👍"},
+			comment: comment(`"viewerCanReact":true,"reactionGroups":[{"content":"THUMBS_UP","viewerHasReacted":true,"users":{"totalCount":1}}]`),
+			want:    "Already reacted THUMBS_UP to PRRC_1",
+			wantOps: []string{"GetComment"},
+		},
+		{
+			name:    "cannot react",
+			tool:    "react",
+			args:    map[string]interface{}{"comment": "PRRC_1", "reaction": "👍"},
+			comment: comment(`"viewerCanReact":false,"reactionGroups":[]`),
+			wantErr: "you cannot react to PRRC_1",
+			wantOps: []string{"GetComment"},
+		},
+		{
+			name:    "hide",
+			tool:    "hide",
+			args:    map[string]interface{}{"comment": "PRRC_1", "reason": "outdated"},
+			comment: comment(`"viewerCanMinimize":true,"reactionGroups":[]`),
+			want:    "Hidden PRRC_1 (reason: outdated)",
+			wantOps: []string{"GetComment", "MinimizeComment"},
+		},
+		{
+			name:    "already hidden",
+			tool:    "hide",
+			args:    map[string]interface{}{"comment": "PRRC_1", "reason": "outdated"},
+			comment: comment(`"viewerCanMinimize":true,"isMinimized":true,"minimizedReason":"OUTDATED","reactionGroups":[]`),
+			want:    "PRRC_1 is already hidden",
+			wantOps: []string{"GetComment"},
+		},
+		{
+			name:    "cannot hide",
+			tool:    "hide",
+			args:    map[string]interface{}{"comment": "PRRC_1", "reason": "outdated"},
+			comment: comment(`"viewerCanMinimize":false,"reactionGroups":[]`),
+			wantErr: "you cannot hide or unhide PRRC_1",
+			wantOps: []string{"GetComment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, map[string]string{
+				"GetComment":      tt.comment,
+				"AddReaction":     `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"THUMBS_UP"},"subject":{"id":"PRRC_1"}}}}`,
+				"MinimizeComment": `{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true,"minimizedReason":"OUTDATED"}}}}`,
+			})
+			client := connectMCP(t, gh)
+
+			result, err := client.CallTool(context.Background(), tt.tool, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.wantErr != "":
+				if !result.IsError || !strings.Contains(result.Text(), tt.wantErr) {
+					t.Errorf("%s = %+v, want error %q", tt.tool, result, tt.wantErr)
+				}
+			case result.IsError || result.Text() != tt.want:
+				t.Errorf("%s = %+v, want %q", tt.tool, result, tt.want)
+			}
+			if got := strings.Join(gh.operations(), ","); got != strings.Join(tt.wantOps, ",") {
+				t.Errorf("operations = %s, want %s", got, strings.Join(tt.wantOps, ","))
+			}
+		})
+	}
+}
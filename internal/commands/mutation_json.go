@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// jsonComment is a comment created or changed by a command
+type jsonComment struct {
+	ID              string     `json:"id"`
+	URL             string     `json:"url,omitempty"`
+	Body            string     `json:"body,omitempty"`
+	Author          string     `json:"author,omitempty"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty"`
+	IsMinimized     bool       `json:"isMinimized"`
+	MinimizedReason string     `json:"minimizedReason,omitempty"`
+}
+
+func commentToJSON(c *api.Comment) *jsonComment {
+	jc := &jsonComment{
+		ID:              c.ID,
+		URL:             c.URL,
+		Body:            c.Body,
+		Author:          c.Author.Login,
+		IsMinimized:     c.IsMinimized,
+		MinimizedReason: c.MinimizedReason,
+	}
+	if !c.CreatedAt.IsZero() {
+		createdAt := c.CreatedAt
+		jc.CreatedAt = &createdAt
+	}
+	return jc
+}
+
+// jsonThreadState is a thread's resolution after resolve or unresolve
+type jsonThreadState struct {
+	ID         string       `json:"id"`
+	IsResolved bool         `json:"isResolved"`
+	ResolvedBy string       `json:"resolvedBy,omitempty"`
+	Reply      *jsonComment `json:"reply,omitempty"`
+}
+
+func threadStateToJSON(t *api.Thread) *jsonThreadState {
+	state := &jsonThreadState{ID: t.ID, IsResolved: t.IsResolved}
+	if t.ResolvedBy != nil {
+		state.ResolvedBy = t.ResolvedBy.Login
+	}
+	return state
+}
+
+// jsonReaction is a reaction added or removed by a command
+type jsonReaction struct {
+	ID      string `json:"id,omitempty"`
+	Content string `json:"content"`
+	Emoji   string `json:"emoji"`
+	Subject string `json:"subject"`
+	Removed bool   `json:"removed,omitempty"`
+	// Skipped is set when the reaction was already in the requested state
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+func reactionToJSON(r *api.Reaction, removed bool) *jsonReaction {
+	return &jsonReaction{
+		ID:      r.ID,
+		Content: r.Content,
+		Emoji:   contentToEmoji(r.Content),
+		Subject: r.SubjectID,
+		Removed: removed,
+	}
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(factory.IOStreams.Out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestMutationJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{
+			name: "reply",
+			v: replyOutput{
+				ThreadID: "PRRT_a",
+				Comment: commentToJSON(&api.Comment{
+					ID: "PRRC_new", URL: "https://github.com/o/r/pull/1#discussion_r9", Body: "Done",
+					Author: api.User{Login: "me"}, CreatedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				}),
+				Reaction: reactionToJSON(&api.Reaction{ID: "REA_1", Content: "THUMBS_UP", SubjectID: "PRRC_1"}, false),
+				Thread:   threadStateToJSON(&api.Thread{ID: "PRRT_a", IsResolved: true, ResolvedBy: &api.User{Login: "me"}}),
+			},
+			want: `{"threadId":"PRRT_a","comment":{"id":"PRRC_new","url":"https://github.com/o/r/pull/1#discussion_r9","body":"Done","author":"me","createdAt":"2025-01-01T10:00:00Z","isMinimized":false},"reaction":{"id":"REA_1","content":"THUMBS_UP","emoji":"👍","subject":"PRRC_1"},"thread":{"id":"PRRT_a","isResolved":true,"resolvedBy":"me"}}`,
+		},
+		{
+			name: "hidden comment",
+			v:    commentToJSON(&api.Comment{ID: "IC_1", IsMinimized: true, MinimizedReason: "outdated"}),
+			want: `{"id":"IC_1","isMinimized":true,"minimizedReason":"outdated"}`,
+		},
+		{
+			name: "removed reaction",
+			v:    reactionToJSON(&api.Reaction{Content: "EYES", SubjectID: "IC_1"}, true),
+			want: `{"content":"EYES","emoji":"👀","subject":"IC_1","removed":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
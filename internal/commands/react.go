@@ -6,17 +6,23 @@ import (
 	"strings"
 
 	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
 	"github.com/spf13/cobra"
 )
 
 var reactCmd = &cobra.Command{
-	Use:   "react <comment-id...> <emoji>",
+	Use:   "react <comment-or-thread...> <emoji>",
 	Short: "Add emoji reaction to comments",
 	Long: `Add an emoji reaction to one or more comments.
 
+Threads can be given instead of comments; --target chooses which of
+their comments get the reaction (first by default). Reactions you have
+already added, or already removed, are skipped.
+
 Arguments:
-  comment-id...  One or more comment IDs (PRRC_... or IC_...)
-  emoji          Emoji or name (👍, THUMBS_UP, +1, etc.)
+  comment-or-thread...  Comment IDs (PRRC_... or IC_...), thread IDs
+                        (PRRT_...), discussion URLs or handles (r123)
+  emoji                 Emoji or name (👍, THUMBS_UP, +1, etc.)
 
 Supported reactions:
   👍 THUMBS_UP     😄 LAUGH      ❤️ HEART
@@ -34,27 +40,43 @@ Examples:
   gh talk react PRRC_kwDOQN97u86UHqK7 ROCKET
 
   # Remove reaction
-  gh talk react PRRC_kwDOQN97u86UHqK7 👍 --remove`,
+  gh talk react PRRC_kwDOQN97u86UHqK7 👍 --remove
+
+  # Toggle: remove it if you already reacted, otherwise add it
+  gh talk react PRRC_kwDOQN97u86UHqK7 👀 --toggle
+
+  # Replace your other reactions on the comment with this one
+  gh talk react PRRC_kwDOQN97u86UHqK7 🚀 --only
+
+  # React to a thread's first comment, or to everyone else's replies
+  gh talk react PRRT_kwDOQN97u85gQeTN 👀
+  gh talk react r101 👍 --target others
+
+  # Show the mutations that would be sent, as JSON
+  gh talk react PRRC_aaa PRRC_bbb 👍 --dry-run=json`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runReact,
 }
 
 func init() {
 	reactCmd.Flags().Bool("remove", false, "Remove reaction instead of adding")
+	reactCmd.Flags().Bool("toggle", false, "Remove the reaction if you already added it, otherwise add it")
+	reactCmd.Flags().Bool("only", false, "Add the reaction and remove your other reactions on the comment")
+	reactCmd.Flags().Bool("json", false, "Output the reactions as JSON")
+	addTargetFlag(reactCmd)
+
+	reactCmd.MarkFlagsMutuallyExclusive("remove", "toggle", "only")
 }
 
 func runReact(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	// Last argument is the emoji, rest are comment IDs
-	commentIDs := args[:len(args)-1]
+	// Last argument is the emoji, rest are comments or threads
+	refs := args[:len(args)-1]
 	emojiInput := args[len(args)-1]
 
-	// Validate all comment IDs
-	for _, commentID := range commentIDs {
-		if !strings.HasPrefix(commentID, "PRRC_") && !strings.HasPrefix(commentID, "IC_") {
-			return fmt.Errorf("invalid comment ID %s - expected format: PRRC_ or IC_", commentID)
-		}
+	if err := checkTargetRefs(refs); err != nil {
+		return err
 	}
 
 	// Parse emoji to GraphQL enum
@@ -64,38 +86,156 @@ func runReact(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create client
-	client, err := api.NewClient()
+	client, err := newMutationClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	mode := reactAdd
+	if remove, _ := cmd.Flags().GetBool("remove"); remove {
+		mode = reactRemove
+	}
+	if toggle, _ := cmd.Flags().GetBool("toggle"); toggle {
+		mode = reactToggle
+	}
+	if only, _ := cmd.Flags().GetBool("only"); only {
+		mode = reactOnly
+	}
+
+	if err := client.CheckReaction(content); err != nil {
+		return err
+	}
+
+	targets, err := resolveTargetComments(ctx, cmd, client, refs)
+	if err != nil {
+		return err
+	}
+
+	// Look up each comment's reactions, to pick the operations and skip
+	// reactions already in the requested state
+	ix := ops.NewIndex(&api.PullRequest{})
+	var operations []ops.Operation
+	for _, target := range targets {
+		comment := target.Comment
+		if comment == nil {
+			comment, err = client.GetComment(ctx, target.ID)
+			if err != nil {
+				return err
+			}
+		}
+		indexComment(ix, comment)
+		operations = append(operations, reactOperations(comment, content, mode)...)
+	}
+
+	if dryRunFormat(cmd) != "" {
+		return runDryRun(ctx, cmd, client, ix, operations)
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	// Check every operation, including the viewer's permissions, before
+	// sending any of them
+	changes, err := ix.CheckAll(operations)
 	if err != nil {
 		return err
 	}
 
-	remove, _ := cmd.Flags().GetBool("remove")
-	emoji := contentToEmoji(content)
+	// Process each operation
+	reactions := make([]*jsonReaction, 0, len(operations))
+	for i, op := range operations {
+		emoji := contentToEmoji(op.Reaction)
+		removing := op.Op == ops.Unreact
 
-	// Process each comment
-	for _, commentID := range commentIDs {
-		if remove {
-			err = client.RemoveReaction(ctx, commentID, content)
+		if changes[i].NoOp {
+			reactions = append(reactions, &jsonReaction{Content: op.Reaction, Emoji: emoji, Subject: op.Comment, Removed: removing, Skipped: true})
+			if !jsonOutput {
+				if removing {
+					fmt.Fprintf(factory.IOStreams.Out, "- No %s reaction on %s to remove\n", emoji, op.Comment)
+				} else {
+					fmt.Fprintf(factory.IOStreams.Out, "- Already reacted %s to %s\n", emoji, op.Comment)
+				}
+			}
+			continue
+		}
+
+		if removing {
+			reaction, err := client.RemoveReaction(ctx, op.Comment, op.Reaction)
 			if err != nil {
-				return fmt.Errorf("failed to remove reaction from %s: %w", commentID, err)
+				return fmt.Errorf("failed to remove reaction from %s: %w", op.Comment, err)
+			}
+			reactions = append(reactions, reactionToJSON(reaction, true))
+			if !jsonOutput {
+				fmt.Fprintf(factory.IOStreams.Out, "✓ Removed %s reaction from %s\n", emoji, op.Comment)
 			}
-			fmt.Printf("✓ Removed %s reaction from %s\n", emoji, commentID)
 		} else {
-			err = client.AddReaction(ctx, commentID, content)
+			reaction, err := client.AddReaction(ctx, op.Comment, op.Reaction)
 			if err != nil {
-				return fmt.Errorf("failed to add reaction to %s: %w", commentID, err)
+				return fmt.Errorf("failed to add reaction to %s: %w", op.Comment, err)
+			}
+			reactions = append(reactions, reactionToJSON(reaction, false))
+			if !jsonOutput {
+				fmt.Fprintf(factory.IOStreams.Out, "✓ Added %s reaction to %s\n", emoji, op.Comment)
 			}
-			fmt.Printf("✓ Added %s reaction to %s\n", emoji, commentID)
 		}
 	}
 
-	if len(commentIDs) > 1 {
-		fmt.Printf("\n✓ Processed %d comments\n", len(commentIDs))
+	if jsonOutput {
+		return printJSON(reactions)
+	}
+
+	if len(targets) > 1 {
+		fmt.Fprintf(factory.IOStreams.Out, "\n✓ Processed %d comments\n", len(targets))
 	}
 
 	return nil
 }
 
+// reactMode is how react changes the viewer's reactions
+type reactMode int
+
+const (
+	reactAdd reactMode = iota
+	reactRemove
+	// reactToggle removes the reaction if the viewer has it, else adds it
+	reactToggle
+	// reactOnly adds the reaction and removes the viewer's others
+	reactOnly
+)
+
+// reactOperations returns the operations that put the viewer's reactions
+// on a comment in the state mode asks for
+func reactOperations(c *api.Comment, content string, mode reactMode) []ops.Operation {
+	reacted := viewerReacted(c, content)
+
+	switch mode {
+	case reactRemove:
+		return []ops.Operation{{Op: ops.Unreact, Comment: c.ID, Reaction: content}}
+	case reactToggle:
+		if reacted {
+			return []ops.Operation{{Op: ops.Unreact, Comment: c.ID, Reaction: content}}
+		}
+	case reactOnly:
+		operations := []ops.Operation{{Op: ops.React, Comment: c.ID, Reaction: content}}
+		for _, rg := range c.ReactionGroups {
+			if rg.ViewerHasReacted && rg.Content != content {
+				operations = append(operations, ops.Operation{Op: ops.Unreact, Comment: c.ID, Reaction: rg.Content})
+			}
+		}
+		return operations
+	}
+	return []ops.Operation{{Op: ops.React, Comment: c.ID, Reaction: content}}
+}
+
+// viewerReacted reports whether the viewer has reacted to c with content
+func viewerReacted(c *api.Comment, content string) bool {
+	for _, rg := range c.ReactionGroups {
+		if rg.Content == content && rg.ViewerHasReacted {
+			return true
+		}
+	}
+	return false
+}
+
 // parseEmoji converts user input to GraphQL ReactionContent enum
 func parseEmoji(input string) (string, error) {
 	// Normalize
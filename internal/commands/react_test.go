@@ -1,7 +1,12 @@
 package commands
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
 )
 
 func TestParseEmoji(t *testing.T) {
@@ -60,3 +65,142 @@ func TestParseEmoji(t *testing.T) {
 		})
 	}
 }
+
+func TestReactOperations(t *testing.T) {
+	comment := &api.Comment{ID: "PRRC_1", ReactionGroups: []api.ReactionGroup{
+		{Content: "THUMBS_UP", ViewerHasReacted: true},
+		{Content: "EYES", ViewerHasReacted: true},
+		{Content: "HEART", ViewerHasReacted: false},
+	}}
+
+	tests := []struct {
+		name    string
+		content string
+		mode    reactMode
+		want    []string
+	}{
+		{"add", "ROCKET", reactAdd, []string{"react ROCKET"}},
+		{"remove", "THUMBS_UP", reactRemove, []string{"unreact THUMBS_UP"}},
+		{"toggle off", "EYES", reactToggle, []string{"unreact EYES"}},
+		{"toggle on", "HEART", reactToggle, []string{"react HEART"}},
+		{"only new", "ROCKET", reactOnly, []string{"react ROCKET", "unreact THUMBS_UP", "unreact EYES"}},
+		{"only existing", "EYES", reactOnly, []string{"react EYES", "unreact THUMBS_UP"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, op := range reactOperations(comment, tt.content, tt.mode) {
+				if op.Comment != "PRRC_1" {
+					t.Errorf("operation on %s, want PRRC_1", op.Comment)
+				}
+				got = append(got, string(op.Op)+" "+op.Reaction)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("reactOperations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReactSkipsNoOps(t *testing.T) {
+	responses := map[string]string{
+		"GetComment": `{"data":{"node":{"id":"PRRC_1","viewerCanReact":true,"author":{"login":"reviewer"},"reactionGroups":[
+			{"content":"THUMBS_UP","users":{"totalCount":1},"viewerHasReacted":true},
+			{"content":"EYES","users":{"totalCount":2},"viewerHasReacted":true}]}}}`,
+		"AddReaction":    `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"ROCKET"},"subject":{"id":"PRRC_1"}}}}`,
+		"RemoveReaction": `{"data":{"removeReaction":{"reaction":{"id":"REA_2","content":"EYES"},"subject":{"id":"PRRC_1"}}}}`,
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		operations []string
+		output     string
+	}{
+		{"already added", []string{"react", "PRRC_1", "👍"}, []string{"GetComment"}, "- Already reacted 👍 to PRRC_1\n"},
+		{"already removed", []string{"react", "PRRC_1", "🚀", "--remove"}, []string{"GetComment"}, "- No 🚀 reaction on PRRC_1 to remove\n"},
+		{"toggle off", []string{"react", "PRRC_1", "👀", "--toggle"}, []string{"GetComment", "RemoveReaction"}, "✓ Removed 👀 reaction from PRRC_1\n"},
+		{"toggle on", []string{"react", "PRRC_1", "🚀", "--toggle"}, []string{"GetComment", "AddReaction"}, "✓ Added 🚀 reaction to PRRC_1\n"},
+		{"only", []string{"react", "PRRC_1", "👍", "--only"}, []string{"GetComment", "RemoveReaction"}, "- Already reacted 👍 to PRRC_1\n✓ Removed 👀 reaction from PRRC_1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, responses)
+			f, out, _ := newTestFactory(t, gh)
+
+			if err := runCommand(t, f, tt.args...); err != nil {
+				t.Fatalf("gh talk %s: %v", strings.Join(tt.args, " "), err)
+			}
+			if got := strings.Join(gh.operations(), ","); got != strings.Join(tt.operations, ",") {
+				t.Errorf("operations = %s, want %s", got, strings.Join(tt.operations, ","))
+			}
+			if out.String() != tt.output {
+				t.Errorf("output = %q, want %q", out.String(), tt.output)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "react", "PRRC_1", "👍", "--json"); err != nil {
+			t.Fatal(err)
+		}
+		var got []jsonReaction
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("parse output: %v\n%s", err, out.String())
+		}
+		if len(got) != 1 || !got[0].Skipped || got[0].Content != "THUMBS_UP" {
+			t.Errorf("output = %s", out.String())
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "react", "PRRC_1", "🚀", "--only", "--dry-run=json"); err != nil {
+			t.Fatal(err)
+		}
+		var report dryRunReport
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatalf("parse output: %v\n%s", err, out.String())
+		}
+		var changes []string
+		for _, c := range report.Changes {
+			changes = append(changes, string(c.Op)+" "+c.Reaction)
+		}
+		if got := strings.Join(changes, ","); got != "react ROCKET,unreact THUMBS_UP,unreact EYES" {
+			t.Errorf("changes = %s", got)
+		}
+		if len(report.Mutations) != 3 || report.Changes[0].Op != ops.React {
+			t.Errorf("mutations = %+v", report.Mutations)
+		}
+	})
+
+	t.Run("exclusive flags", func(t *testing.T) {
+		f, _, _ := newTestFactory(t, newFakeGitHub(t, responses))
+		if err := runCommand(t, f, "react", "PRRC_1", "👍", "--toggle", "--only"); err == nil {
+			t.Error("react --toggle --only: error = nil")
+		}
+	})
+}
+
+func TestReactChecksPermission(t *testing.T) {
+	gh := newFakeGitHub(t, map[string]string{
+		"GetComment":  `{"data":{"node":{"id":"PRRC_1","viewerCanReact":false,"author":{"login":"reviewer"},"reactionGroups":[]}}}`,
+		"AddReaction": `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"THUMBS_UP"},"subject":{"id":"PRRC_1"}}}}`,
+	})
+	f, _, _ := newTestFactory(t, gh)
+
+	err := runCommand(t, f, "react", "PRRC_1", "👍")
+	if err == nil || !strings.Contains(err.Error(), "you cannot react to PRRC_1") {
+		t.Errorf("react error = %v, want permission error", err)
+	}
+	if got := strings.Join(gh.operations(), ","); got != "GetComment" {
+		t.Errorf("operations = %s, want GetComment only", got)
+	}
+}
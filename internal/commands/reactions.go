@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var reactionsCmd = &cobra.Command{
+	Use:   "reactions <comment-id|thread-id>",
+	Short: "Show who reacted to comments",
+	Long: `List the reactions on a comment, or on every comment in a review
+thread, with the users who reacted and when the first one did.
+
+Arguments:
+  comment-id  Comment ID (PRRC_... or IC_...)
+  thread-id   Thread ID (PRRT_...), for all comments in the thread
+
+Examples:
+  # Who reacted to a comment
+  gh talk reactions PRRC_kwDOQN97u86UHqK7
+
+  # Reactions across a whole thread, as JSON
+  gh talk reactions PRRT_kwDOQN97u85gQeTN --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReactions,
+}
+
+func init() {
+	reactionsCmd.Flags().Bool("json", false, "Output the reactions as JSON")
+}
+
+// jsonCommentReactions is a comment's reactions in reactions --json
+type jsonCommentReactions struct {
+	Comment   string              `json:"comment"`
+	Author    string              `json:"author,omitempty"`
+	URL       string              `json:"url,omitempty"`
+	Reactions []jsonReactionGroup `json:"reactions"`
+}
+
+// jsonReactionGroup is one reaction on a comment and who added it
+type jsonReactionGroup struct {
+	Content          string     `json:"content"`
+	Emoji            string     `json:"emoji"`
+	Count            int        `json:"count"`
+	Users            []string   `json:"users"`
+	CreatedAt        *time.Time `json:"createdAt,omitempty"`
+	ViewerHasReacted bool       `json:"viewerHasReacted"`
+}
+
+func runReactions(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	id := args[0]
+	if !strings.HasPrefix(id, "PRRT_") && !strings.HasPrefix(id, "PRRC_") && !strings.HasPrefix(id, "IC_") {
+		return fmt.Errorf("invalid ID %s - expected a comment (PRRC_ or IC_) or thread (PRRT_)", id)
+	}
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	comments, err := client.GetReactions(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if jsonOutput {
+		return printJSON(commentReactionsToJSON(comments))
+	}
+
+	total := 0
+	for _, c := range comments {
+		total += len(c.ReactionGroups)
+	}
+	if total == 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "No reactions on %s\n", id)
+		return nil
+	}
+
+	return outputReactions(comments, factory.IOStreams)
+}
+
+// outputReactions prints one row per reaction on each comment; terminals
+// get a table, other outputs tab-separated values with RFC 3339 times
+func outputReactions(comments []api.Comment, ios *IOStreams) error {
+	t := tableprinter.New(ios.Out, ios.IsTerminal, ios.Width)
+
+	if ios.IsTerminal {
+		t.AddField("Comment")
+		t.AddField("Author")
+		t.AddField("Reaction")
+		t.AddField("Count")
+		t.AddField("Users")
+		t.AddField("First Reacted")
+		t.EndRow()
+	}
+
+	for _, c := range comments {
+		for _, rg := range c.ReactionGroups {
+			t.AddField(c.ID)
+			t.AddField(c.Author.Login)
+			t.AddField(contentToEmoji(rg.Content))
+			t.AddField(fmt.Sprintf("%d", rg.Users.TotalCount))
+			t.AddField(reactionUsers(rg))
+			t.AddField(reactionTime(rg, ios.IsTerminal))
+			t.EndRow()
+		}
+	}
+
+	return t.Render()
+}
+
+// reactionUsers lists who reacted, noting any users beyond those fetched
+func reactionUsers(rg api.ReactionGroup) string {
+	logins := make([]string, len(rg.Users.Nodes))
+	for i, u := range rg.Users.Nodes {
+		logins[i] = u.Login
+	}
+	users := strings.Join(logins, ", ")
+	if more := rg.Users.TotalCount - len(logins); more > 0 {
+		users += fmt.Sprintf(" and %d more", more)
+	}
+	return users
+}
+
+// reactionTime formats when a reaction was first added
+func reactionTime(rg api.ReactionGroup, terminal bool) string {
+	if rg.CreatedAt == nil {
+		return ""
+	}
+	if terminal {
+		return rg.CreatedAt.UTC().Format("2006-01-02 15:04 UTC")
+	}
+	return rg.CreatedAt.UTC().Format(time.RFC3339)
+}
+
+func commentReactionsToJSON(comments []api.Comment) []jsonCommentReactions {
+	result := make([]jsonCommentReactions, len(comments))
+	for i, c := range comments {
+		jc := jsonCommentReactions{
+			Comment:   c.ID,
+			Author:    c.Author.Login,
+			URL:       c.URL,
+			Reactions: make([]jsonReactionGroup, len(c.ReactionGroups)),
+		}
+		for j, rg := range c.ReactionGroups {
+			users := make([]string, len(rg.Users.Nodes))
+			for k, u := range rg.Users.Nodes {
+				users[k] = u.Login
+			}
+			jc.Reactions[j] = jsonReactionGroup{
+				Content:          rg.Content,
+				Emoji:            contentToEmoji(rg.Content),
+				Count:            rg.Users.TotalCount,
+				Users:            users,
+				CreatedAt:        rg.CreatedAt,
+				ViewerHasReacted: rg.ViewerHasReacted,
+			}
+		}
+		result[i] = jc
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const reactionsComment = `{"data":{"node":{"__typename":"PullRequestReviewComment","id":"PRRC_1","url":"https://github.com/owner/repo/pull/1#discussion_r101","body":"Use a constant","author":{"login":"reviewer"},"path":"main.go",
+  "reactionGroups":[
+    {"content":"THUMBS_UP","createdAt":"2024-01-02T05:00:00Z","users":{"totalCount":3,"nodes":[{"login":"alice"},{"login":"bob"}]},"viewerHasReacted":true},
+    {"content":"EYES","createdAt":"2024-01-03T05:00:00Z","users":{"totalCount":1,"nodes":[{"login":"author"}]},"viewerHasReacted":false},
+    {"content":"HEART","createdAt":null,"users":{"totalCount":0,"nodes":[]},"viewerHasReacted":false}
+  ]}}}`
+
+func TestReactions(t *testing.T) {
+	t.Run("tsv", func(t *testing.T) {
+		gh := newFakeGitHub(t, map[string]string{"GetReactions": reactionsComment})
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "reactions", "PRRC_1"); err != nil {
+			t.Fatalf("reactions: %v", err)
+		}
+		assertGolden(t, "reactions_tsv", out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		gh := newFakeGitHub(t, map[string]string{"GetReactions": reactionsComment})
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "reactions", "PRRC_1", "--json"); err != nil {
+			t.Fatalf("reactions --json: %v", err)
+		}
+		var got []jsonCommentReactions
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("parse output: %v\n%s", err, out.String())
+		}
+		if len(got) != 1 || len(got[0].Reactions) != 2 {
+			t.Fatalf("output = %s", out.String())
+		}
+		thumbs := got[0].Reactions[0]
+		if thumbs.Emoji != "👍" || thumbs.Count != 3 || strings.Join(thumbs.Users, ",") != "alice,bob" || thumbs.CreatedAt == nil || !thumbs.ViewerHasReacted {
+			t.Errorf("reaction = %+v", thumbs)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		gh := newFakeGitHub(t, map[string]string{"GetReactions": `{"data":{"node":{"__typename":"IssueComment","id":"IC_1","reactionGroups":[]}}}`})
+		f, out, _ := newTestFactory(t, gh)
+
+		if err := runCommand(t, f, "reactions", "IC_1"); err != nil {
+			t.Fatalf("reactions: %v", err)
+		}
+		if out.String() != "No reactions on IC_1\n" {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		f, _, _ := newTestFactory(t, newFakeGitHub(t, nil))
+		if err := runCommand(t, f, "reactions", "123"); err == nil {
+			t.Error("reactions 123: error = nil")
+		}
+	})
+}
+
+func TestListThreadsReactedBy(t *testing.T) {
+	gh := newFakeGitHub(t, map[string]string{"ListThreads": `{"data":{"repository":{"pullRequest":{"number":1,"reviewThreads":{"nodes":[
+  {"id":"PRRT_a","path":"main.go","line":7,"comments":{"nodes":[{"id":"PRRC_1","body":"Use a constant","author":{"login":"reviewer"},
+    "reactionGroups":[{"content":"THUMBS_UP","users":{"totalCount":1,"nodes":[{"login":"alice"}]}}]}]}},
+  {"id":"PRRT_b","path":"util.go","line":3,"comments":{"nodes":[{"id":"PRRC_3","body":"Typo","author":{"login":"reviewer"},"reactionGroups":[]}]}},
+  {"id":"PRRT_c","path":"util.go","line":9,"comments":{"nodes":[{"id":"PRRC_4","body":"Nice","author":{"login":"reviewer"},
+    "reactionGroups":[{"content":"HEART","users":{"totalCount":25,"nodes":[{"login":"bob"}]}}]}]}}
+]}}}}}`,
+		"ListReactors": `{"data":{"node":{"reactions":{"nodes":[{"user":{"login":"bob"}},{"user":{"login":"alice"}}],"pageInfo":{"hasNextPage":false}}}}}`,
+	})
+	f, out, _ := newTestFactory(t, gh)
+
+	if err := runCommand(t, f, "list", "threads", "--reacted-by", "alice", "--json", "id"); err != nil {
+		t.Fatalf("list threads --reacted-by: %v", err)
+	}
+	var got []jsonThread
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("parse output: %v\n%s", err, out.String())
+	}
+	if len(got) != 2 || got[0].ID != "PRRT_a" || got[1].ID != "PRRT_c" {
+		t.Errorf("threads = %+v, want PRRT_a and PRRT_c", got)
+	}
+
+	// Only the reaction with more users than listed is fetched in full
+	if ops := strings.Join(gh.operations(), ","); ops != "ListThreads,ListReactors" {
+		t.Errorf("operations = %s, want ListThreads,ListReactors", ops)
+	}
+	reactors := gh.requests[1]
+	if reactors.Variables["id"] != "PRRC_4" || reactors.Variables["content"] != "HEART" ||
+		!strings.Contains(reactors.Query, "$content:ReactionContent!") {
+		t.Errorf("ListReactors request = %+v", reactors)
+	}
+}
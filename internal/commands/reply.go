@@ -3,10 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
-	"os"
 
-	"github.com/cli/go-gh/v2/pkg/prompter"
 	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +16,7 @@ var replyCmd = &cobra.Command{
 
 Arguments:
   thread-id   Thread ID (PRRT_...), or omit for interactive selection
-  message     Reply message text, or use --editor
+  message     Reply message text, or use --editor or --template
 
 Examples:
   # Interactive mode (prompts for thread and message)
@@ -33,7 +32,17 @@ Examples:
   gh talk reply PRRT_kwDOQN97u85gQeTN "Fixed!" --react 👍 --resolve
 
   # Using editor
-  gh talk reply PRRT_kwDOQN97u85gQeTN --editor`,
+  gh talk reply PRRT_kwDOQN97u85gQeTN --editor
+
+  # From a saved template
+  gh talk reply PRRT_kwDOQN97u85gQeTN --template fixed
+  gh talk reply PRRT_kwDOQN97u85gQeTN --template followup --var issue=42
+
+  # Chain a follow-up using the new comment's ID
+  gh talk reply PRRT_kwDOQN97u85gQeTN "Fixed!" --json | jq -r .comment.id
+
+  # Show the mutations that would be sent
+  gh talk reply PRRT_kwDOQN97u85gQeTN "Fixed!" --react 👍 --resolve --dry-run`,
 	Args: cobra.RangeArgs(0, 2),
 	RunE: runReply,
 }
@@ -43,8 +52,11 @@ func init() {
 	replyCmd.Flags().Bool("resolve", false, "Resolve thread after replying")
 	replyCmd.Flags().StringP("message", "m", "", "Message text (alternative to positional argument)")
 	replyCmd.Flags().String("react", "", "Add reaction to original comment (emoji or name)")
+	replyCmd.Flags().Bool("json", false, "Output the new comment, reaction and thread state as JSON")
+	replyCmd.Flags().StringP("template", "t", "", "Reply with a saved template (see 'gh talk template')")
+	replyCmd.Flags().StringArray("var", nil, "Template variable as NAME=VALUE (repeatable)")
 
-	replyCmd.MarkFlagsMutuallyExclusive("editor", "message")
+	replyCmd.MarkFlagsMutuallyExclusive("editor", "message", "template")
 }
 
 func runReply(cmd *cobra.Command, args []string) error {
@@ -66,7 +78,7 @@ func runReply(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		id, err := selectThreadInteractive(ctx, owner, name, prNum)
+		id, err := selectThreadInteractive(ctx, cmd, owner, name, prNum)
 		if err != nil {
 			return err
 		}
@@ -90,6 +102,17 @@ func runReply(cmd *cobra.Command, args []string) error {
 		message = args[1]
 	}
 
+	templateName, _ := cmd.Flags().GetString("template")
+	if templateName != "" && message != "" {
+		return fmt.Errorf("cannot use --template with a message")
+	}
+
+	// Create API client
+	client, err := newMutationClient(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Get message if not already set
 	if message == "" {
 		useEditor, _ := cmd.Flags().GetBool("editor")
@@ -97,6 +120,12 @@ func runReply(cmd *cobra.Command, args []string) error {
 
 		if msgFlag != "" {
 			message = msgFlag
+		} else if templateName != "" {
+			msg, err := templateReply(ctx, cmd, client, threadID, templateName)
+			if err != nil {
+				return err
+			}
+			message = msg
 		} else if useEditor {
 			msg, err := openEditor()
 			if err != nil {
@@ -104,9 +133,8 @@ func runReply(cmd *cobra.Command, args []string) error {
 			}
 			message = msg
 		} else {
-			// Prompt for message
-			p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
-			msg, err := p.Input("Reply message:", "")
+			// Prompt for message, offering saved templates
+			msg, err := promptReply(ctx, client, threadID)
 			if err != nil {
 				return fmt.Errorf("failed to get message: %w", err)
 			}
@@ -119,19 +147,26 @@ func runReply(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("message cannot be empty")
 	}
 
-	// Create API client
-	client, err := api.NewClient()
-	if err != nil {
-		return err
+	if dryRunFormat(cmd) != "" {
+		operations, err := replyOperations(ctx, cmd, client, threadID, message)
+		if err != nil {
+			return err
+		}
+		return runDryRun(ctx, cmd, client, nil, operations)
 	}
 
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
 	// Post reply
-	err = client.ReplyToThread(ctx, threadID, message)
+	comment, err := client.ReplyToThread(ctx, threadID, message)
 	if err != nil {
 		return err
 	}
+	output := replyOutput{ThreadID: threadID, Comment: commentToJSON(comment)}
 
-	fmt.Printf("✓ Replied to thread %s\n", threadID)
+	if !jsonOutput {
+		fmt.Fprintf(factory.IOStreams.Out, "✓ Replied to thread %s\n", threadID)
+	}
 
 	// Add reaction if requested
 	reactEmoji, _ := cmd.Flags().GetString("react")
@@ -163,13 +198,24 @@ func runReply(cmd *cobra.Command, args []string) error {
 					return fmt.Errorf("replied successfully but invalid emoji: %w", err)
 				}
 
-				err = client.AddReaction(ctx, firstCommentID, content)
+				emoji := contentToEmoji(content)
+				if viewerReacted(&t.Comments[0], content) {
+					output.Reaction = &jsonReaction{Content: content, Emoji: emoji, Subject: firstCommentID, Skipped: true}
+					if !jsonOutput {
+						fmt.Fprintf(factory.IOStreams.Out, "- Already reacted %s to original comment\n", emoji)
+					}
+					break
+				}
+
+				reaction, err := client.AddReaction(ctx, firstCommentID, content)
 				if err != nil {
 					return fmt.Errorf("replied successfully but failed to add reaction: %w", err)
 				}
+				output.Reaction = reactionToJSON(reaction, false)
 
-				emoji := contentToEmoji(content)
-				fmt.Printf("✓ Added %s reaction to original comment\n", emoji)
+				if !jsonOutput {
+					fmt.Fprintf(factory.IOStreams.Out, "✓ Added %s reaction to original comment\n", emoji)
+				}
 				break
 			}
 		}
@@ -178,19 +224,62 @@ func runReply(cmd *cobra.Command, args []string) error {
 	// Resolve if requested
 	shouldResolve, _ := cmd.Flags().GetBool("resolve")
 	if shouldResolve {
-		err = client.ResolveThread(ctx, threadID)
+		thread, err := client.ResolveThread(ctx, threadID)
 		if err != nil {
 			return fmt.Errorf("replied successfully but failed to resolve: %w", err)
 		}
-		fmt.Printf("✓ Resolved thread\n")
+		output.Thread = threadStateToJSON(thread)
+		if !jsonOutput {
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Resolved thread\n")
+		}
 	}
 
+	if jsonOutput {
+		return printJSON(output)
+	}
 	return nil
 }
 
-func selectThreadInteractive(ctx context.Context, owner, name string, pr int) (string, error) {
+// replyOutput is the --json output of reply
+type replyOutput struct {
+	ThreadID string        `json:"threadId"`
+	Comment  *jsonComment  `json:"comment"`
+	Reaction *jsonReaction `json:"reaction,omitempty"`
+	// Thread is set when the thread was resolved with --resolve
+	Thread *jsonThreadState `json:"thread,omitempty"`
+}
+
+// replyOperations returns the operations a reply sends, including the
+// --react and --resolve follow-ups
+func replyOperations(ctx context.Context, cmd *cobra.Command, client *api.Client, threadID, message string) ([]ops.Operation, error) {
+	operations := []ops.Operation{{Op: ops.Reply, Thread: threadID, Body: message}}
+
+	reactEmoji, _ := cmd.Flags().GetString("react")
+	if reactEmoji != "" {
+		content, err := parseEmoji(reactEmoji)
+		if err != nil {
+			return nil, err
+		}
+		thread, err := client.GetThread(ctx, threadID)
+		if err != nil {
+			return nil, err
+		}
+		if len(thread.Comments) > 0 {
+			operations = append(operations, ops.Operation{Op: ops.React, Comment: thread.Comments[0].ID, Reaction: content})
+		}
+	}
+
+	shouldResolve, _ := cmd.Flags().GetBool("resolve")
+	if shouldResolve {
+		operations = append(operations, ops.Operation{Op: ops.Resolve, Thread: threadID})
+	}
+
+	return operations, nil
+}
+
+func selectThreadInteractive(ctx context.Context, cmd *cobra.Command, owner, name string, pr int) (string, error) {
 	// Create client
-	client, err := api.NewClient()
+	client, err := newClient(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -224,7 +313,7 @@ func selectThreadInteractive(ctx context.Context, owner, name string, pr int) (s
 	}
 
 	// Prompt
-	p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+	p := factory.Prompter()
 	idx, err := p.Select("Select thread:", "", options)
 	if err != nil {
 		return "", err
@@ -3,10 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
-	"os"
 
-	"github.com/cli/go-gh/v2/pkg/prompter"
 	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
 	"github.com/spf13/cobra"
 )
 
@@ -29,7 +28,13 @@ Examples:
   gh talk resolve PRRT_abc123 PRRT_def456 PRRT_ghi789
 
   # With message first
-  gh talk resolve PRRT_abc123 --message "Fixed in commit abc123"`,
+  gh talk resolve PRRT_abc123 --message "Fixed in commit abc123"
+
+  # Resolve threads whose code changed since the comment
+  gh talk resolve --if-addressed
+
+  # Show the mutations that would be sent
+  gh talk resolve PRRT_abc123 --message "Fixed" --dry-run`,
 	Args: cobra.MinimumNArgs(0),
 	RunE: runResolve,
 }
@@ -58,8 +63,11 @@ Examples:
 func init() {
 	resolveCmd.Flags().StringP("message", "m", "", "Message to post before resolving")
 	resolveCmd.Flags().BoolP("yes", "y", false, "Skip confirmation for multiple threads")
+	resolveCmd.Flags().Bool("if-addressed", false, "Resolve unresolved threads whose lines changed since the comment")
+	resolveCmd.Flags().Bool("json", false, "Output the resolved threads as JSON")
 
 	unresolveCmd.Flags().BoolP("yes", "y", false, "Skip confirmation for multiple threads")
+	unresolveCmd.Flags().Bool("json", false, "Output the unresolved threads as JSON")
 }
 
 func runResolve(cmd *cobra.Command, args []string) error {
@@ -67,7 +75,28 @@ func runResolve(cmd *cobra.Command, args []string) error {
 
 	var threadIDs []string
 
-	if len(args) == 0 {
+	ifAddressed, _ := cmd.Flags().GetBool("if-addressed")
+
+	if ifAddressed {
+		if len(args) > 0 {
+			return fmt.Errorf("--if-addressed cannot be combined with thread IDs")
+		}
+
+		owner, name, err := getRepository(cmd)
+		if err != nil {
+			return err
+		}
+		prNum, err := getCurrentPR(cmd)
+		if err != nil {
+			return err
+		}
+
+		ids, err := selectAddressedThreads(ctx, cmd, owner, name, prNum)
+		if err != nil {
+			return err
+		}
+		threadIDs = ids
+	} else if len(args) == 0 {
 		// Interactive selection
 		owner, name, err := getRepository(cmd)
 		if err != nil {
@@ -78,7 +107,7 @@ func runResolve(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		ids, err := selectThreadsInteractive(ctx, owner, name, prNum, false)
+		ids, err := selectThreadsInteractive(ctx, cmd, owner, name, prNum, false)
 		if err != nil {
 			return err
 		}
@@ -98,11 +127,30 @@ func runResolve(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no threads selected")
 	}
 
-	// Confirm for multiple threads
-	if len(threadIDs) > 1 {
+	message, _ := cmd.Flags().GetString("message")
+
+	if dryRunFormat(cmd) != "" {
+		client, err := newMutationClient(cmd)
+		if err != nil {
+			return err
+		}
+		var operations []ops.Operation
+		if message != "" {
+			for _, id := range threadIDs {
+				operations = append(operations, ops.Operation{Op: ops.Reply, Thread: id, Body: message})
+			}
+		}
+		for _, id := range threadIDs {
+			operations = append(operations, ops.Operation{Op: ops.Resolve, Thread: id})
+		}
+		return runDryRun(ctx, cmd, client, nil, operations)
+	}
+
+	// Confirm for multiple threads, and always for detected threads
+	if len(threadIDs) > 1 || ifAddressed {
 		skipConfirm, _ := cmd.Flags().GetBool("yes")
 		if !skipConfirm {
-			p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+			p := factory.Prompter()
 			confirmed, err := p.Confirm(fmt.Sprintf("Resolve %d threads?", len(threadIDs)), false)
 			if err != nil || !confirmed {
 				return fmt.Errorf("cancelled")
@@ -111,29 +159,45 @@ func runResolve(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create client
-	client, err := api.NewClient()
+	client, err := newClient(cmd)
 	if err != nil {
 		return err
 	}
 
 	// Post message first if provided
-	message, _ := cmd.Flags().GetString("message")
+	replies := make(map[string]*api.Comment)
 	if message != "" {
 		for _, id := range threadIDs {
-			if err := client.ReplyToThread(ctx, id, message); err != nil {
+			reply, err := client.ReplyToThread(ctx, id, message)
+			if err != nil {
 				return fmt.Errorf("failed to add message to %s: %w", id, err)
 			}
+			replies[id] = reply
 		}
 	}
 
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
 	// Resolve each thread
+	states := make([]*jsonThreadState, 0, len(threadIDs))
 	for _, id := range threadIDs {
-		if err := client.ResolveThread(ctx, id); err != nil {
+		thread, err := client.ResolveThread(ctx, id)
+		if err != nil {
 			return fmt.Errorf("failed to resolve %s: %w", id, err)
 		}
-		fmt.Printf("✓ Resolved %s\n", id)
+		state := threadStateToJSON(thread)
+		if reply, ok := replies[id]; ok {
+			state.Reply = commentToJSON(reply)
+		}
+		states = append(states, state)
+		if !jsonOutput {
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Resolved %s\n", id)
+		}
 	}
 
+	if jsonOutput {
+		return printJSON(states)
+	}
 	return nil
 }
 
@@ -153,7 +217,7 @@ func runUnresolve(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		ids, err := selectThreadsInteractive(ctx, owner, name, prNum, true)
+		ids, err := selectThreadsInteractive(ctx, cmd, owner, name, prNum, true)
 		if err != nil {
 			return err
 		}
@@ -172,11 +236,23 @@ func runUnresolve(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no threads selected")
 	}
 
+	if dryRunFormat(cmd) != "" {
+		client, err := newMutationClient(cmd)
+		if err != nil {
+			return err
+		}
+		operations := make([]ops.Operation, len(threadIDs))
+		for i, id := range threadIDs {
+			operations[i] = ops.Operation{Op: ops.Unresolve, Thread: id}
+		}
+		return runDryRun(ctx, cmd, client, nil, operations)
+	}
+
 	// Confirm for multiple
 	if len(threadIDs) > 1 {
 		skipConfirm, _ := cmd.Flags().GetBool("yes")
 		if !skipConfirm {
-			p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+			p := factory.Prompter()
 			confirmed, err := p.Confirm(fmt.Sprintf("Unresolve %d threads?", len(threadIDs)), false)
 			if err != nil || !confirmed {
 				return fmt.Errorf("cancelled")
@@ -184,23 +260,73 @@ func runUnresolve(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client, err := api.NewClient()
+	client, err := newClient(cmd)
 	if err != nil {
 		return err
 	}
 
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	states := make([]*jsonThreadState, 0, len(threadIDs))
 	for _, id := range threadIDs {
-		if err := client.UnresolveThread(ctx, id); err != nil {
+		thread, err := client.UnresolveThread(ctx, id)
+		if err != nil {
 			return fmt.Errorf("failed to unresolve %s: %w", id, err)
 		}
-		fmt.Printf("✓ Unresolved %s\n", id)
+		states = append(states, threadStateToJSON(thread))
+		if !jsonOutput {
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Unresolved %s\n", id)
+		}
 	}
 
+	if jsonOutput {
+		return printJSON(states)
+	}
 	return nil
 }
 
-func selectThreadsInteractive(ctx context.Context, owner, name string, pr int, onlyResolved bool) ([]string, error) {
-	client, err := api.NewClient()
+// selectAddressedThreads returns unresolved threads whose lines changed
+// after the thread was started, printing them for confirmation
+func selectAddressedThreads(ctx context.Context, cmd *cobra.Command, owner, name string, pr int) ([]string, error) {
+	client, err := newClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequest, err := client.GetPullRequest(ctx, owner, name, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := make([]api.Thread, 0)
+	for _, t := range pullRequest.ReviewThreads {
+		if !t.IsResolved {
+			unresolved = append(unresolved, t)
+		}
+	}
+
+	addressed := filterChangedThreads(unresolved, newChangeDetector(pullRequest.HeadRefOid))
+	if len(addressed) == 0 {
+		return nil, fmt.Errorf("no unresolved threads with changed code found")
+	}
+
+	fmt.Fprintf(factory.IOStreams.Out, "Threads with code changed since the comment:\n\n")
+	ids := make([]string, len(addressed))
+	for i, t := range addressed {
+		preview := ""
+		if len(t.Comments) > 0 {
+			preview = truncate(t.Comments[0].Body, 50)
+		}
+		fmt.Fprintf(factory.IOStreams.Out, "  %s %s:%d - %s\n", t.ID, t.Path, t.Line, preview)
+		ids[i] = t.ID
+	}
+	fmt.Fprintln(factory.IOStreams.Out)
+
+	return ids, nil
+}
+
+func selectThreadsInteractive(ctx context.Context, cmd *cobra.Command, owner, name string, pr int, onlyResolved bool) ([]string, error) {
+	client, err := newClient(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -230,10 +356,7 @@ func selectThreadsInteractive(ctx context.Context, owner, name string, pr int, o
 	// Build options
 	options := make([]string, len(filtered))
 	for i, t := range filtered {
-		preview := ""
-		if len(t.Comments) > 0 {
-			preview = truncate(t.Comments[0].Body, 50)
-		}
+		preview := truncate(threadPreview(t), 50)
 		status := "○"
 		if t.IsResolved {
 			status = "✓"
@@ -242,7 +365,7 @@ func selectThreadsInteractive(ctx context.Context, owner, name string, pr int, o
 	}
 
 	// Multi-select
-	p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+	p := factory.Prompter()
 	indices, err := p.MultiSelect("Select threads:", nil, options)
 	if err != nil {
 		return nil, err
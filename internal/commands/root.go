@@ -27,7 +27,7 @@ Never leave the terminal for code review conversations.`,
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+		fmt.Fprintf(factory.IOStreams.ErrOut, "✗ %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -37,6 +37,8 @@ func init() {
 	rootCmd.PersistentFlags().StringP("repo", "R", "", "Repository (OWNER/REPO)")
 	rootCmd.PersistentFlags().Int("pr", 0, "PR number")
 	rootCmd.PersistentFlags().Int("issue", 0, "Issue number")
+	rootCmd.PersistentFlags().String("dry-run", "", "Check targets and print the mutations that would be sent, without sending them (text, json)")
+	rootCmd.PersistentFlags().Lookup("dry-run").NoOptDefVal = "text"
 
 	// Add subcommands
 	rootCmd.AddCommand(listCmd)
@@ -44,8 +46,22 @@ func init() {
 	rootCmd.AddCommand(resolveCmd)
 	rootCmd.AddCommand(unresolveCmd)
 	rootCmd.AddCommand(reactCmd)
+	rootCmd.AddCommand(reactionsCmd)
 	rootCmd.AddCommand(hideCmd)
 	rootCmd.AddCommand(unhideCmd)
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(syncCommitsCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(dashboardCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(applyPlanCmd)
+	rootCmd.AddCommand(batchCmd)
+	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(deferCmd)
 }
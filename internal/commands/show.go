@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/format"
 	"github.com/spf13/cobra"
 )
 
@@ -13,16 +14,30 @@ var showCmd = &cobra.Command{
 	Short: "Show thread details",
 	Long: `Show detailed information about a review thread.
 
+Hidden comments are collapsed to the reason they were hidden; use
+--include-hidden to show them in full.
+
 Arguments:
   thread-id   Thread ID (PRRT_...)
 
 Examples:
   # Show thread details
-  gh talk show PRRT_kwDOQN97u85gQeTN`,
+  gh talk show PRRT_kwDOQN97u85gQeTN
+
+  # Include the bodies of hidden comments
+  gh talk show PRRT_kwDOQN97u85gQeTN --include-hidden
+
+  # Show a thread from a saved snapshot
+  gh talk show PRRT_kwDOQN97u85gQeTN --from-snapshot review.json`,
 	Args: cobra.ExactArgs(1),
 	RunE: runShow,
 }
 
+func init() {
+	showCmd.Flags().Bool("include-hidden", false, "Show hidden comments in full")
+	addSnapshotFlag(showCmd)
+}
+
 func runShow(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -31,28 +46,12 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Get repository context (needed to fetch thread details)
-	owner, name, err := getRepository(cmd)
-	if err != nil {
-		return err
-	}
-
-	prNum, err := getCurrentPR(cmd)
-	if err != nil {
-		return err
-	}
-
-	// Create client
-	client, err := api.NewClient()
-	if err != nil {
-		return err
-	}
-
 	// Fetch all threads to find the one we want
-	threads, err := client.ListThreads(ctx, owner, name, prNum)
+	pr, err := fetchPullRequest(ctx, cmd)
 	if err != nil {
 		return err
 	}
+	threads := pr.ReviewThreads
 
 	// Find thread
 	var thread *api.Thread
@@ -68,55 +67,72 @@ func runShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Display thread details
-	fmt.Printf("Thread: %s\n", thread.ID)
-	fmt.Printf("File:   %s:%d\n", thread.Path, thread.Line)
-	fmt.Printf("Status: ")
+	fmt.Fprintf(factory.IOStreams.Out, "Thread: %s\n", thread.ID)
+	if handle := threadHandle(*thread); handle != "" {
+		fmt.Fprintf(factory.IOStreams.Out, "Handle: %s\n", handle)
+	}
+	fmt.Fprintf(factory.IOStreams.Out, "File:   %s:%d\n", thread.Path, thread.Line)
+	fmt.Fprintf(factory.IOStreams.Out, "Status: ")
 	if thread.IsResolved {
-		fmt.Printf("✓ RESOLVED")
+		fmt.Fprintf(factory.IOStreams.Out, "✓ RESOLVED")
 		if thread.ResolvedBy != nil {
-			fmt.Printf(" by @%s", thread.ResolvedBy.Login)
+			fmt.Fprintf(factory.IOStreams.Out, " by @%s", thread.ResolvedBy.Login)
 		}
-		fmt.Println()
+		fmt.Fprintln(factory.IOStreams.Out)
 	} else {
-		fmt.Println("○ OPEN")
+		fmt.Fprintln(factory.IOStreams.Out, "○ OPEN")
 	}
 
 	if thread.IsOutdated {
-		fmt.Println("⚠️  Outdated (code has changed since comment)")
+		fmt.Fprintln(factory.IOStreams.Out, "⚠️  Outdated (code has changed since comment)")
 	}
 
-	fmt.Printf("\nConversation (%d comments):\n\n", len(thread.Comments))
+	includeHidden, _ := cmd.Flags().GetBool("include-hidden")
+
+	fmt.Fprintf(factory.IOStreams.Out, "\nConversation (%d comments):\n\n", len(thread.Comments))
 
 	for i, comment := range thread.Comments {
-		fmt.Printf("─────────────────────────────────────────────────────\n")
-		fmt.Printf("[%d] %s\n", i+1, comment.ID)
-		fmt.Printf("@%s", comment.Author.Login)
+		fmt.Fprintf(factory.IOStreams.Out, "─────────────────────────────────────────────────────\n")
+		fmt.Fprintf(factory.IOStreams.Out, "[%d] %s\n", i+1, comment.ID)
+		fmt.Fprintf(factory.IOStreams.Out, "@%s", comment.Author.Login)
 		if comment.ReplyTo != nil {
-			fmt.Printf(" (in reply to comment %s)", comment.ReplyTo.ID)
+			fmt.Fprintf(factory.IOStreams.Out, " (in reply to comment %s)", comment.ReplyTo.ID)
+		}
+		if comment.IsMinimized {
+			fmt.Fprintf(factory.IOStreams.Out, " · hidden (%s)", format.HiddenReason(comment))
+		}
+		fmt.Fprintf(factory.IOStreams.Out, "\n\n")
+
+		// Hidden comments are collapsed unless asked for
+		if comment.IsMinimized && !includeHidden {
+			fmt.Fprintf(factory.IOStreams.Out, "▸ Hidden comment (gh talk unhide %s to restore)\n", comment.ID)
+			if i < len(thread.Comments)-1 {
+				fmt.Fprintln(factory.IOStreams.Out)
+			}
+			continue
 		}
-		fmt.Printf("\n\n")
-		fmt.Println(comment.Body)
+		fmt.Fprintln(factory.IOStreams.Out, comment.Body)
 
 		// Show reactions
 		if len(comment.ReactionGroups) > 0 {
-			fmt.Printf("\nReactions: ")
+			fmt.Fprintf(factory.IOStreams.Out, "\nReactions: ")
 			for j, rg := range comment.ReactionGroups {
 				if j > 0 {
-					fmt.Printf(" ")
+					fmt.Fprintf(factory.IOStreams.Out, " ")
 				}
 				emoji := contentToEmoji(rg.Content)
-				fmt.Printf("%s %d", emoji, rg.Users.TotalCount)
+				fmt.Fprintf(factory.IOStreams.Out, "%s %d", emoji, rg.Users.TotalCount)
 			}
-			fmt.Println()
+			fmt.Fprintln(factory.IOStreams.Out)
 		}
 
 		if i < len(thread.Comments)-1 {
-			fmt.Println()
+			fmt.Fprintln(factory.IOStreams.Out)
 		}
 	}
 
-	fmt.Printf("─────────────────────────────────────────────────────\n")
-	fmt.Printf("\nTip: Use comment IDs above for reactions (gh talk react <id> <emoji>)\n")
+	fmt.Fprintf(factory.IOStreams.Out, "─────────────────────────────────────────────────────\n")
+	fmt.Fprintf(factory.IOStreams.Out, "\nTip: Use comment IDs above for reactions (gh talk react <id> <emoji>)\n")
 
 	return nil
 }
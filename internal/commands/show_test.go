@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShowCollapsesHiddenComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantBody bool
+	}{
+		{"collapsed", []string{"show", "PRRT_a"}, false},
+		{"include hidden", []string{"show", "PRRT_a", "--include-hidden"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, map[string]string{"ListThreads": hiddenThreads})
+			f, out, _ := newTestFactory(t, gh)
+
+			if err := runCommand(t, f, tt.args...); err != nil {
+				t.Fatalf("show: %v", err)
+			}
+			got := out.String()
+			if !strings.Contains(got, "@spammer · hidden (spam)") {
+				t.Errorf("output does not mark the hidden comment:\n%s", got)
+			}
+			if strings.Contains(got, "Buy cheap watches") != tt.wantBody {
+				t.Errorf("hidden body shown = %v, want %v:\n%s", !tt.wantBody, tt.wantBody, got)
+			}
+			if !strings.Contains(got, "Reported") {
+				t.Errorf("visible reply missing:\n%s", got)
+			}
+		})
+	}
+}
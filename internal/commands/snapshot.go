@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save PR conversations for offline use",
+	Long: `Save the full thread model of a pull request to a file.
+
+Read commands (list threads, show, status, stats, export) accept
+--from-snapshot to work from a saved file without network access.`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save a snapshot of a PR conversation",
+	Long: `Save the review threads and comments of a pull request to a JSON file.
+
+The file holds the GitHub API response, so raw responses captured
+elsewhere can be used as snapshots too.
+
+Examples:
+  # Save the current PR
+  gh talk snapshot save -o review.json
+
+  # Save a specific PR and read it back offline
+  gh talk snapshot save --pr 123 -o pr-123.json
+  gh talk list threads --all --from-snapshot pr-123.json`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshotSave,
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+
+	snapshotSaveCmd.Flags().StringP("output", "o", "", "File to write (required)")
+	_ = snapshotSaveCmd.MarkFlagRequired("output")
+}
+
+func runSnapshotSave(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	owner, name, err := getRepository(cmd)
+	if err != nil {
+		return err
+	}
+
+	prNum, err := getCurrentPR(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := client.Snapshot(ctx, owner, name, prNum)
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if err := snapshot.Save(output); err != nil {
+		return err
+	}
+
+	threads := len(snapshot.Data.Repository.PullRequest.ReviewThreads.Nodes)
+	fmt.Fprintf(factory.IOStreams.ErrOut, "✓ Saved %s/%s#%d (%d threads) to %s\n", owner, name, prNum, threads, output)
+	return nil
+}
+
+// addSnapshotFlag adds --from-snapshot to a read command
+func addSnapshotFlag(cmd *cobra.Command) {
+	cmd.Flags().String("from-snapshot", "", "Read from a saved snapshot instead of GitHub")
+}
+
+// fromSnapshot returns the --from-snapshot path, if set
+func fromSnapshot(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("from-snapshot")
+	return path
+}
+
+// fetchPullRequest loads the pull request from --from-snapshot, or from
+// GitHub using the repository and PR flags
+func fetchPullRequest(ctx context.Context, cmd *cobra.Command) (*api.PullRequest, error) {
+	if path := fromSnapshot(cmd); path != "" {
+		snapshot, err := api.LoadSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Raw API responses may not record the repository or number
+		pr := snapshot.PullRequest()
+		if pr.Repository == "" {
+			if owner, name, err := getRepository(cmd); err == nil {
+				pr.Repository = owner + "/" + name
+			}
+		}
+		if pr.Number == 0 {
+			pr.Number, _ = cmd.Flags().GetInt("pr")
+		}
+		return pr, nil
+	}
+
+	owner, name, err := getRepository(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	prNum, err := getCurrentPR(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetPullRequest(ctx, owner, name, prNum)
+}
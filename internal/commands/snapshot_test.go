@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestFetchPullRequestFromSnapshot(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("repo", "R", "", "")
+	cmd.Flags().Int("pr", 0, "")
+	addSnapshotFlag(cmd)
+
+	if err := cmd.Flags().Parse([]string{
+		"--from-snapshot", "../../testdata/pr_with_resolved_threads.json",
+		"--repo", "owner/repo",
+		"--pr", "5",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := fetchPullRequest(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("fetchPullRequest() error = %v", err)
+	}
+
+	if pr.Repository != "owner/repo" || pr.Number != 5 {
+		t.Errorf("got %s#%d, want owner/repo#5", pr.Repository, pr.Number)
+	}
+	if len(pr.ReviewThreads) != 4 {
+		t.Errorf("got %d threads, want 4", len(pr.ReviewThreads))
+	}
+}
@@ -0,0 +1,333 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show review conversation metrics",
+	Long: `Compute review conversation metrics for a pull request, or for all
+pull requests created in a date range.
+
+Metrics:
+  - Time to first response (first reply from someone else)
+  - Threads started per reviewer
+  - Comments per file
+  - Threads resolved by the PR author vs reviewers
+
+Time to resolve and reopen counts are not reported: GitHub's API does
+not record when a review thread was resolved or reopened.
+
+Examples:
+  # Metrics for the current PR
+  gh talk stats
+
+  # Metrics for a specific PR as JSON
+  gh talk stats --pr 123 --format json
+
+  # All PRs created in January, as CSV
+  gh talk stats --since 2025-01-01 --until 2025-01-31 --format csv`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().String("since", "", "Include PRs created on or after this date (YYYY-MM-DD)")
+	statsCmd.Flags().String("until", "", "Include PRs created on or before this date (YYYY-MM-DD)")
+	statsCmd.Flags().Int("limit", 100, "Maximum PRs to include for a date range")
+	statsCmd.Flags().String("format", "table", "Output format (table, json, csv)")
+	addSnapshotFlag(statsCmd)
+
+	statsCmd.MarkFlagsMutuallyExclusive("from-snapshot", "since")
+	statsCmd.MarkFlagsMutuallyExclusive("from-snapshot", "until")
+}
+
+// durationStats summarizes a set of durations
+type durationStats struct {
+	Count  int
+	Median time.Duration
+	Mean   time.Duration
+}
+
+// MarshalJSON reports durations in whole seconds
+func (d durationStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count         int   `json:"count"`
+		MedianSeconds int64 `json:"medianSeconds"`
+		MeanSeconds   int64 `json:"meanSeconds"`
+	}{d.Count, int64(d.Median.Seconds()), int64(d.Mean.Seconds())})
+}
+
+// reviewStats holds conversation metrics for one or more PRs
+type reviewStats struct {
+	PullRequests        int            `json:"pullRequests"`
+	Threads             int            `json:"threads"`
+	ResolvedThreads     int            `json:"resolvedThreads"`
+	Comments            int            `json:"comments"`
+	TimeToFirstResponse durationStats  `json:"timeToFirstResponse"`
+	ThreadsPerReviewer  map[string]int `json:"threadsPerReviewer"`
+	CommentsPerFile     map[string]int `json:"commentsPerFile"`
+	ResolvedByAuthor    int            `json:"resolvedByAuthor"`
+	ResolvedByReviewer  int            `json:"resolvedByReviewer"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" && format != "csv" {
+		return fmt.Errorf("unknown format: %s\n\nValid formats: table, json, csv", format)
+	}
+
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+
+	var prs []api.PullRequest
+	if since != "" || until != "" {
+		owner, name, err := getRepository(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		created, err := createdQualifier(since, until)
+		if err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 1 || limit > 100 {
+			return fmt.Errorf("limit must be between 1 and 100")
+		}
+
+		prs, err = client.SearchPullRequests(ctx, fmt.Sprintf("repo:%s/%s %s", owner, name, created), limit)
+		if err != nil {
+			return err
+		}
+	} else {
+		pr, err := fetchPullRequest(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		prs = []api.PullRequest{*pr}
+	}
+
+	stats := computeStats(prs)
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(factory.IOStreams.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	case "csv":
+		return writeStatsCSV(factory.IOStreams.Out, stats)
+	default:
+		printStats(stats)
+		return nil
+	}
+}
+
+// createdQualifier builds a search created: qualifier from optional dates
+func createdQualifier(since, until string) (string, error) {
+	for _, d := range []string{since, until} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return "", fmt.Errorf("invalid date: %s\n\nUse YYYY-MM-DD", d)
+		}
+	}
+
+	switch {
+	case since != "" && until != "":
+		return fmt.Sprintf("created:%s..%s", since, until), nil
+	case since != "":
+		return "created:>=" + since, nil
+	default:
+		return "created:<=" + until, nil
+	}
+}
+
+// computeStats computes conversation metrics across pull requests
+func computeStats(prs []api.PullRequest) reviewStats {
+	stats := reviewStats{
+		PullRequests:       len(prs),
+		ThreadsPerReviewer: make(map[string]int),
+		CommentsPerFile:    make(map[string]int),
+	}
+
+	var firstResponses []time.Duration
+
+	for _, pr := range prs {
+		for _, t := range pr.ReviewThreads {
+			stats.Threads++
+			stats.Comments += len(t.Comments)
+			if t.Path != "" {
+				stats.CommentsPerFile[t.Path] += len(t.Comments)
+			}
+
+			if len(t.Comments) == 0 {
+				continue
+			}
+
+			first := t.Comments[0]
+			stats.ThreadsPerReviewer[first.Author.Login]++
+
+			// First reply from someone other than the thread starter
+			for _, c := range t.Comments[1:] {
+				if c.Author.Login != first.Author.Login {
+					firstResponses = append(firstResponses, c.CreatedAt.Sub(first.CreatedAt))
+					break
+				}
+			}
+
+			if t.IsResolved {
+				stats.ResolvedThreads++
+				if t.ResolvedBy != nil {
+					if t.ResolvedBy.Login == pr.Author.Login {
+						stats.ResolvedByAuthor++
+					} else {
+						stats.ResolvedByReviewer++
+					}
+				}
+			}
+		}
+	}
+
+	stats.TimeToFirstResponse = summarizeDurations(firstResponses)
+
+	return stats
+}
+
+// summarizeDurations computes the count, median and mean of durations
+func summarizeDurations(ds []time.Duration) durationStats {
+	if len(ds) == 0 {
+		return durationStats{}
+	}
+
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	return durationStats{
+		Count:  len(sorted),
+		Median: median,
+		Mean:   total / time.Duration(len(sorted)),
+	}
+}
+
+func printStats(stats reviewStats) {
+	fmt.Fprintf(factory.IOStreams.Out, "Pull requests: %d\n\n", stats.PullRequests)
+
+	fmt.Fprintf(factory.IOStreams.Out, "Threads:\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  Total:      %d\n", stats.Threads)
+	fmt.Fprintf(factory.IOStreams.Out, "  Resolved:   %d", stats.ResolvedThreads)
+	if stats.Threads > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, " (%d%%)", stats.ResolvedThreads*100/stats.Threads)
+	}
+	fmt.Fprintf(factory.IOStreams.Out, "\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  Comments:   %d\n", stats.Comments)
+
+	fmt.Fprintf(factory.IOStreams.Out, "\nResponse Times (median / mean):\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  First response: %s\n", formatDurationStats(stats.TimeToFirstResponse))
+
+	resolvedBy := stats.ResolvedByAuthor + stats.ResolvedByReviewer
+	if resolvedBy > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "\nResolved By:\n")
+		fmt.Fprintf(factory.IOStreams.Out, "  Author:     %d (%d%%)\n", stats.ResolvedByAuthor, stats.ResolvedByAuthor*100/resolvedBy)
+		fmt.Fprintf(factory.IOStreams.Out, "  Reviewer:   %d (%d%%)\n", stats.ResolvedByReviewer, stats.ResolvedByReviewer*100/resolvedBy)
+	}
+
+	if len(stats.ThreadsPerReviewer) > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "\nThreads Per Reviewer:\n")
+		for _, kv := range sortedCounts(stats.ThreadsPerReviewer) {
+			fmt.Fprintf(factory.IOStreams.Out, "  @%-20s %d\n", kv.Key, kv.Count)
+		}
+	}
+
+	if len(stats.CommentsPerFile) > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "\nComments Per File:\n")
+		for _, kv := range sortedCounts(stats.CommentsPerFile) {
+			fmt.Fprintf(factory.IOStreams.Out, "  %-40s %d\n", kv.Key, kv.Count)
+		}
+	}
+}
+
+func formatDurationStats(d durationStats) string {
+	if d.Count == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%s / %s (%d threads)", formatAge(d.Median), formatAge(d.Mean), d.Count)
+}
+
+// writeStatsCSV writes metrics as metric,key,value rows
+func writeStatsCSV(out io.Writer, stats reviewStats) error {
+	w := csv.NewWriter(out)
+
+	rows := [][]string{
+		{"metric", "key", "value"},
+		{"pull_requests", "", strconv.Itoa(stats.PullRequests)},
+		{"threads", "", strconv.Itoa(stats.Threads)},
+		{"resolved_threads", "", strconv.Itoa(stats.ResolvedThreads)},
+		{"comments", "", strconv.Itoa(stats.Comments)},
+		{"time_to_first_response_median_seconds", "", strconv.FormatInt(int64(stats.TimeToFirstResponse.Median.Seconds()), 10)},
+		{"time_to_first_response_mean_seconds", "", strconv.FormatInt(int64(stats.TimeToFirstResponse.Mean.Seconds()), 10)},
+		{"resolved_by_author", "", strconv.Itoa(stats.ResolvedByAuthor)},
+		{"resolved_by_reviewer", "", strconv.Itoa(stats.ResolvedByReviewer)},
+	}
+	for _, kv := range sortedCounts(stats.ThreadsPerReviewer) {
+		rows = append(rows, []string{"threads_per_reviewer", kv.Key, strconv.Itoa(kv.Count)})
+	}
+	for _, kv := range sortedCounts(stats.CommentsPerFile) {
+		rows = append(rows, []string{"comments_per_file", kv.Key, strconv.Itoa(kv.Count)})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("write CSV: %w", err)
+	}
+	return nil
+}
+
+type keyCount struct {
+	Key   string
+	Count int
+}
+
+// sortedCounts orders counts by descending count, then key
+func sortedCounts(m map[string]int) []keyCount {
+	counts := make([]keyCount, 0, len(m))
+	for k, v := range m {
+		counts = append(counts, keyCount{k, v})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Key < counts[j].Key
+	})
+	return counts
+}
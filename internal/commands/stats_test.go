@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestComputeStats(t *testing.T) {
+	start := time.Date(2025, 11, 2, 10, 0, 0, 0, time.UTC)
+	comment := func(login string, after time.Duration) api.Comment {
+		return api.Comment{Author: api.User{Login: login}, CreatedAt: start.Add(after)}
+	}
+
+	prs := []api.PullRequest{
+		{
+			Author: api.User{Login: "author"},
+			ReviewThreads: []api.Thread{
+				{
+					Path:       "a.go",
+					IsResolved: true,
+					ResolvedBy: &api.User{Login: "author"},
+					Comments:   []api.Comment{comment("rev1", 0), comment("author", time.Hour), comment("rev1", 3*time.Hour)},
+				},
+				{
+					Path:       "a.go",
+					IsResolved: true,
+					ResolvedBy: &api.User{Login: "rev2"},
+					Comments:   []api.Comment{comment("rev2", 0), comment("rev2", time.Minute), comment("author", 3*time.Hour)},
+				},
+				{
+					Path:     "b.go",
+					Comments: []api.Comment{comment("rev1", 0)},
+				},
+			},
+		},
+	}
+
+	stats := computeStats(prs)
+
+	if stats.Threads != 3 || stats.ResolvedThreads != 2 || stats.Comments != 7 {
+		t.Errorf("counts = %d threads, %d resolved, %d comments", stats.Threads, stats.ResolvedThreads, stats.Comments)
+	}
+	if stats.TimeToFirstResponse.Count != 2 || stats.TimeToFirstResponse.Median != 2*time.Hour {
+		t.Errorf("TimeToFirstResponse = %+v", stats.TimeToFirstResponse)
+	}
+	if stats.ThreadsPerReviewer["rev1"] != 2 || stats.ThreadsPerReviewer["rev2"] != 1 {
+		t.Errorf("ThreadsPerReviewer = %v", stats.ThreadsPerReviewer)
+	}
+	if stats.CommentsPerFile["a.go"] != 6 || stats.CommentsPerFile["b.go"] != 1 {
+		t.Errorf("CommentsPerFile = %v", stats.CommentsPerFile)
+	}
+	if stats.ResolvedByAuthor != 1 || stats.ResolvedByReviewer != 1 {
+		t.Errorf("resolved by author/reviewer = %d/%d", stats.ResolvedByAuthor, stats.ResolvedByReviewer)
+	}
+
+	var buf bytes.Buffer
+	if err := writeStatsCSV(&buf, stats); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "threads_per_reviewer,rev1,2\n") {
+		t.Errorf("CSV missing reviewer row:\n%s", buf.String())
+	}
+}
+
+func TestCreatedQualifier(t *testing.T) {
+	tests := []struct {
+		since, until string
+		want         string
+		wantErr      bool
+	}{
+		{"2025-01-01", "2025-01-31", "created:2025-01-01..2025-01-31", false},
+		{"2025-01-01", "", "created:>=2025-01-01", false},
+		{"", "2025-01-31", "created:<=2025-01-31", false},
+		{"yesterday", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := createdQualifier(tt.since, tt.until)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("createdQualifier(%q, %q) = %q, %v", tt.since, tt.until, got, err)
+		}
+	}
+}
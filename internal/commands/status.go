@@ -15,6 +15,7 @@ var statusCmd = &cobra.Command{
 
 Displays:
   - Total threads and resolution status
+  - Whose turn it is (author or reviewer) for unresolved threads
   - Comment counts
   - Recent activity summary
   - Overall completion status
@@ -33,103 +34,121 @@ Examples:
 
 func init() {
 	statusCmd.Flags().Bool("compact", false, "Show compact one-line summary")
+	addSnapshotFlag(statusCmd)
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	// Get repository context
-	owner, name, err := getRepository(cmd)
-	if err != nil {
-		return err
-	}
-
-	// Get PR number
-	prNum, err := getCurrentPR(cmd)
-	if err != nil {
-		return err
-	}
-
-	// Create API client
-	client, err := api.NewClient()
-	if err != nil {
-		return err
-	}
-
 	// Fetch threads
-	threads, err := client.ListThreads(ctx, owner, name, prNum)
+	pr, err := fetchPullRequest(ctx, cmd)
 	if err != nil {
 		return err
 	}
-
-	// Calculate statistics
-	totalThreads := len(threads)
-	resolvedThreads := 0
-	unresolvedThreads := 0
-	totalComments := 0
-	totalReactions := 0
-
-	for _, t := range threads {
-		totalComments += len(t.Comments)
-		if t.IsResolved {
-			resolvedThreads++
-		} else {
-			unresolvedThreads++
-		}
-
-		// Count reactions
-		for _, c := range t.Comments {
-			for _, rg := range c.ReactionGroups {
-				totalReactions += rg.Users.TotalCount
-			}
-		}
-	}
+	summary := summarizeStatus(pr)
 
 	// Display
 	compact, _ := cmd.Flags().GetBool("compact")
 
 	if compact {
 		// One-line summary
-		fmt.Printf("PR %s/%s#%d: %d threads (%d resolved, %d unresolved), %d comments, %d reactions\n",
-			owner, name, prNum, totalThreads, resolvedThreads, unresolvedThreads, totalComments, totalReactions)
+		fmt.Fprintf(factory.IOStreams.Out, "PR %s#%d: %d threads (%d resolved, %d unresolved; %d waiting on author, %d on reviewer), %d comments, %d reactions\n",
+			pr.Repository, pr.Number, summary.Threads, summary.Resolved, summary.Unresolved, summary.WaitingOnAuthor, summary.WaitingOnReviewer, summary.Comments, summary.Reactions)
 		return nil
 	}
 
 	// Detailed output
-	fmt.Printf("PR: %s/%s#%d\n\n", owner, name, prNum)
+	fmt.Fprintf(factory.IOStreams.Out, "PR: %s#%d\n\n", pr.Repository, pr.Number)
 
-	fmt.Printf("Threads:\n")
-	fmt.Printf("  Total:      %d\n", totalThreads)
-	fmt.Printf("  Resolved:   %d", resolvedThreads)
-	if resolvedThreads == totalThreads && totalThreads > 0 {
-		fmt.Printf(" ✓ All resolved!\n")
+	fmt.Fprintf(factory.IOStreams.Out, "Threads:\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  Total:      %d\n", summary.Threads)
+	fmt.Fprintf(factory.IOStreams.Out, "  Resolved:   %d", summary.Resolved)
+	if summary.Resolved == summary.Threads && summary.Threads > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, " ✓ All resolved!\n")
 	} else {
-		fmt.Printf("\n")
+		fmt.Fprintf(factory.IOStreams.Out, "\n")
 	}
-	fmt.Printf("  Unresolved: %d", unresolvedThreads)
-	if unresolvedThreads > 0 {
-		fmt.Printf(" ⚠️  Needs attention\n")
-	} else if totalThreads > 0 {
-		fmt.Printf(" ✓\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  Unresolved: %d", summary.Unresolved)
+	if summary.Unresolved > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, " ⚠️  Needs attention\n")
+	} else if summary.Threads > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, " ✓\n")
 	} else {
-		fmt.Printf("\n")
+		fmt.Fprintf(factory.IOStreams.Out, "\n")
+	}
+
+	if summary.Unresolved > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "\nWaiting On:\n")
+		fmt.Fprintf(factory.IOStreams.Out, "  Author:     %d", summary.WaitingOnAuthor)
+		if pr.Author.Login != "" {
+			fmt.Fprintf(factory.IOStreams.Out, " (@%s)", pr.Author.Login)
+		}
+		fmt.Fprintf(factory.IOStreams.Out, "\n")
+		fmt.Fprintf(factory.IOStreams.Out, "  Reviewer:   %d\n", summary.WaitingOnReviewer)
 	}
 
-	fmt.Printf("\nComments:\n")
-	fmt.Printf("  Total:      %d\n", totalComments)
+	fmt.Fprintf(factory.IOStreams.Out, "\nComments:\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  Total:      %d\n", summary.Comments)
 
-	fmt.Printf("\nReactions:\n")
-	fmt.Printf("  Total:      %d\n", totalReactions)
+	fmt.Fprintf(factory.IOStreams.Out, "\nReactions:\n")
+	fmt.Fprintf(factory.IOStreams.Out, "  Total:      %d\n", summary.Reactions)
 
 	// Overall status
-	fmt.Printf("\nOverall Status: ")
-	if unresolvedThreads == 0 && totalThreads > 0 {
-		fmt.Printf("✓ All feedback addressed\n")
-	} else if unresolvedThreads > 0 {
-		fmt.Printf("⚠️  %d thread(s) need attention\n", unresolvedThreads)
+	fmt.Fprintf(factory.IOStreams.Out, "\nOverall Status: ")
+	if summary.Unresolved == 0 && summary.Threads > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "✓ All feedback addressed\n")
+	} else if summary.Unresolved > 0 {
+		fmt.Fprintf(factory.IOStreams.Out, "⚠️  %d thread(s) need attention\n", summary.Unresolved)
 	} else {
-		fmt.Printf("No review threads found\n")
+		fmt.Fprintf(factory.IOStreams.Out, "No review threads found\n")
 	}
 
 	return nil
 }
+
+// statusSummary holds review thread counts for a pull request
+type statusSummary struct {
+	Repository        string `json:"repository"`
+	Number            int    `json:"number"`
+	Threads           int    `json:"threads"`
+	Resolved          int    `json:"resolved"`
+	Unresolved        int    `json:"unresolved"`
+	WaitingOnAuthor   int    `json:"waitingOnAuthor"`
+	WaitingOnReviewer int    `json:"waitingOnReviewer"`
+	Comments          int    `json:"comments"`
+	Reactions         int    `json:"reactions"`
+}
+
+// summarizeStatus counts threads, comments and reactions on a pull request
+func summarizeStatus(pr *api.PullRequest) statusSummary {
+	summary := statusSummary{
+		Repository: pr.Repository,
+		Number:     pr.Number,
+		Threads:    len(pr.ReviewThreads),
+	}
+
+	for _, t := range pr.ReviewThreads {
+		summary.Comments += len(t.Comments)
+		if t.IsResolved {
+			summary.Resolved++
+		} else {
+			summary.Unresolved++
+		}
+
+		switch t.WaitingOn {
+		case api.WaitingOnAuthor:
+			summary.WaitingOnAuthor++
+		case api.WaitingOnReviewer:
+			summary.WaitingOnReviewer++
+		}
+
+		// Count reactions
+		for _, c := range t.Comments {
+			for _, rg := range c.ReactionGroups {
+				summary.Reactions += rg.Users.TotalCount
+			}
+		}
+	}
+
+	return summary
+}
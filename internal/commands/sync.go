@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+)
+
+var syncCommitsCmd = &cobra.Command{
+	Use:   "sync-commits",
+	Short: "Reply to threads referenced in commit messages",
+	Long: `Scan local commits for review thread references and reply to each
+referenced thread with a link to the commit that addressed it.
+
+Commit messages may reference threads by:
+  - Full thread ID        addresses PRRT_kwDOQN97u85gQeTN
+  - Discussion URL        fixes https://github.com/o/r/pull/1#discussion_r456
+  - Short handle          fixes review: r456
+
+A preview is always printed first. Threads that already have a reply
+mentioning the commit are skipped, so running the command again never
+posts duplicate replies.
+
+Examples:
+  # Preview replies for commits not yet on origin's default branch
+  gh talk sync-commits --dry-run
+
+  # Reply and resolve, scanning commits since origin/main
+  gh talk sync-commits --since origin/main --resolve
+
+  # Skip confirmation
+  gh talk sync-commits --yes`,
+	Args: cobra.NoArgs,
+	RunE: runSyncCommits,
+}
+
+func init() {
+	syncCommitsCmd.Flags().String("since", "origin/HEAD", "Scan commits after this revision")
+	syncCommitsCmd.Flags().Bool("resolve", false, "Resolve threads after replying")
+	syncCommitsCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+}
+
+// commitRefPattern matches thread references inside commit messages
+var commitRefPattern = regexp.MustCompile(`PRRT_[A-Za-z0-9_-]+|https?://\S+#discussion_r\d+|\br\d{4,}\b`)
+
+// syncAction is a reply planned for one commit and thread
+type syncAction struct {
+	Commit  gitCommit
+	Thread  api.Thread
+	Replied bool
+}
+
+func runSyncCommits(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	owner, name, err := getRepository(cmd)
+	if err != nil {
+		return err
+	}
+
+	prNum, err := getCurrentPR(cmd)
+	if err != nil {
+		return err
+	}
+
+	since, _ := cmd.Flags().GetString("since")
+	revRange := "HEAD"
+	if since != "" {
+		revRange = since + "..HEAD"
+	}
+
+	commits, err := gitLog(revRange)
+	if err != nil {
+		return fmt.Errorf("failed to read commits: %w", err)
+	}
+
+	client, err := newMutationClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	pr, err := client.GetPullRequest(ctx, owner, name, prNum)
+	if err != nil {
+		return err
+	}
+	threads := pr.ReviewThreads
+	repoURL := repositoryURL(pr, commandHost(cmd), owner, name)
+
+	actions, warnings := planSyncActions(commits, threads)
+	for _, w := range warnings {
+		fmt.Fprintf(factory.IOStreams.ErrOut, "! %s\n", w)
+	}
+
+	fmt.Fprintf(factory.IOStreams.Out, "Scanned %d commits (%s)\n\n", len(commits), revRange)
+	if len(actions) == 0 {
+		fmt.Fprintln(factory.IOStreams.Out, "No thread references found")
+		return nil
+	}
+
+	shouldResolve, _ := cmd.Flags().GetBool("resolve")
+
+	pending := 0
+	for _, a := range actions {
+		if a.Replied {
+			fmt.Fprintf(factory.IOStreams.Out, "  - %s %s: already replied, skipping\n", shortSHA(a.Commit.SHA), a.Thread.ID)
+			continue
+		}
+		pending++
+		fmt.Fprintf(factory.IOStreams.Out, "  → %s %s (%s:%d): %s\n", shortSHA(a.Commit.SHA), a.Thread.ID, a.Thread.Path, a.Thread.Line, syncReplyBody(repoURL, a.Commit))
+		if shouldResolve && !a.Thread.IsResolved {
+			fmt.Fprintf(factory.IOStreams.Out, "    and resolve\n")
+		}
+	}
+	fmt.Fprintln(factory.IOStreams.Out)
+
+	if pending == 0 {
+		fmt.Fprintln(factory.IOStreams.Out, "✓ All referenced threads are up to date")
+		return nil
+	}
+
+	if dryRunFormat(cmd) != "" {
+		return runDryRun(ctx, cmd, client, ops.NewIndex(pr), syncOperations(repoURL, actions, shouldResolve))
+	}
+
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	if !skipConfirm {
+		p := factory.Prompter()
+		confirmed, err := p.Confirm(fmt.Sprintf("Post %d replies?", pending), false)
+		if err != nil || !confirmed {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	resolved := make(map[string]bool)
+	for _, a := range actions {
+		if a.Replied {
+			continue
+		}
+
+		if _, err := client.ReplyToThread(ctx, a.Thread.ID, syncReplyBody(repoURL, a.Commit)); err != nil {
+			return fmt.Errorf("failed to reply to %s: %w", a.Thread.ID, err)
+		}
+		fmt.Fprintf(factory.IOStreams.Out, "✓ Replied to %s (%s)\n", a.Thread.ID, shortSHA(a.Commit.SHA))
+
+		if shouldResolve && !a.Thread.IsResolved && !resolved[a.Thread.ID] {
+			if _, err := client.ResolveThread(ctx, a.Thread.ID); err != nil {
+				return fmt.Errorf("replied successfully but failed to resolve %s: %w", a.Thread.ID, err)
+			}
+			resolved[a.Thread.ID] = true
+			fmt.Fprintf(factory.IOStreams.Out, "✓ Resolved %s\n", a.Thread.ID)
+		}
+	}
+
+	return nil
+}
+
+// syncOperations returns the replies and resolutions for pending actions
+func syncOperations(repoURL string, actions []syncAction, resolve bool) []ops.Operation {
+	var operations []ops.Operation
+	resolved := make(map[string]bool)
+	for _, a := range actions {
+		if a.Replied {
+			continue
+		}
+		operations = append(operations, ops.Operation{Op: ops.Reply, Thread: a.Thread.ID, Body: syncReplyBody(repoURL, a.Commit)})
+		if resolve && !a.Thread.IsResolved && !resolved[a.Thread.ID] {
+			resolved[a.Thread.ID] = true
+			operations = append(operations, ops.Operation{Op: ops.Resolve, Thread: a.Thread.ID})
+		}
+	}
+	return operations
+}
+
+// findThreadRefs returns the distinct thread references in a commit message
+func findThreadRefs(message string) []string {
+	seen := make(map[string]bool)
+	var refs []string
+	for _, ref := range commitRefPattern.FindAllString(message, -1) {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// planSyncActions matches commit references against threads.
+//
+// A thread that already has a comment containing the commit SHA is marked
+// as replied so it is never answered twice for the same commit.
+func planSyncActions(commits []gitCommit, threads []api.Thread) ([]syncAction, []string) {
+	var actions []syncAction
+	var warnings []string
+
+	for _, commit := range commits {
+		planned := make(map[string]bool)
+		for _, ref := range findThreadRefs(commit.Message) {
+			thread, err := resolveThreadRef(ref, threads)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s not found in this PR", shortSHA(commit.SHA), ref))
+				continue
+			}
+			if planned[thread.ID] {
+				continue
+			}
+			planned[thread.ID] = true
+
+			actions = append(actions, syncAction{
+				Commit:  commit,
+				Thread:  *thread,
+				Replied: threadMentions(*thread, commit.SHA),
+			})
+		}
+	}
+
+	return actions, warnings
+}
+
+// threadMentions reports whether any comment in the thread contains text
+func threadMentions(t api.Thread, text string) bool {
+	for _, c := range t.Comments {
+		if strings.Contains(c.Body, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncReplyBody formats the reply posted for a commit, linking to it in
+// the repository at repoURL
+func syncReplyBody(repoURL string, commit gitCommit) string {
+	return fmt.Sprintf("Addressed in [%s](%s/commit/%s)", shortSHA(commit.SHA), repoURL, commit.SHA)
+}
+
+// repositoryURL returns the web URL of a pull request's repository. It
+// comes from the PR's URL, so links work on any host, or else is built
+// from host ("" for github.com).
+func repositoryURL(pr *api.PullRequest, host, owner, name string) string {
+	if base, _, ok := strings.Cut(pr.URL, "/pull/"); ok {
+		return base
+	}
+	if host == "" {
+		host = "github.com"
+	}
+	return fmt.Sprintf("https://%s/%s/%s", host, owner, name)
+}
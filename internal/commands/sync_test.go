@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestFindThreadRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{
+			name:    "full thread ID",
+			message: "Use constant for TODO\n\naddresses PRRT_kwDOQN97u85gQeTN",
+			want:    []string{"PRRT_kwDOQN97u85gQeTN"},
+		},
+		{
+			name:    "discussion URL",
+			message: "fixes https://github.com/owner/repo/pull/1#discussion_r2485035707",
+			want:    []string{"https://github.com/owner/repo/pull/1#discussion_r2485035707"},
+		},
+		{
+			name:    "short handle and duplicate",
+			message: "fixes review: r2485035707, r2485035707 and PRRT_abc",
+			want:    []string{"r2485035707", "PRRT_abc"},
+		},
+		{
+			name:    "no references",
+			message: "Bump version to r2",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findThreadRefs(tt.message)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findThreadRefs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGitLog(t *testing.T) {
+	out := "aaa\x1fFirst\x1fFirst\n\naddresses PRRT_a\x1e\nbbb\x1fSecond\x1fSecond\x1e\n"
+
+	got := parseGitLog(out)
+	want := []gitCommit{
+		{SHA: "aaa", Subject: "First", Message: "First\n\naddresses PRRT_a"},
+		{SHA: "bbb", Subject: "Second", Message: "Second"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGitLog() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanSyncActions(t *testing.T) {
+	threads := []api.Thread{
+		{
+			ID:       "PRRT_a",
+			Comments: []api.Comment{{DatabaseID: 111111, Body: "Please fix"}},
+		},
+		{
+			ID: "PRRT_b",
+			Comments: []api.Comment{
+				{DatabaseID: 222222, Body: "Rename this"},
+				{DatabaseID: 222223, Body: "Addressed in [bbbbbbb](https://github.com/o/r/commit/bbbbbbbbbb)"},
+			},
+		},
+	}
+
+	commits := []gitCommit{
+		{SHA: "aaaaaaaaaa", Message: "Fix it\n\naddresses PRRT_a and r111111"},
+		{SHA: "bbbbbbbbbb", Message: "Rename\n\nfixes review: r222222"},
+		{SHA: "cccccccccc", Message: "Other\n\naddresses PRRT_missing"},
+	}
+
+	actions, warnings := planSyncActions(commits, threads)
+
+	if len(actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(actions))
+	}
+	if actions[0].Thread.ID != "PRRT_a" || actions[0].Replied {
+		t.Errorf("unexpected first action: %+v", actions[0])
+	}
+	if actions[1].Thread.ID != "PRRT_b" || !actions[1].Replied {
+		t.Errorf("expected second action to be marked replied: %+v", actions[1])
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected 1 warning, got %v", warnings)
+	}
+}
+
+func TestSyncReplyBody(t *testing.T) {
+	commit := gitCommit{SHA: "abcdef1234567890"}
+
+	tests := []struct {
+		name string
+		pr   *api.PullRequest
+		host string
+		want string
+	}{
+		{
+			name: "from PR URL",
+			pr:   &api.PullRequest{URL: "https://github.example.com/o/r/pull/7"},
+			want: "Addressed in [abcdef1](https://github.example.com/o/r/commit/abcdef1234567890)",
+		},
+		{
+			name: "from host",
+			pr:   &api.PullRequest{},
+			host: "github.example.com",
+			want: "Addressed in [abcdef1](https://github.example.com/o/r/commit/abcdef1234567890)",
+		},
+		{
+			name: "default host",
+			pr:   &api.PullRequest{},
+			want: "Addressed in [abcdef1](https://github.com/o/r/commit/abcdef1234567890)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := syncReplyBody(repositoryURL(tt.pr, tt.host, "o", "r"), commit); got != tt.want {
+				t.Errorf("syncReplyBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
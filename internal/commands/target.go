@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// Values for --target, choosing which comments of a thread a command acts on
+const (
+	targetFirst  = "first"
+	targetLast   = "last"
+	targetAll    = "all"
+	targetMine   = "mine"
+	targetOthers = "others"
+)
+
+var targetValues = []string{targetFirst, targetLast, targetAll, targetMine, targetOthers}
+
+// addTargetFlag adds --target to a command that acts on comments and also
+// accepts threads
+func addTargetFlag(cmd *cobra.Command) {
+	cmd.Flags().String("target", targetFirst, "Comments to act on in threads: "+strings.Join(targetValues, ", "))
+}
+
+// targetComment is a comment a command acts on. Comment is set when it was
+// fetched as part of a thread, and nil when its ID was given directly.
+type targetComment struct {
+	ID      string
+	Comment *api.Comment
+}
+
+// isCommentID reports whether ref is a review or issue comment ID
+func isCommentID(ref string) bool {
+	return strings.HasPrefix(ref, "PRRC_") || strings.HasPrefix(ref, "IC_")
+}
+
+// isThreadRef reports whether ref refers to a review thread: a thread ID,
+// discussion URL or short handle
+func isThreadRef(ref string) bool {
+	return strings.HasPrefix(ref, "PRRT_") || discussionURLPattern.MatchString(ref) || handlePattern.MatchString(ref)
+}
+
+// checkTargetRefs returns an error for the first reference that is neither
+// a comment nor a thread
+func checkTargetRefs(refs []string) error {
+	for _, ref := range refs {
+		if !isCommentID(ref) && !isThreadRef(ref) {
+			return fmt.Errorf("invalid comment or thread %s - expected a comment ID (PRRC_ or IC_), thread ID (PRRT_), discussion URL or handle (r123)", ref)
+		}
+	}
+	return nil
+}
+
+// resolveTargetComments turns comment IDs, thread IDs and thread handles
+// into the comments to act on, choosing comments of threads with --target.
+// Comment IDs are passed through without a lookup.
+func resolveTargetComments(ctx context.Context, cmd *cobra.Command, client *api.Client, refs []string) ([]targetComment, error) {
+	target, _ := cmd.Flags().GetString("target")
+	if !validTarget(target) {
+		return nil, fmt.Errorf("invalid --target: %s\n\nValid targets: %s", target, strings.Join(targetValues, ", "))
+	}
+
+	if err := checkTargetRefs(refs); err != nil {
+		return nil, err
+	}
+
+	var prThreads []api.Thread
+	fetchedThreads := false
+	var viewer string
+
+	var targets []targetComment
+	seen := make(map[string]bool)
+	add := func(t targetComment) {
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			targets = append(targets, t)
+		}
+	}
+
+	for _, ref := range refs {
+		if isCommentID(ref) {
+			add(targetComment{ID: ref})
+			continue
+		}
+
+		var thread *api.Thread
+		if strings.HasPrefix(ref, "PRRT_") {
+			t, err := client.GetThread(ctx, ref)
+			if err != nil {
+				return nil, err
+			}
+			thread = t
+		} else {
+			// Handles and URLs are looked up among the PR's threads
+			if !fetchedThreads {
+				owner, name, err := getRepository(cmd)
+				if err != nil {
+					return nil, err
+				}
+				prNum, err := getCurrentPR(cmd)
+				if err != nil {
+					return nil, err
+				}
+				prThreads, err = client.ListThreads(ctx, owner, name, prNum)
+				if err != nil {
+					return nil, err
+				}
+				fetchedThreads = true
+			}
+			t, err := resolveThreadRef(ref, prThreads)
+			if err != nil {
+				return nil, err
+			}
+			thread = t
+		}
+
+		if (target == targetMine || target == targetOthers) && viewer == "" {
+			login, err := client.CurrentUser(ctx)
+			if err != nil {
+				return nil, err
+			}
+			viewer = login
+		}
+
+		comments := selectThreadComments(thread, target, viewer)
+		if len(comments) == 0 {
+			return nil, fmt.Errorf("no comments in thread %s match --target %s", thread.ID, target)
+		}
+		for i := range comments {
+			add(targetComment{ID: comments[i].ID, Comment: &comments[i]})
+		}
+	}
+
+	return targets, nil
+}
+
+func validTarget(target string) bool {
+	for _, v := range targetValues {
+		if target == v {
+			return true
+		}
+	}
+	return false
+}
+
+// selectThreadComments returns the comments of a thread a target chooses.
+// viewer is the current user's login, used by mine and others.
+func selectThreadComments(t *api.Thread, target, viewer string) []api.Comment {
+	if len(t.Comments) == 0 {
+		return nil
+	}
+
+	switch target {
+	case targetFirst:
+		return t.Comments[:1]
+	case targetLast:
+		return t.Comments[len(t.Comments)-1:]
+	case targetAll:
+		return t.Comments
+	}
+
+	var selected []api.Comment
+	for _, c := range t.Comments {
+		mine := strings.EqualFold(c.Author.Login, viewer)
+		if mine == (target == targetMine) {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestSelectThreadComments(t *testing.T) {
+	thread := &api.Thread{Comments: []api.Comment{
+		{ID: "PRRC_1", Author: api.User{Login: "reviewer"}},
+		{ID: "PRRC_2", Author: api.User{Login: "me"}},
+		{ID: "PRRC_3", Author: api.User{Login: "reviewer"}},
+		{ID: "PRRC_4", Author: api.User{Login: "Me"}},
+	}}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{targetFirst, "PRRC_1"},
+		{targetLast, "PRRC_4"},
+		{targetAll, "PRRC_1,PRRC_2,PRRC_3,PRRC_4"},
+		{targetMine, "PRRC_2,PRRC_4"},
+		{targetOthers, "PRRC_1,PRRC_3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var ids []string
+			for _, c := range selectThreadComments(thread, tt.target, "me") {
+				ids = append(ids, c.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("selectThreadComments(%s) = %s, want %s", tt.target, got, tt.want)
+			}
+		})
+	}
+
+	if got := selectThreadComments(&api.Thread{}, targetFirst, "me"); len(got) != 0 {
+		t.Errorf("selectThreadComments(empty thread) = %v", got)
+	}
+}
+
+const targetThread = `{"data":{"node":{"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"viewerCanResolve":true,"viewerCanReply":true,"comments":{"nodes":[
+  {"id":"PRRC_1","databaseId":101,"body":"Use a constant","author":{"login":"reviewer"},"viewerCanReact":true,"viewerCanMinimize":true},
+  {"id":"PRRC_2","databaseId":102,"body":"Done","author":{"login":"author"},"viewerCanReact":true,"viewerCanMinimize":true},
+  {"id":"PRRC_5","databaseId":105,"body":"Thanks","author":{"login":"reviewer"},"viewerCanReact":true,"viewerCanMinimize":true}
+]}}}}`
+
+func TestCommentTargets(t *testing.T) {
+	responses := map[string]string{
+		"GetThread":         targetThread,
+		"ListThreads":       goldenThreads,
+		"GetComment":        goldenComment,
+		"CurrentUser":       `{"data":{"viewer":{"login":"author"}}}`,
+		"AddReaction":       `{"data":{"addReaction":{"reaction":{"id":"REA_1","content":"EYES"},"subject":{"id":"PRRC_1"}}}}`,
+		"MinimizeComment":   `{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true,"minimizedReason":"OUTDATED"}}}}`,
+		"UnminimizeComment": `{"data":{"unminimizeComment":{"unminimizedComment":{"isMinimized":false}}}}`,
+	}
+
+	subjects := func(gh *fakeGitHub, operation string) string {
+		var ids []string
+		for _, input := range mutationInputs(gh, operation) {
+			if id, ok := input["subjectId"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return strings.Join(ids, ",")
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		operations string
+		mutation   string
+		subjects   string
+	}{
+		{"react thread", []string{"react", "PRRT_a", "👀"}, "GetThread,AddReaction", "AddReaction", "PRRC_1"},
+		{"react last", []string{"react", "PRRT_a", "👀", "--target", "last"}, "GetThread,AddReaction", "AddReaction", "PRRC_5"},
+		{"react others", []string{"react", "PRRT_a", "👀", "--target", "others"}, "GetThread,CurrentUser,AddReaction,AddReaction", "AddReaction", "PRRC_1,PRRC_5"},
+		{"react handle", []string{"react", "r102", "👀", "--target", "all"}, "ListThreads,AddReaction,AddReaction", "AddReaction", "PRRC_1,PRRC_2"},
+		{"react comment and thread", []string{"react", "PRRC_1", "PRRT_a", "👀"}, "GetThread,GetComment,AddReaction", "AddReaction", "PRRC_1"},
+		{"hide mine", []string{"hide", "PRRT_a", "--target", "mine", "--reason", "outdated"}, "GetThread,CurrentUser,MinimizeComment", "MinimizeComment", "PRRC_2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, responses)
+			f, _, _ := newTestFactory(t, gh)
+
+			if err := runCommand(t, f, tt.args...); err != nil {
+				t.Fatalf("gh talk %s: %v", strings.Join(tt.args, " "), err)
+			}
+			if got := strings.Join(gh.operations(), ","); got != tt.operations {
+				t.Errorf("operations = %s, want %s", got, tt.operations)
+			}
+			if got := subjects(gh, tt.mutation); got != tt.subjects {
+				t.Errorf("%s subjects = %s, want %s", tt.mutation, got, tt.subjects)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTestFactory(t, gh)
+
+		for _, args := range [][]string{
+			{"react", "PRRT_a", "👀", "--target", "middle"},
+			{"react", "123", "👀"},
+			{"hide", "PRRT_a", "--target", "nobody"},
+		} {
+			if err := runCommand(t, f, args...); err == nil {
+				t.Errorf("gh talk %s: error = nil", strings.Join(args, " "))
+			}
+		}
+		if len(mutationInputs(gh, "AddReaction"))+len(mutationInputs(gh, "MinimizeComment")) != 0 {
+			t.Errorf("operations = %v, want no mutations", gh.operations())
+		}
+	})
+}
@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage saved reply templates",
+	Long: `Manage saved replies used with 'gh talk reply --template'.
+
+Templates are Go templates stored in the config file
+(~/.config/gh-talk/config.yml, or GH_TALK_CONFIG). They can use:
+
+  {{path}}      File the thread is on
+  {{line}}      Line the thread is on
+  {{reviewer}}  Login of the thread's first commenter
+  {{thread}}    Thread ID
+  {{sha}}       Short SHA of HEAD, or of the revision in --var sha=REV
+
+and any variable passed with --var NAME=VALUE, as {{NAME}}.`,
+}
+
+var templateAddCmd = &cobra.Command{
+	Use:   "add <name> [body]",
+	Short: "Save a reply template",
+	Long: `Save a reply template, prompting for the body if it is not given.
+
+Examples:
+  gh talk template add fixed 'Fixed in {{sha}}'
+  gh talk template add thanks 'Good catch @{{reviewer}}, done'
+  gh talk template add followup 'Will address in follow-up #{{issue}}'`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTemplateAdd,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List reply templates",
+	Args:  cobra.NoArgs,
+	RunE:  runTemplateList,
+}
+
+var templateRmCmd = &cobra.Command{
+	Use:   "rm <name...>",
+	Short: "Remove reply templates",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runTemplateRm,
+}
+
+func init() {
+	templateCmd.AddCommand(templateAddCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateRmCmd)
+
+	templateAddCmd.Flags().BoolP("force", "f", false, "Replace an existing template")
+}
+
+func runTemplateAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	var body string
+	if len(args) == 2 {
+		body = args[1]
+	} else {
+		input, err := factory.Prompter().Input("Template body:", "")
+		if err != nil {
+			return fmt.Errorf("failed to get template body: %w", err)
+		}
+		body = input
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("template body cannot be empty")
+	}
+
+	if _, err := parseReplyTemplate(name, body, nil); err != nil {
+		if _, missing := missingTemplateVar(err); !missing {
+			return err
+		}
+	}
+
+	cfg, err := factory.Config()
+	if err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if _, exists := cfg.Templates[name]; exists && !force {
+		return fmt.Errorf("template %s already exists\n\nUse --force to replace it", name)
+	}
+
+	if cfg.Templates == nil {
+		cfg.Templates = make(map[string]string)
+	}
+	cfg.Templates[name] = body
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(factory.IOStreams.Out, "✓ Saved template %s\n", name)
+	return nil
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	cfg, err := factory.Config()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Templates) == 0 {
+		fmt.Fprintln(factory.IOStreams.ErrOut, "No templates saved\n\nAdd one with 'gh talk template add NAME BODY'")
+		return nil
+	}
+
+	ios := factory.IOStreams
+	t := tableprinter.New(ios.Out, ios.IsTerminal, ios.Width)
+	for _, name := range templateNames(cfg.Templates) {
+		t.AddField(name)
+		t.AddField(cfg.Templates[name])
+		t.EndRow()
+	}
+	return t.Render()
+}
+
+func runTemplateRm(cmd *cobra.Command, args []string) error {
+	cfg, err := factory.Config()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		if _, exists := cfg.Templates[name]; !exists {
+			return fmt.Errorf("template not found: %s", name)
+		}
+	}
+
+	for _, name := range args {
+		delete(cfg.Templates, name)
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		fmt.Fprintf(factory.IOStreams.Out, "✓ Removed template %s\n", name)
+	}
+	return nil
+}
+
+// templateNames returns template names in alphabetical order
+func templateNames(templates map[string]string) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// replyTemplateContext is what a reply template can refer to
+type replyTemplateContext struct {
+	Thread *api.Thread
+	// Vars are --var NAME=VALUE pairs; they take precedence over the
+	// thread context
+	Vars map[string]string
+	// ShortSHA returns the short SHA of a git revision
+	ShortSHA func(rev string) (string, error)
+}
+
+// funcs returns the template functions for a context. A nil context
+// gives placeholder functions, for checking a template's syntax.
+func (c *replyTemplateContext) funcs() template.FuncMap {
+	if c == nil {
+		c = &replyTemplateContext{Thread: &api.Thread{}, ShortSHA: func(string) (string, error) { return "", nil }}
+	}
+
+	funcs := template.FuncMap{
+		"thread": func() string { return c.Thread.ID },
+		"path":   func() string { return c.Thread.Path },
+		"line":   func() int { return c.Thread.Line },
+		"reviewer": func() string {
+			if len(c.Thread.Comments) == 0 {
+				return ""
+			}
+			return c.Thread.Comments[0].Author.Login
+		},
+		"sha": func() (string, error) {
+			rev := c.Vars["sha"]
+			if rev == "" {
+				rev = "HEAD"
+			}
+			return c.ShortSHA(rev)
+		},
+	}
+
+	for name, value := range c.Vars {
+		if name == "sha" {
+			continue
+		}
+		value := value
+		funcs[name] = func() string { return value }
+	}
+	return funcs
+}
+
+// parseReplyTemplate parses a reply template for a context
+func parseReplyTemplate(name, body string, ctx *replyTemplateContext) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(ctx.funcs()).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// undefinedFuncPattern matches text/template's error for an unknown
+// function, which is how a missing --var shows up
+var undefinedFuncPattern = regexp.MustCompile(`function "(\w+)" not defined`)
+
+// missingTemplateVar returns the variable a template needs but was not
+// given, if that is why parsing failed
+func missingTemplateVar(err error) (string, bool) {
+	m := undefinedFuncPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// renderReplyTemplate fills in a reply template
+func renderReplyTemplate(name, body string, ctx *replyTemplateContext) (string, error) {
+	tmpl, err := parseReplyTemplate(name, body, ctx)
+	if err != nil {
+		if v, missing := missingTemplateVar(err); missing {
+			return "", fmt.Errorf("template %s needs a value for %s\n\nUse --var %s=VALUE", name, v, v)
+		}
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, nil); err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// templateVarPattern matches names usable as template functions
+var templateVarPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseTemplateVars parses --var NAME=VALUE flags
+func parseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || !templateVarPattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid --var %q\n\nExpected NAME=VALUE, with a name of letters, digits and underscores", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// gitShortSHA returns the short SHA of a git revision
+func gitShortSHA(rev string) (string, error) {
+	return runGit("rev-parse", "--short", rev)
+}
+
+// threadTemplateContext returns the context for filling in a template
+// replying to a thread
+func threadTemplateContext(ctx context.Context, client *api.Client, threadID string, vars map[string]string) (*replyTemplateContext, error) {
+	thread, err := client.GetThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	return &replyTemplateContext{Thread: thread, Vars: vars, ShortSHA: gitShortSHA}, nil
+}
+
+// templateReply renders the --template reply to a thread
+func templateReply(ctx context.Context, cmd *cobra.Command, client *api.Client, threadID, name string) (string, error) {
+	cfg, err := factory.Config()
+	if err != nil {
+		return "", err
+	}
+	body, ok := cfg.Templates[name]
+	if !ok {
+		return "", fmt.Errorf("template not found: %s\n\nRun 'gh talk template list' to see saved templates", name)
+	}
+
+	pairs, _ := cmd.Flags().GetStringArray("var")
+	vars, err := parseTemplateVars(pairs)
+	if err != nil {
+		return "", err
+	}
+
+	tctx, err := threadTemplateContext(ctx, client, threadID, vars)
+	if err != nil {
+		return "", err
+	}
+	return renderReplyTemplate(name, body, tctx)
+}
+
+// promptReply asks for a reply message, offering saved templates. A
+// chosen template's missing variables are prompted for, and the result
+// can be edited before sending.
+func promptReply(ctx context.Context, client *api.Client, threadID string) (string, error) {
+	p := factory.Prompter()
+
+	cfg, err := factory.Config()
+	if err != nil {
+		return "", err
+	}
+	if len(cfg.Templates) == 0 {
+		return p.Input("Reply message:", "")
+	}
+
+	names := templateNames(cfg.Templates)
+	options := []string{"Write a new reply"}
+	for _, name := range names {
+		options = append(options, fmt.Sprintf("%s: %s", name, truncate(cfg.Templates[name], 50)))
+	}
+	idx, err := p.Select("Reply with:", options[0], options)
+	if err != nil {
+		return "", err
+	}
+	if idx == 0 {
+		return p.Input("Reply message:", "")
+	}
+
+	name := names[idx-1]
+	body := cfg.Templates[name]
+	tctx, err := threadTemplateContext(ctx, client, threadID, map[string]string{})
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		_, err := parseReplyTemplate(name, body, tctx)
+		if err == nil {
+			break
+		}
+		v, missing := missingTemplateVar(err)
+		if !missing {
+			return "", err
+		}
+		value, err := p.Input(v+":", "")
+		if err != nil {
+			return "", err
+		}
+		tctx.Vars[v] = value
+	}
+
+	message, err := renderReplyTemplate(name, body, tctx)
+	if err != nil {
+		return "", err
+	}
+	return p.Input("Reply message:", message)
+}
@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/config"
+)
+
+func TestRenderReplyTemplate(t *testing.T) {
+	thread := &api.Thread{
+		ID:   "PRRT_a",
+		Path: "main.go",
+		Line: 7,
+		Comments: []api.Comment{
+			{Author: api.User{Login: "reviewer"}},
+			{Author: api.User{Login: "author"}},
+		},
+	}
+	shortSHA := func(rev string) (string, error) {
+		switch rev {
+		case "HEAD":
+			return "abc1234", nil
+		case "HEAD~1":
+			return "def5678", nil
+		}
+		return "", errors.New("unknown revision " + rev)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		vars    map[string]string
+		want    string
+		wantErr string
+	}{
+		{"thread context", "Good catch @{{reviewer}}, fixed {{path}}:{{line}} ({{thread}})", nil, "Good catch @reviewer, fixed main.go:7 (PRRT_a)", ""},
+		{"HEAD SHA", "Fixed in {{sha}}", nil, "Fixed in abc1234", ""},
+		{"SHA of a revision", "Fixed in {{sha}}", map[string]string{"sha": "HEAD~1"}, "Fixed in def5678", ""},
+		{"variable", "Will address in follow-up #{{issue}}", map[string]string{"issue": "42"}, "Will address in follow-up #42", ""},
+		{"variable overrides context", "Thanks {{reviewer}}", map[string]string{"reviewer": "team"}, "Thanks team", ""},
+		{"missing variable", "Will address in follow-up #{{issue}}", nil, "", "needs a value for issue"},
+		{"bad revision", "Fixed in {{sha}}", map[string]string{"sha": "nope"}, "", "unknown revision nope"},
+		{"syntax error", "Fixed in {{sha", nil, "", "template fixed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &replyTemplateContext{Thread: thread, Vars: tt.vars, ShortSHA: shortSHA}
+			got, err := renderReplyTemplate("fixed", tt.body, ctx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("renderReplyTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderReplyTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderReplyTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTemplateVars(t *testing.T) {
+	vars, err := parseTemplateVars([]string{"issue=42", "note=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("parseTemplateVars() error = %v", err)
+	}
+	if vars["issue"] != "42" || vars["note"] != "a=b" || vars["empty"] != "" {
+		t.Errorf("parseTemplateVars() = %v", vars)
+	}
+
+	for _, bad := range []string{"issue", "=42", "my-var=1"} {
+		if _, err := parseTemplateVars([]string{bad}); err == nil {
+			t.Errorf("parseTemplateVars(%q) error = nil", bad)
+		}
+	}
+}
+
+// newTemplateFactory returns a test factory whose config is a file in a
+// temporary directory
+func newTemplateFactory(t *testing.T, gh *fakeGitHub) (*Factory, *bytes.Buffer, *fakePrompter) {
+	t.Helper()
+
+	f, out, _ := newTestFactory(t, gh)
+	path := filepath.Join(t.TempDir(), "config.yml")
+	f.Config = func() (*config.Config, error) {
+		return config.LoadFile(path)
+	}
+	f.Git = func(args ...string) (string, error) {
+		if strings.Join(args, " ") == "rev-parse --short HEAD" {
+			return "abc1234", nil
+		}
+		return "", errors.New("unexpected git " + strings.Join(args, " "))
+	}
+	prompter := &fakePrompter{confirm: true}
+	f.Prompter = func() Prompter { return prompter }
+	return f, out, prompter
+}
+
+func TestTemplateCommands(t *testing.T) {
+	f, out, _ := newTemplateFactory(t, newFakeGitHub(t, nil))
+
+	for _, args := range [][]string{
+		{"template", "add", "fixed", "Fixed in {{sha}}"},
+		{"template", "add", "followup", "Will address in follow-up #{{issue}}"},
+	} {
+		if err := runCommand(t, f, args...); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+
+	if err := runCommand(t, f, "template", "add", "fixed", "Done"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("adding an existing template: error = %v", err)
+	}
+	if err := runCommand(t, f, "template", "add", "broken", "{{sha"); err == nil {
+		t.Error("adding a template with a syntax error: error = nil")
+	}
+
+	out.Reset()
+	if err := runCommand(t, f, "template", "list"); err != nil {
+		t.Fatal(err)
+	}
+	want := "fixed\tFixed in {{sha}}\nfollowup\tWill address in follow-up #{{issue}}\n"
+	if got := out.String(); got != want {
+		t.Errorf("template list = %q, want %q", got, want)
+	}
+
+	if err := runCommand(t, f, "template", "rm", "followup"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ := f.Config()
+	if len(cfg.Templates) != 1 || cfg.Templates["fixed"] == "" {
+		t.Errorf("templates after rm = %v", cfg.Templates)
+	}
+	if err := runCommand(t, f, "template", "rm", "missing"); err == nil {
+		t.Error("removing a missing template: error = nil")
+	}
+}
+
+func TestReplyTemplate(t *testing.T) {
+	responses := map[string]string{
+		"GetThread": goldenThread,
+		"AddReply":  `{"data":{"addPullRequestReviewThreadReply":{"comment":{"id":"PRRC_9"}}}}`,
+	}
+
+	replyBody := func(gh *fakeGitHub) string {
+		for _, r := range gh.requests {
+			if r.Operation == "AddReply" {
+				input, _ := r.Variables["input"].(map[string]interface{})
+				body, _ := input["body"].(string)
+				return body
+			}
+		}
+		return ""
+	}
+
+	t.Run("flag", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTemplateFactory(t, gh)
+		if err := runCommand(t, f, "template", "add", "fixed", "Fixed in {{sha}}, thanks @{{reviewer}} ({{path}}:{{line}})"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := runCommand(t, f, "reply", "PRRT_a", "--template", "fixed"); err != nil {
+			t.Fatalf("reply --template: %v", err)
+		}
+		if got, want := replyBody(gh), "Fixed in abc1234, thanks @reviewer (main.go:7)"; got != want {
+			t.Errorf("reply body = %q, want %q", got, want)
+		}
+
+		if err := runCommand(t, f, "reply", "PRRT_a", "--template", "missing"); err == nil || !strings.Contains(err.Error(), "template not found") {
+			t.Errorf("reply --template missing: error = %v", err)
+		}
+		if err := runCommand(t, f, "reply", "PRRT_a", "Done", "--template", "fixed"); err == nil {
+			t.Error("reply with a message and --template: error = nil")
+		}
+	})
+
+	t.Run("variables", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, _ := newTemplateFactory(t, gh)
+		if err := runCommand(t, f, "template", "add", "followup", "Will address in follow-up #{{issue}}"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := runCommand(t, f, "reply", "PRRT_a", "--template", "followup"); err == nil || !strings.Contains(err.Error(), "--var issue=VALUE") {
+			t.Errorf("reply without --var: error = %v", err)
+		}
+		if err := runCommand(t, f, "reply", "PRRT_a", "--template", "followup", "--var", "issue=42"); err != nil {
+			t.Fatalf("reply --var: %v", err)
+		}
+		if got, want := replyBody(gh), "Will address in follow-up #42"; got != want {
+			t.Errorf("reply body = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		gh := newFakeGitHub(t, responses)
+		f, _, prompter := newTemplateFactory(t, gh)
+		for _, args := range [][]string{
+			{"template", "add", "fixed", "Fixed in {{sha}}"},
+			{"template", "add", "followup", "Will address in follow-up #{{issue}}"},
+		} {
+			if err := runCommand(t, f, args...); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Options are "Write a new reply", then templates alphabetically
+		prompter.selected = 2
+		prompter.inputs = map[string]string{"issue:": "7"}
+		if err := runCommand(t, f, "reply", "PRRT_a"); err != nil {
+			t.Fatalf("interactive reply: %v", err)
+		}
+		if got, want := replyBody(gh), "Will address in follow-up #7"; got != want {
+			t.Errorf("reply body = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
+	"github.com/hamishmorgan/gh-talk/internal/ops"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo recent replies, resolutions, reactions and hides",
+	Long: `Undo conversation changes recorded in the local journal.
+
+Every reply, resolve, unresolve, react, hide and unhide is appended to a
+journal with the target's prior state. Undo replays the inverse, newest
+first:
+
+  reply      deletes the posted reply
+  resolve    unresolves the thread
+  unresolve  resolves the thread again
+  react      removes the reaction
+  unreact    adds the reaction back
+  hide       unhides the comment
+  unhide     hides the comment again with its previous reason
+
+Changes that did nothing (e.g. resolving a resolved thread) are skipped.
+The journal lives at ~/.local/state/gh-talk/journal.jsonl, or at
+GH_TALK_JOURNAL; set GH_TALK_JOURNAL=off to turn journaling off.
+
+Examples:
+  # Undo the last change
+  gh talk undo
+
+  # Undo the last 20 changes
+  gh talk undo --last 20
+
+  # Undo everything from the last 10 minutes, without confirmation
+  gh talk undo --since 10m --yes
+
+  # Show what would be undone
+  gh talk undo --last 5 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+func init() {
+	undoCmd.Flags().Int("last", 1, "Undo the last N changes")
+	undoCmd.Flags().Duration("since", 0, "Undo changes made within this long (e.g. 10m, 1h)")
+	undoCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	undoCmd.MarkFlagsMutuallyExclusive("last", "since")
+}
+
+// undoClient is the API surface needed to reverse journal entries
+type undoClient interface {
+	ops.Mutator
+	DeleteReviewComment(ctx context.Context, commentID string) error
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	j, err := journal.Default()
+	if err != nil {
+		return err
+	}
+	if j == nil {
+		return fmt.Errorf("journaling is turned off (GH_TALK_JOURNAL=off)")
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		return err
+	}
+
+	last, _ := cmd.Flags().GetInt("last")
+	since, _ := cmd.Flags().GetDuration("since")
+	selected, err := selectUndo(journal.Undoable(entries), last, since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintln(factory.IOStreams.Out, "Nothing to undo")
+		return nil
+	}
+
+	fmt.Fprintf(factory.IOStreams.Out, "Undo %d changes:\n\n", len(selected))
+	for _, e := range selected {
+		fmt.Fprintf(factory.IOStreams.Out, "  ↶ %s  %-40s → %s\n", e.Time.Local().Format("2006-01-02 15:04"), e, undoAction(e))
+	}
+	fmt.Fprintln(factory.IOStreams.Out)
+
+	if dryRunFormat(cmd) != "" {
+		fmt.Fprintln(factory.IOStreams.Out, "Dry run: nothing was sent")
+		return nil
+	}
+
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	if !skipConfirm {
+		p := factory.Prompter()
+		confirmed, err := p.Confirm(fmt.Sprintf("Undo %d changes?", len(selected)), false)
+		if err != nil || !confirmed {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	// Each entry is undone on the host it was made on
+	clients := make(map[string]*api.Client)
+	clientFor := func(e journal.Entry) (*api.Client, error) {
+		host := e.Host
+		if host == "" {
+			host = commandHost(cmd)
+		}
+		if client, ok := clients[host]; ok {
+			return client, nil
+		}
+		client, err := hostClient(factory.Client, host)
+		if err != nil {
+			return nil, err
+		}
+		// Undo entries are recorded below instead, so they are never undone again
+		client.SetJournal(nil)
+		clients[host] = client
+		return client, nil
+	}
+
+	for _, e := range selected {
+		client, err := clientFor(e)
+		if err != nil {
+			return err
+		}
+		if err := undoEntry(ctx, client, e); err != nil {
+			return fmt.Errorf("failed to undo %s: %w", e, err)
+		}
+		if _, err := j.Append(journal.Entry{Op: journal.Undo, ID: e.ID, PR: e.PR, Host: e.Host, Undoes: e.Seq}); err != nil {
+			return err
+		}
+		fmt.Fprintf(factory.IOStreams.Out, "✓ Undid %s\n", e)
+	}
+
+	return nil
+}
+
+// selectUndo picks entries to undo from undoable entries, newest first:
+// those within since of now when since is set, otherwise the last n
+func selectUndo(undoable []journal.Entry, n int, since time.Duration, now time.Time) ([]journal.Entry, error) {
+	if since > 0 {
+		cutoff := now.Add(-since)
+		var selected []journal.Entry
+		for _, e := range undoable {
+			if e.Time.Before(cutoff) {
+				break
+			}
+			selected = append(selected, e)
+		}
+		return selected, nil
+	}
+
+	if n < 1 {
+		return nil, fmt.Errorf("--last must be at least 1")
+	}
+	if n > len(undoable) {
+		n = len(undoable)
+	}
+	return undoable[:n], nil
+}
+
+// undoAction describes the inverse of an entry
+func undoAction(e journal.Entry) string {
+	switch e.Op {
+	case journal.Reply:
+		return "delete reply " + e.Reply
+	case journal.Resolve:
+		return "unresolve"
+	case journal.Unresolve:
+		return "resolve"
+	case journal.React:
+		return "remove " + contentToEmoji(e.Reaction)
+	case journal.Unreact:
+		return "add " + contentToEmoji(e.Reaction)
+	case journal.Hide:
+		return "unhide"
+	case journal.Unhide:
+		return fmt.Sprintf("hide (%s)", strings.ToLower(e.PriorReason))
+	}
+	return "unknown"
+}
+
+// undoEntry sends the inverse of an entry
+func undoEntry(ctx context.Context, client undoClient, e journal.Entry) error {
+	var err error
+	switch e.Op {
+	case journal.Reply:
+		if e.Reply == "" {
+			return fmt.Errorf("the posted reply was not recorded")
+		}
+		err = client.DeleteReviewComment(ctx, e.Reply)
+	case journal.Resolve:
+		_, err = client.UnresolveThread(ctx, e.ID)
+	case journal.Unresolve:
+		_, err = client.ResolveThread(ctx, e.ID)
+	case journal.React:
+		_, err = client.RemoveReaction(ctx, e.ID, e.Reaction)
+	case journal.Unreact:
+		_, err = client.AddReaction(ctx, e.ID, e.Reaction)
+	case journal.Hide:
+		_, err = client.UnminimizeComment(ctx, e.ID)
+	case journal.Unhide:
+		if e.PriorReason == "" {
+			return fmt.Errorf("the reason the comment was hidden with was not recorded")
+		}
+		classifier, parseErr := api.ParseClassifier(e.PriorReason)
+		if parseErr != nil {
+			return parseErr
+		}
+		_, err = client.MinimizeComment(ctx, e.ID, classifier)
+	default:
+		return fmt.Errorf("cannot undo %s", e.Op)
+	}
+	return err
+}
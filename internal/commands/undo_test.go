@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/journal"
+)
+
+func (f *fakeBatchClient) DeleteReviewComment(ctx context.Context, commentID string) error {
+	return f.record("delete " + commentID)
+}
+
+func TestSelectUndo(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	undoable := []journal.Entry{
+		{Seq: 3, Time: now.Add(-1 * time.Minute)},
+		{Seq: 2, Time: now.Add(-5 * time.Minute)},
+		{Seq: 1, Time: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name  string
+		last  int
+		since time.Duration
+		want  []int
+	}{
+		{"last one", 1, 0, []int{3}},
+		{"last more than journal", 10, 0, []int{3, 2, 1}},
+		{"since", 1, 10 * time.Minute, []int{3, 2}},
+		{"since nothing", 1, 30 * time.Second, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectUndo(undoable, tt.last, tt.since, now)
+			if err != nil {
+				t.Fatalf("selectUndo() error = %v", err)
+			}
+			var got []int
+			for _, e := range selected {
+				got = append(got, e.Seq)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("selectUndo() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("selectUndo() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := selectUndo(undoable, 0, 0, now); err == nil {
+		t.Error("selectUndo() with --last 0 should fail")
+	}
+}
+
+func TestUndoEntry(t *testing.T) {
+	client := &fakeBatchClient{}
+	entries := []journal.Entry{
+		{Op: journal.Reply, ID: "PRRT_a", Reply: "PRRC_new"},
+		{Op: journal.Resolve, ID: "PRRT_a"},
+		{Op: journal.Unresolve, ID: "PRRT_b"},
+		{Op: journal.React, ID: "PRRC_1", Reaction: "ROCKET"},
+		{Op: journal.Unreact, ID: "PRRC_1", Reaction: "EYES"},
+		{Op: journal.Hide, ID: "IC_1"},
+		{Op: journal.Unhide, ID: "IC_2", PriorReason: "off-topic"},
+	}
+
+	for _, e := range entries {
+		if err := undoEntry(context.Background(), client, e); err != nil {
+			t.Fatalf("undoEntry(%s) error = %v", e, err)
+		}
+	}
+
+	want := "delete PRRC_new,unresolve PRRT_a,resolve PRRT_b,unreact PRRC_1 ROCKET,react PRRC_1 EYES,unhide IC_1,hide IC_2"
+	if got := strings.Join(client.calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+
+	if err := undoEntry(context.Background(), client, journal.Entry{Op: journal.Reply, ID: "PRRT_a"}); err == nil {
+		t.Error("undoing a reply without its comment ID should fail")
+	}
+}
+
+func TestUndoUsesEntryHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	t.Setenv("GH_TALK_JOURNAL", path)
+
+	j := journal.Open(path)
+	for _, e := range []journal.Entry{
+		{Op: journal.Resolve, ID: "PRRT_a", PR: "o/r#1", Host: "github.example.com", Prior: journal.StateOpen},
+		{Op: journal.Resolve, ID: "PRRT_b", PR: "o/r#2", Prior: journal.StateOpen},
+	} {
+		if _, err := j.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gh := newFakeGitHub(t, map[string]string{
+		"UnresolveThread": `{"data":{"unresolveReviewThread":{"thread":{"id":"PRRT_a","isResolved":false}}}}`,
+	})
+	f, _, _ := newTestFactory(t, gh)
+	var hosts []string
+	f.Client = func(host string) (*api.Client, error) {
+		hosts = append(hosts, host)
+		return api.NewClientWithOptions(gh.clientOptions())
+	}
+
+	if err := runCommand(t, f, "undo", "--last", "2", "--yes"); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	// Newest first; the entry without a host uses the current repository's
+	if got := strings.Join(hosts, ","); got != "github.com,github.example.com" {
+		t.Errorf("clients created for %s, want github.com,github.example.com", got)
+	}
+	if n := len(mutationInputs(gh, "UnresolveThread")); n != 2 {
+		t.Errorf("sent %d unresolves, want 2", n)
+	}
+}
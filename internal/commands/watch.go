@@ -0,0 +1,283 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/cache"
+	"github.com/hamishmorgan/gh-talk/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream new conversation activity",
+	Long: `Poll a pull request and print new conversation activity as it happens:
+new threads, replies and PR comments, resolutions, unresolutions and
+reactions.
+
+Each poll checks a fingerprint of the pull request (resolutions, edits,
+hidden states and reaction counts) and fetches it in full only when the
+fingerprint changed.
+
+The last seen state is kept in the gh-talk cache directory, so restarting
+watch only reports activity that happened since it last ran.
+
+Placeholders in --exec are replaced for each event:
+  {id}       Comment ID, or thread ID for thread events
+  {thread}   Thread ID
+  {comment}  Comment ID (empty for thread events)
+  {type}     Event type (thread_added, comment_added, comment_edited,
+             comment_minimized, comment_unminimized, thread_resolved,
+             thread_unresolved, reaction_added, reaction_removed)
+  {author}   Login of the commenter or resolver
+
+Examples:
+  # Watch the current PR
+  gh talk watch
+
+  # Poll every 10 seconds and emit NDJSON events
+  gh talk watch --pr 123 --interval 10s --format ndjson
+
+  # Run a command for each event
+  gh talk watch --exec 'notify-send "PR activity" {type}'`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", 30*time.Second, "Polling interval")
+	watchCmd.Flags().String("format", "text", "Output format (text, ndjson)")
+	watchCmd.Flags().String("exec", "", "Command to run for each event")
+}
+
+// watchState is the high-water mark persisted between runs
+type watchState struct {
+	UpdatedAt   time.Time
+	PullRequest *api.PullRequest
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	owner, name, err := getRepository(cmd)
+	if err != nil {
+		return err
+	}
+
+	prNum, err := getCurrentPR(cmd)
+	if err != nil {
+		return err
+	}
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != "text" && format != "ndjson" {
+		return fmt.Errorf("unknown format: %s\n\nValid formats: text, ndjson", format)
+	}
+
+	execTemplate, _ := cmd.Flags().GetString("exec")
+
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	// The same OWNER/REPO#N on different hosts are different PRs
+	host := commandHost(cmd)
+	if host == "" {
+		host = "github.com"
+	}
+	stateKey := cache.Key("watch", host, owner, name, fmt.Sprintf("%d", prNum))
+
+	var state watchState
+	found, err := cache.Load(stateKey, &state)
+	if err != nil {
+		fmt.Fprintf(factory.IOStreams.ErrOut, "! ignoring saved watch state: %v\n", err)
+		found = false
+	}
+
+	if format == "text" {
+		fmt.Fprintf(factory.IOStreams.ErrOut, "Watching %s/%s#%d every %s (Ctrl+C to stop)\n", owner, name, prNum, interval)
+	}
+
+	w := &watcher{client: client, owner: owner, name: name, pr: prNum}
+	if found {
+		w.previous = state.PullRequest
+	}
+
+	for {
+		events, changed, err := w.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(factory.IOStreams.ErrOut, "! poll failed: %v\n", err)
+		} else {
+			for _, e := range events {
+				if err := writeWatchEvent(factory.IOStreams.Out, format, e); err != nil {
+					return err
+				}
+				if execTemplate != "" {
+					runWatchExec(execTemplate, e)
+				}
+			}
+
+			if changed {
+				state = watchState{UpdatedAt: time.Now(), PullRequest: w.previous}
+				if err := cache.Save(stateKey, state); err != nil {
+					fmt.Fprintf(factory.IOStreams.ErrOut, "! failed to save watch state: %v\n", err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watcher keeps the last seen state of a pull request between polls
+type watcher struct {
+	client *api.Client
+	owner  string
+	name   string
+	pr     int
+
+	previous *api.PullRequest
+	activity *api.Activity
+}
+
+// poll returns the events since the previous poll and whether the last
+// seen state changed.
+//
+// Each poll checks the pull request's activity fingerprint and only
+// fetches it in full when the fingerprint changed.
+// A nil previous state means there is no high-water mark yet: the current
+// state becomes the baseline and no events are reported.
+func (w *watcher) poll(ctx context.Context) ([]diff.Event, bool, error) {
+	activity, err := w.client.GetActivity(ctx, w.owner, w.name, w.pr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if w.previous != nil && activity.Equal(w.activity) {
+		return nil, false, nil
+	}
+
+	pr, err := w.client.GetPullRequest(ctx, w.owner, w.name, w.pr)
+	if err != nil {
+		return nil, false, err
+	}
+	w.activity = activity
+
+	previous := w.previous
+	w.previous = pr
+	if previous == nil {
+		return nil, true, nil
+	}
+	return diff.ComparePullRequests(previous, pr), true, nil
+}
+
+// writeWatchEvent prints an event as a human line or an NDJSON record
+func writeWatchEvent(w io.Writer, format string, e diff.Event) error {
+	if format == "ndjson" {
+		record := struct {
+			Time string `json:"time"`
+			diff.Event
+		}{
+			Time:  time.Now().UTC().Format(time.RFC3339),
+			Event: e,
+		}
+		return json.NewEncoder(w).Encode(record)
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s\n", time.Now().Format("15:04:05"), describeEvent(e))
+	return err
+}
+
+// describeEvent formats an event as a single human-readable line
+func describeEvent(e diff.Event) string {
+	location := e.ThreadID
+	switch {
+	case e.Path != "":
+		location = fmt.Sprintf("%s:%d (%s)", e.Path, e.Line, e.ThreadID)
+	case e.ThreadID == "":
+		location = "the conversation"
+	}
+
+	switch e.Type {
+	case diff.ThreadAdded:
+		return fmt.Sprintf("💬 @%s started a thread on %s: %s", e.Author, location, truncate(firstLine(e.Body), 60))
+	case diff.CommentAdded:
+		return fmt.Sprintf("💬 @%s replied on %s: %s", e.Author, location, truncate(firstLine(e.Body), 60))
+	case diff.CommentEdited:
+		return fmt.Sprintf("✎ Edited %s on %s: %s", e.CommentID, location, truncate(firstLine(e.Body), 60))
+	case diff.CommentMinimized:
+		if e.Reason != "" {
+			return fmt.Sprintf("⊘ Hidden %s on %s (%s)", e.CommentID, location, strings.ToLower(e.Reason))
+		}
+		return fmt.Sprintf("⊘ Hidden %s on %s", e.CommentID, location)
+	case diff.CommentUnminimized:
+		return fmt.Sprintf("◌ Unhidden %s on %s", e.CommentID, location)
+	case diff.ThreadResolved:
+		if e.Author != "" {
+			return fmt.Sprintf("✓ @%s resolved %s", e.Author, location)
+		}
+		return fmt.Sprintf("✓ Resolved %s", location)
+	case diff.ThreadUnresolved:
+		return fmt.Sprintf("○ Unresolved %s", location)
+	case diff.ReactionAdded:
+		return fmt.Sprintf("%s +%d on %s", contentToEmoji(e.Reaction), e.Count, e.CommentID)
+	case diff.ReactionRemoved:
+		return fmt.Sprintf("%s -%d on %s", contentToEmoji(e.Reaction), e.Count, e.CommentID)
+	default:
+		return fmt.Sprintf("%s %s", e.Type, e.ID())
+	}
+}
+
+// runWatchExec runs the --exec command for an event through the shell
+func runWatchExec(template string, e diff.Event) {
+	replacer := strings.NewReplacer(
+		"{id}", shellQuote(e.ID()),
+		"{thread}", shellQuote(e.ThreadID),
+		"{comment}", shellQuote(e.CommentID),
+		"{type}", shellQuote(string(e.Type)),
+		"{author}", shellQuote(e.Author),
+	)
+
+	c := exec.Command("sh", "-c", replacer.Replace(template))
+	c.Stdout = factory.IOStreams.Out
+	c.Stderr = factory.IOStreams.ErrOut
+	if err := c.Run(); err != nil {
+		fmt.Fprintf(factory.IOStreams.ErrOut, "! --exec failed for %s: %v\n", e.ID(), err)
+	}
+}
+
+// shellQuote quotes s for safe use as a single sh argument
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+	"github.com/hamishmorgan/gh-talk/internal/diff"
+)
+
+const watchInitialActivity = `{"data":{"repository":{"pullRequest":{
+  "comments":{"nodes":[]},
+  "reviewThreads":{"nodes":[{"id":"PRRT_a","isResolved":false,"comments":{"nodes":[{"id":"PRRC_1","reactionGroups":[]}]}}]}}}}}`
+
+const watchUpdatedActivity = `{"data":{"repository":{"pullRequest":{
+  "comments":{"nodes":[{"id":"IC_1","reactionGroups":[]}]},
+  "reviewThreads":{"nodes":[{"id":"PRRT_a","isResolved":true,"comments":{"nodes":[
+    {"id":"PRRC_1","reactionGroups":[{"content":"THUMBS_UP","users":{"totalCount":1}}]},
+    {"id":"PRRC_2","reactionGroups":[]}]}}]}}}}}`
+
+const watchReactedActivity = `{"data":{"repository":{"pullRequest":{
+  "comments":{"nodes":[{"id":"IC_1","reactionGroups":[{"content":"EYES","users":{"totalCount":1}}]}]},
+  "reviewThreads":{"nodes":[{"id":"PRRT_a","isResolved":true,"comments":{"nodes":[
+    {"id":"PRRC_1","reactionGroups":[{"content":"THUMBS_UP","users":{"totalCount":1}}]},
+    {"id":"PRRC_2","reactionGroups":[]}]}}]}}}}}`
+
+const watchInitialState = `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[
+  {"id":"PRRT_a","isResolved":false,"path":"main.go","line":7,"comments":{"nodes":[
+    {"id":"PRRC_1","body":"Use a constant","author":{"login":"reviewer"},"reactionGroups":[]}
+  ]}}
+]}}}}}`
+
+const watchUpdatedState = `{"data":{"repository":{"pullRequest":{
+  "comments":{"nodes":[
+    {"id":"IC_1","body":"Ready for another look","author":{"login":"author"},"reactionGroups":[]}
+  ]},
+  "reviewThreads":{"nodes":[
+  {"id":"PRRT_a","isResolved":true,"path":"main.go","line":7,"resolvedBy":{"login":"author"},"comments":{"nodes":[
+    {"id":"PRRC_1","body":"Use a constant","author":{"login":"reviewer"},"reactionGroups":[
+      {"content":"THUMBS_UP","users":{"totalCount":1},"viewerHasReacted":false}
+    ]},
+    {"id":"PRRC_2","body":"Done","author":{"login":"author"},"reactionGroups":[]}
+  ]}}
+]}}}}}`
+
+// watchReactedState is watchUpdatedState with an 👀 on IC_1
+var watchReactedState = strings.Replace(watchUpdatedState,
+	`"author":{"login":"author"},"reactionGroups":[]}
+  ]},`,
+	`"author":{"login":"author"},"reactionGroups":[{"content":"EYES","users":{"totalCount":1},"viewerHasReacted":false}]}
+  ]},`, 1)
+
+func TestWatcherPoll(t *testing.T) {
+	ctx := context.Background()
+	gh := newFakeGitHub(t, map[string]string{
+		"GetActivity": watchInitialActivity,
+		"ListThreads": watchInitialState,
+	})
+	client, err := api.NewClientWithOptions(gh.clientOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &watcher{client: client, owner: "owner", name: "repo", pr: 1}
+
+	poll := func(name string, wantOperations string, wantTypes ...diff.EventType) []diff.Event {
+		t.Helper()
+		before := len(gh.operations())
+		events, changed, err := w.poll(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := strings.Join(gh.operations()[before:], ","); got != wantOperations {
+			t.Errorf("%s: operations = %s, want %s", name, got, wantOperations)
+		}
+		if wantChanged := wantOperations != "GetActivity"; changed != wantChanged {
+			t.Errorf("%s: changed = %v, want %v", name, changed, wantChanged)
+		}
+		if len(events) != len(wantTypes) {
+			t.Fatalf("%s: reported %+v, want types %v", name, events, wantTypes)
+		}
+		for i, typ := range wantTypes {
+			if events[i].Type != typ {
+				t.Errorf("%s: event %d type = %s, want %s", name, i, events[i].Type, typ)
+			}
+		}
+		return events
+	}
+
+	// First poll without a high-water mark only records the baseline
+	poll("first poll", "GetActivity,ListThreads")
+
+	// The reply, resolution, reaction and PR comment change the activity
+	gh.respond("GetActivity", watchUpdatedActivity)
+	gh.respond("ListThreads", watchUpdatedState)
+	events := poll("second poll", "GetActivity,ListThreads",
+		diff.ThreadResolved, diff.ReactionAdded, diff.CommentAdded, diff.CommentAdded)
+	if events[3].CommentID != "IC_1" || events[3].ThreadID != "" {
+		t.Errorf("PR comment event = %+v, want IC_1 outside any thread", events[3])
+	}
+
+	// Unchanged activity produces no events and skips the full fetch
+	poll("unchanged poll", "GetActivity")
+
+	// A reaction alone changes the activity and is reported straight away
+	gh.respond("GetActivity", watchReactedActivity)
+	gh.respond("ListThreads", watchReactedState)
+	events = poll("reaction poll", "GetActivity,ListThreads", diff.ReactionAdded)
+	if events[0].CommentID != "IC_1" || events[0].Reaction != "EYES" {
+		t.Errorf("reaction event = %+v, want EYES on IC_1", events[0])
+	}
+}
+
+func TestWriteWatchEvent(t *testing.T) {
+	e := diff.Event{Type: diff.CommentAdded, ThreadID: "PRRT_a", CommentID: "PRRC_2", Path: "main.go", Line: 7, Author: "author", Body: "Done"}
+
+	var buf bytes.Buffer
+	if err := writeWatchEvent(&buf, "ndjson", e); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"type":"comment_added"`) || !strings.HasSuffix(buf.String(), "}\n") {
+		t.Errorf("unexpected NDJSON line: %q", buf.String())
+	}
+
+	if got := describeEvent(e); got != "💬 @author replied on main.go:7 (PRRT_a): Done" {
+		t.Errorf("describeEvent() = %q", got)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote() = %s", got)
+	}
+}
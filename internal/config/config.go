@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the gh-talk configuration file
+type Config struct {
+	// Hosts holds settings for each GitHub host, keyed by hostname
+	Hosts map[string]HostConfig `yaml:"hosts,omitempty"`
+
+	// Templates are saved replies by name, as Go templates
+	Templates map[string]string `yaml:"templates,omitempty"`
+
+	// path is the file the config was loaded from
+	path string
+}
+
+// HostConfig holds settings for one GitHub host.
+//
+// GitHub Enterprise Server releases can lag behind github.com; listing the
+// reactions and minimize classifiers a server accepts lets gh-talk refuse
+// the others with a clear error before sending anything. Empty lists
+// allow everything.
+type HostConfig struct {
+	// Reactions are ReactionContent values, e.g. THUMBS_UP
+	Reactions []string `yaml:"reactions,omitempty"`
+	// Classifiers are ReportedContentClassifiers values, e.g. OUTDATED
+	Classifiers []string `yaml:"classifiers,omitempty"`
+}
+
+// Path returns the config file location.
+//
+// GH_TALK_CONFIG takes precedence, otherwise the file lives in the user
+// config directory (~/.config/gh-talk/config.yml on Linux).
+func Path() (string, error) {
+	if path := os.Getenv("GH_TALK_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh-talk", "config.yml"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determine config location: %w", err)
+	}
+	return filepath.Join(home, ".config", "gh-talk", "config.yml"), nil
+}
+
+// Load reads the config file at Path
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the config file at path. A missing file is an empty
+// config.
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Host returns the settings for a host. Hostnames are matched ignoring
+// case, and an empty host means github.com.
+func (c *Config) Host(host string) HostConfig {
+	if host == "" {
+		host = "github.com"
+	}
+	for name, hc := range c.Hosts {
+		if strings.EqualFold(name, host) {
+			return hc
+		}
+	}
+	return HostConfig{}
+}
+
+// Save writes the config back to the file it was loaded from
+func (c *Config) Save() error {
+	if c.path == "" {
+		return fmt.Errorf("save config: no config file")
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
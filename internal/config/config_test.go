@@ -1,8 +1,104 @@
 package config
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
 
 func TestPackageImport(t *testing.T) {
 	// Verify package compiles
 	t.Log("config package imports successfully")
 }
+
+func TestPath(t *testing.T) {
+	t.Run("GH_TALK_CONFIG", func(t *testing.T) {
+		t.Setenv("GH_TALK_CONFIG", "/tmp/talk.yml")
+		if got, _ := Path(); got != "/tmp/talk.yml" {
+			t.Errorf("Path() = %q, want /tmp/talk.yml", got)
+		}
+	})
+
+	t.Run("XDG_CONFIG_HOME", func(t *testing.T) {
+		t.Setenv("GH_TALK_CONFIG", "")
+		t.Setenv("XDG_CONFIG_HOME", "/xdg")
+		if got, _ := Path(); got != filepath.Join("/xdg", "gh-talk", "config.yml") {
+			t.Errorf("Path() = %q", got)
+		}
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		cfg, err := LoadFile(filepath.Join(dir, "missing.yml"))
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if len(cfg.Hosts) != 0 {
+			t.Errorf("LoadFile() = %+v, want empty config", cfg)
+		}
+	})
+
+	t.Run("hosts", func(t *testing.T) {
+		path := filepath.Join(dir, "config.yml")
+		data := `hosts:
+  GHE.example.com:
+    reactions: [THUMBS_UP, THUMBS_DOWN]
+    classifiers: [OUTDATED]
+`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+
+		want := HostConfig{Reactions: []string{"THUMBS_UP", "THUMBS_DOWN"}, Classifiers: []string{"OUTDATED"}}
+		if got := cfg.Host("ghe.example.com"); !reflect.DeepEqual(got, want) {
+			t.Errorf("Host(ghe.example.com) = %+v, want %+v", got, want)
+		}
+		if got := cfg.Host(""); !reflect.DeepEqual(got, HostConfig{}) {
+			t.Errorf("Host(\"\") = %+v, want empty", got)
+		}
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.yml")
+		if err := os.WriteFile(path, []byte("hosts: [\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("LoadFile() error = nil, want parse error")
+		}
+	})
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gh-talk", "config.yml")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	cfg.Templates = map[string]string{"fixed": "Fixed in {{sha}}"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if loaded.Templates["fixed"] != "Fixed in {{sha}}" {
+		t.Errorf("Templates = %v after save", loaded.Templates)
+	}
+
+	if err := (&Config{}).Save(); err == nil {
+		t.Error("Save() of a config not loaded from a file: error = nil")
+	}
+}
@@ -0,0 +1,191 @@
+package diff
+
+import (
+	"sort"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// EventType identifies the kind of change an Event describes
+type EventType string
+
+const (
+	ThreadAdded        EventType = "thread_added"
+	CommentAdded       EventType = "comment_added"
+	CommentEdited      EventType = "comment_edited"
+	CommentMinimized   EventType = "comment_minimized"
+	CommentUnminimized EventType = "comment_unminimized"
+	ThreadResolved     EventType = "thread_resolved"
+	ThreadUnresolved   EventType = "thread_unresolved"
+	ReactionAdded      EventType = "reaction_added"
+	ReactionRemoved    EventType = "reaction_removed"
+)
+
+// Event is a single change between two views of a pull request.
+// ThreadID is empty for top-level PR comments.
+type Event struct {
+	Type      EventType `json:"type"`
+	ThreadID  string    `json:"thread"`
+	CommentID string    `json:"comment,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Line      int       `json:"line,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	Body      string    `json:"body,omitempty"`
+	Reaction  string    `json:"reaction,omitempty"`
+	Count     int       `json:"count,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// ID returns the most specific node ID the event refers to
+func (e Event) ID() string {
+	if e.CommentID != "" {
+		return e.CommentID
+	}
+	return e.ThreadID
+}
+
+// Compare returns the events that turn old into new.
+//
+// Events are ordered by thread, following the order of threads in new,
+// then comments within each thread. Threads that disappeared are ignored
+// since GitHub does not delete review threads.
+func Compare(old, new []api.Thread) []Event {
+	oldThreads := make(map[string]api.Thread, len(old))
+	for _, t := range old {
+		oldThreads[t.ID] = t
+	}
+
+	var events []Event
+	for _, t := range new {
+		prev, existed := oldThreads[t.ID]
+		if !existed {
+			events = append(events, threadAddedEvents(t)...)
+			continue
+		}
+
+		if t.IsResolved != prev.IsResolved {
+			e := threadEvent(ThreadUnresolved, t)
+			if t.IsResolved {
+				e.Type = ThreadResolved
+				if t.ResolvedBy != nil {
+					e.Author = t.ResolvedBy.Login
+				}
+			}
+			events = append(events, e)
+		}
+
+		events = append(events, compareComments(t, prev.Comments, t.Comments)...)
+	}
+
+	return events
+}
+
+// ComparePullRequests returns the events that turn old into new, covering
+// review threads followed by top-level PR comments
+func ComparePullRequests(old, new *api.PullRequest) []Event {
+	events := Compare(old.ReviewThreads, new.ReviewThreads)
+	return append(events, compareComments(api.Thread{}, old.Comments, new.Comments)...)
+}
+
+// compareComments reports comments added, edited, minimized or reacted to
+func compareComments(t api.Thread, old, new []api.Comment) []Event {
+	prevComments := make(map[string]api.Comment, len(old))
+	for _, c := range old {
+		prevComments[c.ID] = c
+	}
+
+	var events []Event
+	for _, c := range new {
+		prev, existed := prevComments[c.ID]
+		if !existed {
+			events = append(events, commentEvent(CommentAdded, t, c))
+			events = append(events, reactionEvents(t, api.Comment{}, c)...)
+			continue
+		}
+
+		if c.Body != prev.Body {
+			events = append(events, commentEvent(CommentEdited, t, c))
+		}
+		if c.IsMinimized != prev.IsMinimized {
+			e := commentEvent(CommentUnminimized, t, c)
+			if c.IsMinimized {
+				e.Type = CommentMinimized
+				e.Reason = c.MinimizedReason
+			}
+			events = append(events, e)
+		}
+		events = append(events, reactionEvents(t, prev, c)...)
+	}
+	return events
+}
+
+func threadAddedEvents(t api.Thread) []Event {
+	e := threadEvent(ThreadAdded, t)
+	if len(t.Comments) > 0 {
+		first := t.Comments[0]
+		e.CommentID = first.ID
+		e.Author = first.Author.Login
+		e.Body = first.Body
+	}
+
+	events := []Event{e}
+	for i, c := range t.Comments {
+		if i > 0 {
+			events = append(events, commentEvent(CommentAdded, t, c))
+		}
+		events = append(events, reactionEvents(t, api.Comment{}, c)...)
+	}
+	return events
+}
+
+func threadEvent(typ EventType, t api.Thread) Event {
+	return Event{
+		Type:     typ,
+		ThreadID: t.ID,
+		Path:     t.Path,
+		Line:     t.Line,
+	}
+}
+
+func commentEvent(typ EventType, t api.Thread, c api.Comment) Event {
+	e := threadEvent(typ, t)
+	e.CommentID = c.ID
+	e.Author = c.Author.Login
+	e.Body = c.Body
+	return e
+}
+
+// reactionEvents reports reaction count changes on a comment.
+// Count holds the number of reactions added or removed.
+func reactionEvents(t api.Thread, old, new api.Comment) []Event {
+	counts := make(map[string]int)
+	for _, rg := range new.ReactionGroups {
+		counts[rg.Content] += rg.Users.TotalCount
+	}
+	for _, rg := range old.ReactionGroups {
+		counts[rg.Content] -= rg.Users.TotalCount
+	}
+
+	contents := make([]string, 0, len(counts))
+	for content, delta := range counts {
+		if delta != 0 {
+			contents = append(contents, content)
+		}
+	}
+	sort.Strings(contents)
+
+	events := make([]Event, 0, len(contents))
+	for _, content := range contents {
+		delta := counts[content]
+		e := threadEvent(ReactionAdded, t)
+		e.CommentID = new.ID
+		e.Reaction = content
+		e.Count = delta
+		if delta < 0 {
+			e.Type = ReactionRemoved
+			e.Count = -delta
+		}
+		events = append(events, e)
+	}
+	return events
+}
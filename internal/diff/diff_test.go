@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func TestCompare(t *testing.T) {
+	old := []api.Thread{
+		{
+			ID: "PRRT_a",
+			Comments: []api.Comment{
+				{ID: "PRRC_1", Body: "Fix this"},
+			},
+		},
+		{
+			ID:         "PRRT_b",
+			IsResolved: true,
+			Comments: []api.Comment{
+				{
+					ID: "PRRC_2",
+					ReactionGroups: []api.ReactionGroup{
+						{Content: "THUMBS_UP", Users: api.ReactionUsers{TotalCount: 2}},
+					},
+				},
+			},
+		},
+	}
+
+	new := []api.Thread{
+		{
+			ID:         "PRRT_a",
+			IsResolved: true,
+			ResolvedBy: &api.User{Login: "alice"},
+			Comments: []api.Comment{
+				{ID: "PRRC_1", Body: "Fix this"},
+				{ID: "PRRC_3", Body: "Done", Author: api.User{Login: "bob"}},
+			},
+		},
+		{
+			ID: "PRRT_b",
+			Comments: []api.Comment{
+				{
+					ID: "PRRC_2",
+					ReactionGroups: []api.ReactionGroup{
+						{Content: "THUMBS_UP", Users: api.ReactionUsers{TotalCount: 1}},
+						{Content: "EYES", Users: api.ReactionUsers{TotalCount: 1}},
+					},
+				},
+			},
+		},
+		{
+			ID:   "PRRT_c",
+			Path: "main.go",
+			Comments: []api.Comment{
+				{ID: "PRRC_4", Body: "New thread", Author: api.User{Login: "carol"}},
+			},
+		},
+	}
+
+	got := Compare(old, new)
+	want := []Event{
+		{Type: ThreadResolved, ThreadID: "PRRT_a", Author: "alice"},
+		{Type: CommentAdded, ThreadID: "PRRT_a", CommentID: "PRRC_3", Author: "bob", Body: "Done"},
+		{Type: ThreadUnresolved, ThreadID: "PRRT_b"},
+		{Type: ReactionAdded, ThreadID: "PRRT_b", CommentID: "PRRC_2", Reaction: "EYES", Count: 1},
+		{Type: ReactionRemoved, ThreadID: "PRRT_b", CommentID: "PRRC_2", Reaction: "THUMBS_UP", Count: 1},
+		{Type: ThreadAdded, ThreadID: "PRRT_c", CommentID: "PRRC_4", Path: "main.go", Author: "carol", Body: "New thread"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Compare() returned %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	threads := []api.Thread{
+		{ID: "PRRT_a", Comments: []api.Comment{{ID: "PRRC_1"}}},
+	}
+
+	if got := Compare(threads, threads); len(got) != 0 {
+		t.Errorf("Compare() of identical threads = %+v, want none", got)
+	}
+}
+
+func TestComparePullRequests(t *testing.T) {
+	old := &api.PullRequest{
+		ReviewThreads: []api.Thread{
+			{ID: "PRRT_a", Comments: []api.Comment{{ID: "PRRC_1", Body: "Fix this"}}},
+		},
+		Comments: []api.Comment{
+			{ID: "IC_1", Body: "Coverage 80%"},
+		},
+	}
+
+	new := &api.PullRequest{
+		ReviewThreads: []api.Thread{
+			{ID: "PRRT_a", Comments: []api.Comment{{ID: "PRRC_1", Body: "Fix this, please"}}},
+		},
+		Comments: []api.Comment{
+			{ID: "IC_1", Body: "Coverage 80%", IsMinimized: true, MinimizedReason: "OUTDATED"},
+			{ID: "IC_2", Body: "LGTM", Author: api.User{Login: "dave"}},
+		},
+	}
+
+	got := ComparePullRequests(old, new)
+	want := []Event{
+		{Type: CommentEdited, ThreadID: "PRRT_a", CommentID: "PRRC_1", Body: "Fix this, please"},
+		{Type: CommentMinimized, CommentID: "IC_1", Body: "Coverage 80%", Reason: "OUTDATED"},
+		{Type: CommentAdded, CommentID: "IC_2", Author: "dave", Body: "LGTM"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("ComparePullRequests() returned %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package diff compares two views of a pull request's conversations and
+// reports what changed between them.
+package diff
@@ -0,0 +1,117 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// Options controls how a conversation is rendered
+type Options struct {
+	// RedactHidden replaces the bodies of minimized comments with a note
+	RedactHidden bool
+}
+
+// Renderer writes a pull request conversation as a document
+type Renderer func(w io.Writer, pr *api.PullRequest, opts Options) error
+
+// Renderers maps export format names to renderers
+var Renderers = map[string]Renderer{
+	"markdown": Markdown,
+	"html":     HTML,
+	"json":     JSON,
+}
+
+// ReactionEmoji converts a GraphQL ReactionContent value to its emoji
+func ReactionEmoji(content string) string {
+	switch content {
+	case "THUMBS_UP":
+		return "👍"
+	case "THUMBS_DOWN":
+		return "👎"
+	case "LAUGH":
+		return "😄"
+	case "HOORAY":
+		return "🎉"
+	case "CONFUSED":
+		return "😕"
+	case "HEART":
+		return "❤️"
+	case "ROCKET":
+		return "🚀"
+	case "EYES":
+		return "👀"
+	default:
+		return content
+	}
+}
+
+// Reactions formats a comment's reactions as "👍 2 🚀 1"
+func Reactions(groups []api.ReactionGroup) string {
+	parts := make([]string, 0, len(groups))
+	for _, rg := range groups {
+		if rg.Users.TotalCount > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", ReactionEmoji(rg.Content), rg.Users.TotalCount))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// commentBody returns the body to render, honouring RedactHidden
+func commentBody(c api.Comment, opts Options) string {
+	if c.IsMinimized && opts.RedactHidden {
+		return fmt.Sprintf("Hidden comment (%s)", HiddenReason(c))
+	}
+	return c.Body
+}
+
+// HiddenReason returns a readable reason for a minimized comment, such as
+// "outdated"
+func HiddenReason(c api.Comment) string {
+	if c.MinimizedReason == "" {
+		return "hidden"
+	}
+	return strings.ToLower(strings.ReplaceAll(c.MinimizedReason, "_", "-"))
+}
+
+// timestamp formats a time for documents, in UTC
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// location formats a thread's file position
+func location(t api.Thread) string {
+	switch {
+	case t.Line == 0:
+		return t.Path
+	case t.StartLine > 0 && t.StartLine != t.Line:
+		return fmt.Sprintf("%s:%d-%d", t.Path, t.StartLine, t.Line)
+	default:
+		return fmt.Sprintf("%s:%d", t.Path, t.Line)
+	}
+}
+
+// threadStatus describes a thread's resolution state
+func threadStatus(t api.Thread) string {
+	if !t.IsResolved {
+		return "Open"
+	}
+	if t.ResolvedBy != nil {
+		return "Resolved by @" + t.ResolvedBy.Login
+	}
+	return "Resolved"
+}
+
+// threadHunk returns the diff hunk the thread was started on
+func threadHunk(t api.Thread) string {
+	if len(t.Comments) == 0 {
+		return ""
+	}
+	return t.Comments[0].DiffHunk
+}
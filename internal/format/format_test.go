@@ -1,8 +1,195 @@
 package format
 
-import "testing"
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
 
-func TestPackageImport(t *testing.T) {
-	// Verify package compiles
-	t.Log("format package imports successfully")
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func testPullRequest() *api.PullRequest {
+	created := time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC)
+	return &api.PullRequest{
+		Number:     7,
+		Title:      "Add widgets",
+		URL:        "https://github.com/o/r/pull/7",
+		Repository: "o/r",
+		Author:     api.User{Login: "author"},
+		CreatedAt:  created,
+		Comments: []api.Comment{
+			{ID: "IC_1", Body: "Looks good <b>overall</b>", Author: api.User{Login: "alice"}, CreatedAt: created},
+			{ID: "IC_2", Body: "Coverage: 80%", Author: api.User{Login: "codecov[bot]"}, CreatedAt: created, IsMinimized: true, MinimizedReason: "OUTDATED"},
+		},
+		ReviewThreads: []api.Thread{
+			{
+				ID:         "PRRT_1",
+				Path:       "main.go",
+				Line:       12,
+				IsResolved: true,
+				ResolvedBy: &api.User{Login: "author"},
+				Comments: []api.Comment{
+					{
+						ID:        "PRRC_1",
+						Body:      "Rename this",
+						DiffHunk:  "@@ -1,3 +1,4 @@\n+func widget() {}",
+						Author:    api.User{Login: "bob"},
+						CreatedAt: created,
+						ReactionGroups: []api.ReactionGroup{
+							{Content: "THUMBS_UP", Users: api.ReactionUsers{TotalCount: 2}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, testPullRequest(), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# Add widgets (o/r#7)",
+		"## Conversation",
+		"### @alice — 2025-01-02 03:04 UTC",
+		"> _Hidden (outdated)_",
+		"Coverage: 80%",
+		"### main.go:12 — Resolved by @author",
+		"```diff\n@@ -1,3 +1,4 @@\n+func widget() {}\n```",
+		"Reactions: 👍 2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown() missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"plain", "+func widget() {}", "```"},
+		{"inline code", "+// use `widget`", "```"},
+		{"fence", "+```go\n+code\n+```", "````"},
+		{"longer run", "+`````", "``````"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codeFence(tt.s); got != tt.want {
+				t.Errorf("codeFence(%q) = %s, want %s", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHidden(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer Renderer
+	}{
+		{"markdown", Markdown},
+		{"html", HTML},
+		{"json", JSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.renderer(&buf, testPullRequest(), Options{RedactHidden: true}); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(buf.String(), "Coverage: 80") {
+				t.Errorf("hidden comment body was not redacted\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestHTMLEscapesBodies(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, testPullRequest(), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "<b>overall</b>") {
+		t.Error("HTML() did not escape comment body")
+	}
+	if !strings.Contains(out, "main.go:12 — Resolved by @author") {
+		t.Errorf("HTML() missing thread heading\n%s", out)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JSON(&buf, testPullRequest(), Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc exportDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Comments) != 2 || len(doc.Threads) != 1 {
+		t.Fatalf("got %d comments, %d threads; want 2, 1", len(doc.Comments), len(doc.Threads))
+	}
+	thread := doc.Threads[0]
+	if thread.ResolvedBy != "author" || thread.DiffHunk == "" {
+		t.Errorf("thread = %+v", thread)
+	}
+	if len(thread.Comments[0].Reactions) != 1 || thread.Comments[0].Reactions[0].Count != 2 {
+		t.Errorf("reactions = %+v", thread.Comments[0].Reactions)
+	}
+}
+
+func TestReactionEmoji(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"THUMBS_UP", "👍"},
+		{"EYES", "👀"},
+		{"UNKNOWN", "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		if got := ReactionEmoji(tt.content); got != tt.want {
+			t.Errorf("ReactionEmoji(%s) = %s, want %s", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestFollowUpIssue(t *testing.T) {
+	pr := testPullRequest()
+	pr.ReviewThreads[0].Comments[0].URL = "https://github.com/o/r/pull/7#discussion_r1"
+	pr.ReviewThreads[0].Comments = append(pr.ReviewThreads[0].Comments, api.Comment{
+		ID: "PRRC_2", Body: "secret", Author: api.User{Login: "carol"}, IsMinimized: true, MinimizedReason: "SPAM",
+	})
+
+	got := FollowUpIssue(pr, pr.ReviewThreads)
+
+	for _, want := range []string{
+		"Deferred from review of https://github.com/o/r/pull/7 (Add widgets).",
+		"## main.go:12",
+		"https://github.com/o/r/pull/7#discussion_r1",
+		"```diff\n@@ -1,3 +1,4 @@\n+func widget() {}\n```",
+		"### @bob — 2025-01-02 03:04 UTC\n\nRename this",
+		"> _Hidden (spam)_",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FollowUpIssue() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "secret") {
+		t.Errorf("FollowUpIssue() includes a hidden comment body:\n%s", got)
+	}
 }
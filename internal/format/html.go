@@ -0,0 +1,79 @@
+package format
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// htmlTemplate renders a self-contained page with inline styles
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"body":      commentBody,
+	"withOpts":  func(opts Options, c api.Comment) htmlComment { return htmlComment{Opts: opts, Comment: c} },
+	"hidden":    HiddenReason,
+	"hunk":      threadHunk,
+	"location":  location,
+	"reactions": Reactions,
+	"status":    threadStatus,
+	"timestamp": timestamp,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.PR.Title}} ({{.PR.Repository}}#{{.PR.Number}})</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; color: #1f2328; }
+.meta, .when { color: #656d76; font-size: 0.9em; }
+.thread { border: 1px solid #d0d7de; border-radius: 6px; margin: 1.5em 0; }
+.thread > h3 { margin: 0; padding: 0.5em 1em; background: #f6f8fa; border-bottom: 1px solid #d0d7de; font-size: 1em; }
+.comment { padding: 0.5em 1em; border-top: 1px solid #eaeef2; }
+.body { white-space: pre-wrap; }
+.hidden { color: #656d76; font-style: italic; }
+.reactions { font-size: 0.9em; }
+pre.hunk { margin: 0; padding: 0.5em 1em; background: #f6f8fa; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>{{.PR.Title}} <span class="meta">{{.PR.Repository}}#{{.PR.Number}}</span></h1>
+<p class="meta">{{if .PR.URL}}<a href="{{.PR.URL}}">{{.PR.URL}}</a> · {{end}}@{{.PR.Author.Login}}{{with timestamp .PR.CreatedAt}} · opened {{.}}{{end}}</p>
+{{if .PR.Body}}<div class="body">{{.PR.Body}}</div>{{end}}
+{{if .PR.Comments}}
+<h2>Conversation</h2>
+<div class="thread">
+{{range .PR.Comments}}{{template "comment" withOpts $.Opts .}}{{end}}
+</div>
+{{end}}
+{{if .PR.ReviewThreads}}
+<h2>Review Threads</h2>
+{{range .PR.ReviewThreads}}
+<div class="thread">
+<h3>{{location .}} — {{status .}}{{if .IsOutdated}} <span class="meta">(outdated)</span>{{end}}</h3>
+{{with hunk .}}<pre class="hunk">{{.}}</pre>{{end}}
+{{range .Comments}}{{template "comment" withOpts $.Opts .}}{{end}}
+</div>
+{{end}}
+{{end}}
+</body>
+</html>
+{{define "comment"}}<div class="comment">
+<p><strong>@{{.Comment.Author.Login}}</strong> <span class="when">{{timestamp .Comment.CreatedAt}}</span></p>
+{{if .Comment.IsMinimized}}<p class="hidden">Hidden ({{hidden .Comment}})</p>{{end}}
+{{if not (and .Comment.IsMinimized .Opts.RedactHidden)}}<div class="body">{{body .Comment .Opts}}</div>
+{{with reactions .Comment.ReactionGroups}}<p class="reactions">{{.}}</p>{{end}}{{end}}
+</div>
+{{end}}`))
+
+// htmlComment pairs a comment with render options for the comment template
+type htmlComment struct {
+	Opts    Options
+	Comment api.Comment
+}
+
+// HTML renders a pull request conversation as a self-contained HTML page
+func HTML(w io.Writer, pr *api.PullRequest, opts Options) error {
+	return htmlTemplate.Execute(w, struct {
+		PR   *api.PullRequest
+		Opts Options
+	}{pr, opts})
+}
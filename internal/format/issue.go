@@ -0,0 +1,41 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// FollowUpIssue renders the body of an issue tracking review threads
+// deferred from a pull request: each thread's location, permalink, diff
+// hunk and comments. Hidden comments are redacted.
+func FollowUpIssue(pr *api.PullRequest, threads []api.Thread) string {
+	var b strings.Builder
+
+	ref := fmt.Sprintf("#%d", pr.Number)
+	if pr.URL != "" {
+		ref = pr.URL
+	}
+	fmt.Fprintf(&b, "Deferred from review of %s", ref)
+	if pr.Title != "" {
+		fmt.Fprintf(&b, " (%s)", pr.Title)
+	}
+	fmt.Fprintf(&b, ".\n\n")
+
+	for _, t := range threads {
+		fmt.Fprintf(&b, "## %s\n\n", location(t))
+		if len(t.Comments) > 0 && t.Comments[0].URL != "" {
+			fmt.Fprintf(&b, "%s\n\n", t.Comments[0].URL)
+		}
+		if hunk := threadHunk(t); hunk != "" {
+			fence := codeFence(hunk)
+			fmt.Fprintf(&b, "%sdiff\n%s\n%s\n\n", fence, strings.TrimRight(hunk, "\n"), fence)
+		}
+		for _, c := range t.Comments {
+			writeMarkdownComment(&b, c, "###", Options{RedactHidden: true})
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
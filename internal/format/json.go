@@ -0,0 +1,116 @@
+package format
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// exportDocument is the JSON shape of an exported conversation
+type exportDocument struct {
+	Repository string          `json:"repository"`
+	Number     int             `json:"number"`
+	Title      string          `json:"title"`
+	URL        string          `json:"url"`
+	Author     string          `json:"author"`
+	CreatedAt  *time.Time      `json:"createdAt,omitempty"`
+	Body       string          `json:"body"`
+	Comments   []exportComment `json:"comments"`
+	Threads    []exportThread  `json:"threads"`
+}
+
+type exportThread struct {
+	ID         string          `json:"id"`
+	Path       string          `json:"path"`
+	Line       int             `json:"line,omitempty"`
+	StartLine  int             `json:"startLine,omitempty"`
+	IsResolved bool            `json:"isResolved"`
+	IsOutdated bool            `json:"isOutdated"`
+	ResolvedBy string          `json:"resolvedBy,omitempty"`
+	DiffHunk   string          `json:"diffHunk,omitempty"`
+	Comments   []exportComment `json:"comments"`
+}
+
+type exportComment struct {
+	ID              string           `json:"id"`
+	URL             string           `json:"url,omitempty"`
+	Author          string           `json:"author"`
+	CreatedAt       *time.Time       `json:"createdAt,omitempty"`
+	Body            string           `json:"body"`
+	IsMinimized     bool             `json:"isMinimized"`
+	MinimizedReason string           `json:"minimizedReason,omitempty"`
+	Reactions       []exportReaction `json:"reactions"`
+}
+
+type exportReaction struct {
+	Content string `json:"content"`
+	Count   int    `json:"count"`
+}
+
+// JSON renders a pull request conversation as an indented JSON document
+func JSON(w io.Writer, pr *api.PullRequest, opts Options) error {
+	doc := exportDocument{
+		Repository: pr.Repository,
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.URL,
+		Author:     pr.Author.Login,
+		CreatedAt:  optionalTime(pr.CreatedAt),
+		Body:       pr.Body,
+		Comments:   exportComments(pr.Comments, opts),
+		Threads:    make([]exportThread, 0, len(pr.ReviewThreads)),
+	}
+
+	for _, t := range pr.ReviewThreads {
+		thread := exportThread{
+			ID:         t.ID,
+			Path:       t.Path,
+			Line:       t.Line,
+			StartLine:  t.StartLine,
+			IsResolved: t.IsResolved,
+			IsOutdated: t.IsOutdated,
+			DiffHunk:   threadHunk(t),
+			Comments:   exportComments(t.Comments, opts),
+		}
+		if t.ResolvedBy != nil {
+			thread.ResolvedBy = t.ResolvedBy.Login
+		}
+		doc.Threads = append(doc.Threads, thread)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func exportComments(comments []api.Comment, opts Options) []exportComment {
+	result := make([]exportComment, 0, len(comments))
+	for _, c := range comments {
+		ec := exportComment{
+			ID:              c.ID,
+			URL:             c.URL,
+			Author:          c.Author.Login,
+			CreatedAt:       optionalTime(c.CreatedAt),
+			Body:            commentBody(c, opts),
+			IsMinimized:     c.IsMinimized,
+			MinimizedReason: c.MinimizedReason,
+			Reactions:       []exportReaction{},
+		}
+		for _, rg := range c.ReactionGroups {
+			if rg.Users.TotalCount > 0 {
+				ec.Reactions = append(ec.Reactions, exportReaction{Content: rg.Content, Count: rg.Users.TotalCount})
+			}
+		}
+		result = append(result, ec)
+	}
+	return result
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
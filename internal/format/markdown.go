@@ -0,0 +1,90 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// Markdown renders a pull request conversation as a Markdown document
+func Markdown(w io.Writer, pr *api.PullRequest, opts Options) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s (%s#%d)\n\n", pr.Title, pr.Repository, pr.Number)
+	meta := []string{}
+	if pr.URL != "" {
+		meta = append(meta, pr.URL)
+	}
+	if pr.Author.Login != "" {
+		meta = append(meta, "@"+pr.Author.Login)
+	}
+	if created := timestamp(pr.CreatedAt); created != "" {
+		meta = append(meta, "opened "+created)
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(meta, " · "))
+	}
+	if pr.Body != "" {
+		fmt.Fprintf(&b, "%s\n\n", pr.Body)
+	}
+
+	if len(pr.Comments) > 0 {
+		fmt.Fprintf(&b, "## Conversation\n\n")
+		for _, c := range pr.Comments {
+			writeMarkdownComment(&b, c, "###", opts)
+		}
+	}
+
+	if len(pr.ReviewThreads) > 0 {
+		fmt.Fprintf(&b, "## Review Threads\n\n")
+		for _, t := range pr.ReviewThreads {
+			fmt.Fprintf(&b, "### %s — %s\n\n", location(t), threadStatus(t))
+			if t.IsOutdated {
+				fmt.Fprintf(&b, "_Outdated_\n\n")
+			}
+			if hunk := threadHunk(t); hunk != "" {
+				fence := codeFence(hunk)
+				fmt.Fprintf(&b, "%sdiff\n%s\n%s\n\n", fence, strings.TrimRight(hunk, "\n"), fence)
+			}
+			for _, c := range t.Comments {
+				writeMarkdownComment(&b, c, "####", opts)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownComment(b *strings.Builder, c api.Comment, heading string, opts Options) {
+	fmt.Fprintf(b, "%s @%s — %s\n\n", heading, c.Author.Login, timestamp(c.CreatedAt))
+
+	if c.IsMinimized {
+		fmt.Fprintf(b, "> _Hidden (%s)_\n\n", HiddenReason(c))
+		if opts.RedactHidden {
+			return
+		}
+	}
+
+	fmt.Fprintf(b, "%s\n\n", commentBody(c, opts))
+	if reactions := Reactions(c.ReactionGroups); reactions != "" {
+		fmt.Fprintf(b, "Reactions: %s\n\n", reactions)
+	}
+}
+
+// codeFence returns a backtick fence longer than any run of backticks in
+// s, so s cannot close the code block early
+func codeFence(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
@@ -0,0 +1,3 @@
+// Package journal records conversation mutations in a local append-only
+// log so they can be undone.
+package journal
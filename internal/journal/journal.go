@@ -0,0 +1,237 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Operations recorded in the journal. They match the ops package kinds,
+// plus Undo for entries that reverse an earlier one.
+const (
+	Reply     = "reply"
+	React     = "react"
+	Unreact   = "unreact"
+	Resolve   = "resolve"
+	Unresolve = "unresolve"
+	Hide      = "hide"
+	Unhide    = "unhide"
+	Undo      = "undo"
+)
+
+// Prior states of a target
+const (
+	StateOpen       = "open"
+	StateResolved   = "resolved"
+	StateReacted    = "reacted"
+	StateNotReacted = "not-reacted"
+	StateVisible    = "visible"
+	StateHidden     = "hidden"
+)
+
+// Entry is one mutation in the journal
+type Entry struct {
+	// Seq numbers entries from 1 in the order they were appended
+	Seq  int       `json:"seq"`
+	Time time.Time `json:"time"`
+	Op   string    `json:"op"`
+	// ID is the thread or comment the mutation acted on
+	ID string `json:"id,omitempty"`
+	// PR is the pull request, as OWNER/REPO#NUMBER
+	PR string `json:"pr,omitempty"`
+	// Host is the GitHub host the mutation was sent to, e.g. github.com.
+	// Entries written before hosts were recorded have none.
+	Host string `json:"host,omitempty"`
+
+	// Prior is the target's state before the mutation, one of the State
+	// constants, or empty when it could not be looked up. PriorReason is
+	// the minimized reason of a hidden comment.
+	Prior       string `json:"prior,omitempty"`
+	PriorReason string `json:"priorReason,omitempty"`
+
+	Reaction string `json:"reaction,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// Reply is the ID of the comment a reply posted
+	Reply string `json:"reply,omitempty"`
+	// Undoes is the Seq of the entry an undo entry reversed
+	Undoes int `json:"undoes,omitempty"`
+}
+
+// NoOp reports whether the mutation left its target unchanged, so there
+// is nothing to undo
+func (e Entry) NoOp() bool {
+	switch e.Op {
+	case Resolve:
+		return e.Prior == StateResolved
+	case Unresolve:
+		return e.Prior == StateOpen
+	case React:
+		return e.Prior == StateReacted
+	case Unreact:
+		return e.Prior == StateNotReacted
+	case Hide:
+		return e.Prior == StateHidden
+	case Unhide:
+		return e.Prior == StateVisible
+	}
+	return false
+}
+
+// String formats the entry for listings, e.g. "resolve PRRT_abc (o/r#1)"
+func (e Entry) String() string {
+	s := fmt.Sprintf("%s %s", e.Op, e.ID)
+	if e.Reaction != "" {
+		s += " " + e.Reaction
+	}
+	if e.PR != "" {
+		s += fmt.Sprintf(" (%s)", e.PR)
+	}
+	return s
+}
+
+// Journal is an append-only file of entries, one JSON object per line
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Path returns the journal file location.
+//
+// GH_TALK_JOURNAL takes precedence, otherwise the file lives in the user
+// state directory (~/.local/state/gh-talk/journal.jsonl on Linux).
+func Path() (string, error) {
+	if path := os.Getenv("GH_TALK_JOURNAL"); path != "" {
+		return path, nil
+	}
+
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "gh-talk", "journal.jsonl"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determine journal location: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "gh-talk", "journal.jsonl"), nil
+}
+
+// Default opens the journal at Path. It returns nil when journaling is
+// turned off with GH_TALK_JOURNAL=off.
+func Default() (*Journal, error) {
+	if os.Getenv("GH_TALK_JOURNAL") == "off" {
+		return nil, nil
+	}
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return Open(path), nil
+}
+
+// Open returns the journal stored at path. The file is created on the
+// first Append.
+func Open(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Append numbers an entry, stamps it with the current time if unset, and
+// writes it to the journal
+func (j *Journal) Append(entry Entry) (Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries, err := j.read()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Seq = 1
+	if len(entries) > 0 {
+		entry.Seq = entries[len(entries)-1].Seq + 1
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("encode journal entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("create journal directory: %w", err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Entry{}, fmt.Errorf("open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return Entry{}, fmt.Errorf("write journal: %w", err)
+	}
+	return entry, nil
+}
+
+// Entries returns all entries, oldest first
+func (j *Journal) Entries() ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.read()
+}
+
+func (j *Journal) read() ([]Entry, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Undoable returns the entries that can still be undone, newest first:
+// mutations that changed something and have not been undone already
+func Undoable(entries []Entry) []Entry {
+	undone := make(map[int]bool)
+	for _, e := range entries {
+		if e.Op == Undo {
+			undone[e.Undoes] = true
+		}
+	}
+
+	var result []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Op == Undo || undone[e.Seq] || e.NoOp() {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendAndEntries(t *testing.T) {
+	j := Open(filepath.Join(t.TempDir(), "state", "journal.jsonl"))
+
+	entries, err := j.Entries()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Entries() on missing journal = %v, %v", entries, err)
+	}
+
+	first, err := j.Append(Entry{Op: Resolve, ID: "PRRT_a", Prior: StateOpen, PR: "o/r#1"})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	second, err := j.Append(Entry{Op: React, ID: "PRRC_1", Reaction: "EYES", Prior: StateNotReacted})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	if first.Seq != 1 || second.Seq != 2 {
+		t.Errorf("Seq = %d, %d, want 1, 2", first.Seq, second.Seq)
+	}
+	if first.Time.IsZero() {
+		t.Error("Append() should stamp the time")
+	}
+
+	entries, err = j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].PR != "o/r#1" || entries[1].Reaction != "EYES" {
+		t.Errorf("Entries() = %+v", entries)
+	}
+}
+
+func TestUndoable(t *testing.T) {
+	entries := []Entry{
+		{Seq: 1, Op: Resolve, ID: "PRRT_a", Prior: StateOpen},
+		{Seq: 2, Op: Resolve, ID: "PRRT_b", Prior: StateResolved},
+		{Seq: 3, Op: Hide, ID: "IC_1", Prior: StateVisible},
+		{Seq: 4, Op: Reply, ID: "PRRT_a", Reply: "PRRC_9"},
+		{Seq: 5, Op: Undo, ID: "IC_1", Undoes: 3},
+	}
+
+	got := Undoable(entries)
+	if len(got) != 2 || got[0].Seq != 4 || got[1].Seq != 1 {
+		t.Errorf("Undoable() = %+v, want entries 4 and 1", got)
+	}
+}
+
+func TestNoOp(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  bool
+	}{
+		{Entry{Op: Resolve, Prior: StateResolved}, true},
+		{Entry{Op: Resolve, Prior: StateOpen}, false},
+		{Entry{Op: Unresolve, Prior: StateOpen}, true},
+		{Entry{Op: React, Prior: StateReacted}, true},
+		{Entry{Op: Unreact, Prior: StateReacted}, false},
+		{Entry{Op: Hide, Prior: StateHidden}, true},
+		{Entry{Op: Unhide, Prior: StateHidden}, false},
+		{Entry{Op: Reply}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.NoOp(); got != tt.want {
+			t.Errorf("%s prior %q: NoOp() = %v, want %v", tt.entry.Op, tt.entry.Prior, got, tt.want)
+		}
+	}
+}
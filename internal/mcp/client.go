@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Client is a minimal MCP client, used to exercise servers in-process
+type Client struct {
+	in     *bufio.Scanner
+	out    io.Writer
+	nextID int
+}
+
+// NewClient returns a client reading responses from r and writing
+// requests to w
+func NewClient(r io.Reader, w io.Writer) *Client {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	return &Client{in: scanner, out: w}
+}
+
+// Connect starts s in a goroutine and returns a client talking to it.
+// Call the returned function to shut the server down.
+func Connect(ctx context.Context, s *Server) (*Client, func()) {
+	requestReader, requestWriter := io.Pipe()
+	responseReader, responseWriter := io.Pipe()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.Serve(ctx, requestReader, responseWriter)
+		responseWriter.Close()
+	}()
+
+	return NewClient(responseReader, requestWriter), func() {
+		requestWriter.Close()
+		<-done
+	}
+}
+
+// Call sends a request and decodes its result into result
+func (c *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+
+	req := struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  interface{}     `json:"params,omitempty"`
+	}{"2.0", id, method, params}
+
+	if err := json.NewEncoder(c.out).Encode(req); err != nil {
+		return fmt.Errorf("send %s: %w", method, err)
+	}
+
+	for c.in.Scan() {
+		var resp struct {
+			ID     json.RawMessage `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *RPCError       `json:"error"`
+		}
+		if err := json.Unmarshal(c.in.Bytes(), &resp); err != nil {
+			return fmt.Errorf("decode %s response: %w", method, err)
+		}
+		if string(resp.ID) != string(id) {
+			continue
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	}
+
+	if err := c.in.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%s: connection closed", method)
+}
+
+// Initialize performs the MCP handshake
+func (c *Client) Initialize(ctx context.Context) error {
+	params := map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "gh-talk-test", "version": "0"},
+	}
+	if err := c.Call(ctx, "initialize", params, nil); err != nil {
+		return err
+	}
+
+	notification := map[string]string{"jsonrpc": "2.0", "method": "notifications/initialized"}
+	return json.NewEncoder(c.out).Encode(notification)
+}
+
+// ListTools returns the server's tools
+func (c *Client) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var result struct {
+		Tools []ToolInfo `json:"tools"`
+	}
+	if err := c.Call(ctx, "tools/list", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool calls a tool with the given arguments
+func (c *Client) CallTool(ctx context.Context, name string, arguments interface{}) (*CallToolResult, error) {
+	var result CallToolResult
+	params := map[string]interface{}{"name": name, "arguments": arguments}
+	if err := c.Call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
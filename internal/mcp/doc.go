@@ -0,0 +1,4 @@
+// Package mcp implements a minimal Model Context Protocol server over
+// stdio (newline-delimited JSON-RPC 2.0) exposing tools, and an
+// in-process client for testing it.
+package mcp
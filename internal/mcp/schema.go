@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// FlagSchema derives a JSON schema for a command-line flag from its type,
+// usage and default value
+func FlagSchema(flag *pflag.Flag) *Schema {
+	s := &Schema{Description: flag.Usage}
+
+	switch flag.Value.Type() {
+	case "bool":
+		s.Type = "boolean"
+		if v, err := strconv.ParseBool(flag.DefValue); err == nil && v {
+			s.Default = v
+		}
+	case "int", "int64":
+		s.Type = "integer"
+		if v, err := strconv.Atoi(flag.DefValue); err == nil && v != 0 {
+			s.Default = v
+		}
+	case "stringSlice", "stringArray":
+		s.Type = "array"
+		s.Items = &Schema{Type: "string"}
+	default:
+		// Strings and durations are passed as text
+		s.Type = "string"
+		if flag.DefValue != "" {
+			s.Default = flag.DefValue
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Handler runs a tool with its JSON arguments and returns text output
+type Handler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// Tool is a tool exposed by the server
+type Tool struct {
+	ToolInfo
+	Handler Handler
+}
+
+// Server serves tools over MCP
+type Server struct {
+	Name    string
+	Version string
+
+	tools []Tool
+}
+
+// AddTool registers a tool
+func (s *Server) AddTool(tool Tool) {
+	s.tools = append(s.tools, tool)
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// closed or ctx is cancelled. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		resp := s.handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("write response: %w", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+	return nil
+}
+
+// handle returns the response to a request, or nil for notifications
+func (s *Server) handle(ctx context.Context, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error())
+	}
+
+	if req.ID == nil {
+		// Notifications, such as notifications/initialized, need no reply
+		return nil
+	}
+	if req.JSONRPC != "2.0" {
+		return errorResponse(req.ID, codeInvalidRequest, "jsonrpc must be 2.0")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var result initializeResult
+		result.ProtocolVersion = ProtocolVersion
+		result.ServerInfo.Name = s.Name
+		result.ServerInfo.Version = s.Version
+		return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+	case "ping":
+		return &response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}}
+	case "tools/list":
+		infos := make([]ToolInfo, len(s.tools))
+		for i, t := range s.tools {
+			infos[i] = t.ToolInfo
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Result: map[string]interface{}{"tools": infos}}
+	case "tools/call":
+		var params callToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return errorResponse(req.ID, codeInvalidParams, "invalid params: "+err.Error())
+		}
+		tool, ok := s.tool(params.Name)
+		if !ok {
+			return errorResponse(req.ID, codeInvalidParams, "unknown tool: "+params.Name)
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Result: callTool(ctx, tool, params.Arguments)}
+	default:
+		return errorResponse(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+func (s *Server) tool(name string) (Tool, bool) {
+	for _, t := range s.tools {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Tool{}, false
+}
+
+// callTool runs a tool, reporting failures as tool errors so the model
+// can see and react to them
+func callTool(ctx context.Context, tool Tool, arguments json.RawMessage) *CallToolResult {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+
+	text, err := tool.Handler(ctx, arguments)
+	if err != nil {
+		return &CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+	return &CallToolResult{Content: []Content{{Type: "text", Text: text}}}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &RPCError{Code: code, Message: message}}
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func testServer() *Server {
+	s := &Server{Name: "test", Version: "1.0"}
+	s.AddTool(Tool{
+		ToolInfo: ToolInfo{Name: "echo", Description: "Echo text", InputSchema: &Schema{Type: "object"}},
+		Handler: func(ctx context.Context, arguments json.RawMessage) (string, error) {
+			var args struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(arguments, &args); err != nil {
+				return "", err
+			}
+			if args.Text == "" {
+				return "", errors.New("text is required")
+			}
+			return args.Text, nil
+		},
+	})
+	return s
+}
+
+func TestServerWithClient(t *testing.T) {
+	ctx := context.Background()
+	client, stop := Connect(ctx, testServer())
+	defer stop()
+
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", tools)
+	}
+
+	result, err := client.CallTool(ctx, "echo", map[string]string{"text": "hello"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError || result.Text() != "hello" {
+		t.Errorf("result = %+v", result)
+	}
+
+	result, err = client.CallTool(ctx, "echo", map[string]string{})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || result.Text() != "text is required" {
+		t.Errorf("tool error result = %+v", result)
+	}
+
+	_, err = client.CallTool(ctx, "missing", nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeInvalidParams {
+		t.Errorf("unknown tool error = %v", err)
+	}
+
+	err = client.Call(ctx, "resources/list", nil, nil)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("unknown method error = %v", err)
+	}
+}
+
+func TestServeMalformedInput(t *testing.T) {
+	in := strings.NewReader("not json\n{\"jsonrpc\":\"2.0\",\"method\":\"notifications/initialized\"}\n")
+	var out bytes.Buffer
+
+	if err := testServer().Serve(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"code":-32700`) {
+		t.Errorf("output = %q, want one parse error", out.String())
+	}
+}
+
+func TestFlagSchema(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Bool("all", false, "Show all")
+	fs.Int("limit", 50, "Maximum")
+	fs.String("reason", "off-topic", "Reason")
+	fs.StringSlice("json", nil, "Fields")
+
+	tests := []struct {
+		flag    string
+		want    string
+		wantDef interface{}
+	}{
+		{"all", "boolean", nil},
+		{"limit", "integer", 50},
+		{"reason", "string", "off-topic"},
+		{"json", "array", nil},
+	}
+
+	for _, tt := range tests {
+		s := FlagSchema(fs.Lookup(tt.flag))
+		if s.Type != tt.want || s.Default != tt.wantDef {
+			t.Errorf("FlagSchema(%s) = %s default %v, want %s default %v", tt.flag, s.Type, s.Default, tt.want, tt.wantDef)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package ops describes conversation operations (reply, react, resolve,
+// hide and their inverses), validates them against a pull request, and
+// executes them in bulk.
+package ops
@@ -0,0 +1,101 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// Mutator is the subset of the API client that executes operations
+type Mutator interface {
+	ReplyToThread(ctx context.Context, threadID, body string) (*api.Comment, error)
+	ResolveThread(ctx context.Context, threadID string) (*api.Thread, error)
+	UnresolveThread(ctx context.Context, threadID string) (*api.Thread, error)
+	AddReaction(ctx context.Context, subjectID, content string) (*api.Reaction, error)
+	RemoveReaction(ctx context.Context, subjectID, content string) (*api.Reaction, error)
+	MinimizeComment(ctx context.Context, commentID, classifier string) (*api.Comment, error)
+	UnminimizeComment(ctx context.Context, commentID string) (*api.Comment, error)
+}
+
+// Result statuses
+const (
+	StatusOK      = "ok"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// Result records the outcome of one operation
+type Result struct {
+	Operation
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Executor runs validated changes in order
+type Executor struct {
+	Client Mutator
+
+	// ContinueOnError keeps going after a failed operation instead of
+	// skipping the rest
+	ContinueOnError bool
+}
+
+// Execute runs changes in order and returns one result per change.
+// No-op changes are skipped without calling the API.
+func (e *Executor) Execute(ctx context.Context, changes []Change) []Result {
+	results := make([]Result, 0, len(changes))
+	failed := false
+
+	for _, change := range changes {
+		result := Result{Operation: change.Operation}
+
+		switch {
+		case failed && !e.ContinueOnError:
+			result.Status = StatusSkipped
+			result.Error = "skipped after earlier failure"
+		case change.NoOp:
+			result.Status = StatusSkipped
+		default:
+			if err := e.Run(ctx, change.Operation); err != nil {
+				result.Status = StatusFailed
+				result.Error = err.Error()
+				failed = true
+			} else {
+				result.Status = StatusOK
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Run executes a single operation without validation
+func (e *Executor) Run(ctx context.Context, op Operation) error {
+	var err error
+	switch op.Op {
+	case Reply:
+		_, err = e.Client.ReplyToThread(ctx, op.Thread, op.Body)
+	case Resolve:
+		_, err = e.Client.ResolveThread(ctx, op.Thread)
+	case Unresolve:
+		_, err = e.Client.UnresolveThread(ctx, op.Thread)
+	case React:
+		_, err = e.Client.AddReaction(ctx, op.Comment, op.Reaction)
+	case Unreact:
+		_, err = e.Client.RemoveReaction(ctx, op.Comment, op.Reaction)
+	case Hide:
+		classifier, parseErr := api.ParseClassifier(reasonOrDefault(op.Reason))
+		if parseErr != nil {
+			return parseErr
+		}
+		_, err = e.Client.MinimizeComment(ctx, op.Comment, classifier)
+	case Unhide:
+		_, err = e.Client.UnminimizeComment(ctx, op.Comment)
+	default:
+		return fmt.Errorf("unknown op: %s", op.Op)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies an operation
+type Kind string
+
+const (
+	Reply     Kind = "reply"
+	React     Kind = "react"
+	Unreact   Kind = "unreact"
+	Resolve   Kind = "resolve"
+	Unresolve Kind = "unresolve"
+	Hide      Kind = "hide"
+	Unhide    Kind = "unhide"
+)
+
+// Kinds lists all operations in the order they are documented
+var Kinds = []Kind{Reply, React, Unreact, Resolve, Unresolve, Hide, Unhide}
+
+// Operation is a single change to a pull request conversation.
+//
+// Thread operations (reply, resolve, unresolve) use Thread; comment
+// operations (react, unreact, hide, unhide) use Comment. Reaction is a
+// GraphQL ReactionContent value such as THUMBS_UP.
+type Operation struct {
+	Op       Kind   `json:"op" yaml:"op"`
+	Thread   string `json:"thread,omitempty" yaml:"thread,omitempty"`
+	Comment  string `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Body     string `json:"body,omitempty" yaml:"body,omitempty"`
+	Reaction string `json:"reaction,omitempty" yaml:"reaction,omitempty"`
+	Reason   string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Target returns the ID of the thread or comment the operation acts on
+func (o Operation) Target() string {
+	switch o.Op {
+	case Reply, Resolve, Unresolve:
+		return o.Thread
+	default:
+		return o.Comment
+	}
+}
+
+// String formats the operation for messages, e.g. "resolve PRRT_abc"
+func (o Operation) String() string {
+	return fmt.Sprintf("%s %s", o.Op, o.Target())
+}
+
+// Validate checks that the operation has the fields it needs
+func (o Operation) Validate() error {
+	switch o.Op {
+	case Reply:
+		if o.Body == "" {
+			return fmt.Errorf("reply needs a body")
+		}
+		return requireID("thread", o.Thread, "PRRT_")
+	case Resolve, Unresolve:
+		return requireID("thread", o.Thread, "PRRT_")
+	case React, Unreact:
+		if o.Reaction == "" {
+			return fmt.Errorf("%s needs a reaction", o.Op)
+		}
+		return requireID("comment", o.Comment, "PRRC_", "IC_")
+	case Hide, Unhide:
+		return requireID("comment", o.Comment, "PRRC_", "IC_")
+	case "":
+		return fmt.Errorf("missing op")
+	default:
+		return fmt.Errorf("unknown op: %s", o.Op)
+	}
+}
+
+func requireID(field, id string, prefixes ...string) error {
+	if id == "" {
+		return fmt.Errorf("missing %s", field)
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(id, p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s ID %s - expected format: %s", field, id, strings.Join(prefixes, " or "))
+}
@@ -0,0 +1,185 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+func testPullRequest() *api.PullRequest {
+	return &api.PullRequest{
+		ReviewThreads: []api.Thread{
+			{
+				ID:               "PRRT_open",
+				Path:             "main.go",
+				Line:             7,
+				ViewerCanReply:   true,
+				ViewerCanResolve: true,
+				Comments: []api.Comment{
+					{
+						ID:                "PRRC_1",
+						ViewerCanReact:    true,
+						ViewerCanMinimize: true,
+						ReactionGroups: []api.ReactionGroup{
+							{Content: "THUMBS_UP", ViewerHasReacted: true},
+						},
+					},
+				},
+			},
+			{
+				ID:         "PRRT_resolved",
+				IsResolved: true,
+				Comments:   []api.Comment{{ID: "PRRC_2"}},
+			},
+		},
+		Comments: []api.Comment{
+			{ID: "IC_1", ViewerCanReact: true, ViewerCanMinimize: true, IsMinimized: true},
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ix := NewIndex(testPullRequest())
+
+	tests := []struct {
+		name     string
+		op       Operation
+		wantErr  string
+		wantNoOp bool
+		wantLoc  string
+	}{
+		{name: "reply", op: Operation{Op: Reply, Thread: "PRRT_open", Body: "Done"}, wantLoc: "main.go:7"},
+		{name: "reply without body", op: Operation{Op: Reply, Thread: "PRRT_open"}, wantErr: "needs a body"},
+		{name: "reply without permission", op: Operation{Op: Reply, Thread: "PRRT_resolved", Body: "x"}, wantErr: "cannot reply"},
+		{name: "unknown thread", op: Operation{Op: Resolve, Thread: "PRRT_missing"}, wantErr: "thread not found"},
+		{name: "resolve", op: Operation{Op: Resolve, Thread: "PRRT_open"}},
+		{name: "resolve resolved", op: Operation{Op: Resolve, Thread: "PRRT_resolved"}, wantNoOp: true},
+		{name: "unresolve without permission", op: Operation{Op: Unresolve, Thread: "PRRT_resolved"}, wantErr: "cannot unresolve"},
+		{name: "react already reacted", op: Operation{Op: React, Comment: "PRRC_1", Reaction: "THUMBS_UP"}, wantNoOp: true},
+		{name: "react", op: Operation{Op: React, Comment: "PRRC_1", Reaction: "ROCKET"}},
+		{name: "invalid reaction", op: Operation{Op: React, Comment: "PRRC_1", Reaction: "👍"}, wantErr: "invalid reaction"},
+		{name: "react without permission", op: Operation{Op: React, Comment: "PRRC_2", Reaction: "ROCKET"}, wantErr: "cannot react"},
+		{name: "hide", op: Operation{Op: Hide, Comment: "PRRC_1", Reason: "outdated"}},
+		{name: "hide invalid reason", op: Operation{Op: Hide, Comment: "PRRC_1", Reason: "boring"}, wantErr: "invalid reason"},
+		{name: "hide hidden", op: Operation{Op: Hide, Comment: "IC_1"}, wantNoOp: true, wantLoc: "conversation"},
+		{name: "invalid comment ID", op: Operation{Op: Unhide, Comment: "PRRT_open"}, wantErr: "invalid comment ID"},
+		{name: "unknown op", op: Operation{Op: "merge"}, wantErr: "unknown op"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := ix.Check(tt.op)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Check() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if change.NoOp != tt.wantNoOp {
+				t.Errorf("NoOp = %v, want %v", change.NoOp, tt.wantNoOp)
+			}
+			if tt.wantLoc != "" && change.Location != tt.wantLoc {
+				t.Errorf("Location = %q, want %q", change.Location, tt.wantLoc)
+			}
+		})
+	}
+}
+
+func TestCheckAllReportsEveryProblem(t *testing.T) {
+	ix := NewIndex(testPullRequest())
+
+	_, err := ix.CheckAll([]Operation{
+		{Op: Resolve, Thread: "PRRT_open"},
+		{Op: Resolve, Thread: "PRRT_missing"},
+		{Op: Reply, Thread: "PRRT_open"},
+	})
+	if err == nil {
+		t.Fatal("CheckAll() error = nil")
+	}
+	for _, want := range []string{"operation 2 (resolve PRRT_missing)", "operation 3 (reply PRRT_open)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+// fakeMutator records calls and fails for IDs in fail
+type fakeMutator struct {
+	calls []string
+	fail  map[string]bool
+}
+
+func (f *fakeMutator) record(call, id string) error {
+	f.calls = append(f.calls, call+" "+id)
+	if f.fail[id] {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (f *fakeMutator) ReplyToThread(ctx context.Context, threadID, body string) (*api.Comment, error) {
+	return nil, f.record("reply", threadID)
+}
+func (f *fakeMutator) ResolveThread(ctx context.Context, threadID string) (*api.Thread, error) {
+	return nil, f.record("resolve", threadID)
+}
+func (f *fakeMutator) UnresolveThread(ctx context.Context, threadID string) (*api.Thread, error) {
+	return nil, f.record("unresolve", threadID)
+}
+func (f *fakeMutator) AddReaction(ctx context.Context, subjectID, content string) (*api.Reaction, error) {
+	return nil, f.record("react", subjectID)
+}
+func (f *fakeMutator) RemoveReaction(ctx context.Context, subjectID, content string) (*api.Reaction, error) {
+	return nil, f.record("unreact", subjectID)
+}
+func (f *fakeMutator) MinimizeComment(ctx context.Context, commentID, classifier string) (*api.Comment, error) {
+	return nil, f.record("hide:"+classifier, commentID)
+}
+func (f *fakeMutator) UnminimizeComment(ctx context.Context, commentID string) (*api.Comment, error) {
+	return nil, f.record("unhide", commentID)
+}
+
+func TestExecute(t *testing.T) {
+	changes := []Change{
+		{Operation: Operation{Op: Reply, Thread: "PRRT_a", Body: "Done"}},
+		{Operation: Operation{Op: Resolve, Thread: "PRRT_b"}, NoOp: true},
+		{Operation: Operation{Op: Hide, Comment: "IC_1", Reason: "OUTDATED"}},
+		{Operation: Operation{Op: Resolve, Thread: "PRRT_c"}},
+	}
+
+	tests := []struct {
+		name            string
+		continueOnError bool
+		wantStatus      []string
+		wantCalls       int
+	}{
+		{"stop on error", false, []string{StatusOK, StatusSkipped, StatusFailed, StatusSkipped}, 2},
+		{"continue on error", true, []string{StatusOK, StatusSkipped, StatusFailed, StatusOK}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeMutator{fail: map[string]bool{"IC_1": true}}
+			e := &Executor{Client: client, ContinueOnError: tt.continueOnError}
+
+			results := e.Execute(context.Background(), changes)
+			for i, want := range tt.wantStatus {
+				if results[i].Status != want {
+					t.Errorf("result %d status = %s, want %s", i, results[i].Status, want)
+				}
+			}
+			if len(client.calls) != tt.wantCalls {
+				t.Errorf("calls = %v, want %d", client.calls, tt.wantCalls)
+			}
+			if client.calls[1] != "hide:OUTDATED IC_1" {
+				t.Errorf("hide call = %q", client.calls[1])
+			}
+		})
+	}
+}
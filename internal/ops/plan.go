@@ -0,0 +1,34 @@
+package ops
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Plan is a reviewed list of operations on one pull request
+type Plan struct {
+	Repo       string      `yaml:"repo,omitempty"`
+	PR         int         `yaml:"pr,omitempty"`
+	Operations []Operation `yaml:"operations"`
+}
+
+// LoadPlan reads a YAML plan file
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read plan: %w", err)
+	}
+
+	var plan Plan
+	if err := yaml.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("parse plan %s: %w", path, err)
+	}
+
+	if len(plan.Operations) == 0 {
+		return nil, fmt.Errorf("plan %s has no operations", path)
+	}
+
+	return &plan, nil
+}
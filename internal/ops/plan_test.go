@@ -0,0 +1,61 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	content := `repo: owner/repo
+pr: 12
+operations:
+  - op: reply
+    thread: PRRT_a
+    body: |
+      Fixed, thanks!
+  - op: react
+    comment: PRRC_b
+    reaction: 👍
+  - op: hide
+    comment: IC_c
+    reason: outdated
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan() error = %v", err)
+	}
+
+	if plan.Repo != "owner/repo" || plan.PR != 12 {
+		t.Errorf("got %s#%d, want owner/repo#12", plan.Repo, plan.PR)
+	}
+	want := []Operation{
+		{Op: Reply, Thread: "PRRT_a", Body: "Fixed, thanks!\n"},
+		{Op: React, Comment: "PRRC_b", Reaction: "👍"},
+		{Op: Hide, Comment: "IC_c", Reason: "outdated"},
+	}
+	if len(plan.Operations) != len(want) {
+		t.Fatalf("got %d operations, want %d", len(plan.Operations), len(want))
+	}
+	for i := range want {
+		if plan.Operations[i] != want[i] {
+			t.Errorf("operation %d = %+v, want %+v", i, plan.Operations[i], want[i])
+		}
+	}
+}
+
+func TestLoadPlanEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := os.WriteFile(path, []byte("pr: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadPlan(path); err == nil {
+		t.Error("LoadPlan() of plan without operations succeeded")
+	}
+}
@@ -0,0 +1,204 @@
+package ops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamishmorgan/gh-talk/internal/api"
+)
+
+// Change is a validated operation and its effect on the pull request
+type Change struct {
+	Operation
+
+	// Location describes the target, e.g. "main.go:12"
+	Location string `json:"location,omitempty"`
+	// Before and After describe the target's state around the change
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	// NoOp is set when the target is already in the requested state
+	NoOp bool `json:"noOp,omitempty"`
+}
+
+// Index looks up threads and comments of a pull request by ID
+type Index struct {
+	threads  map[string]*api.Thread
+	comments map[string]*api.Comment
+	location map[string]string
+}
+
+// NewIndex indexes the threads and comments of a pull request
+func NewIndex(pr *api.PullRequest) *Index {
+	ix := &Index{
+		threads:  make(map[string]*api.Thread),
+		comments: make(map[string]*api.Comment),
+		location: make(map[string]string),
+	}
+
+	for i := range pr.ReviewThreads {
+		ix.AddThread(&pr.ReviewThreads[i])
+	}
+	for i := range pr.Comments {
+		ix.AddComment(&pr.Comments[i], "conversation")
+	}
+
+	return ix
+}
+
+// AddThread indexes a thread and its comments
+func (ix *Index) AddThread(t *api.Thread) {
+	loc := fmt.Sprintf("%s:%d", t.Path, t.Line)
+	ix.threads[t.ID] = t
+	ix.location[t.ID] = loc
+	for j := range t.Comments {
+		ix.AddComment(&t.Comments[j], loc)
+	}
+}
+
+// AddComment indexes a comment found at location
+func (ix *Index) AddComment(c *api.Comment, location string) {
+	ix.comments[c.ID] = c
+	ix.location[c.ID] = location
+}
+
+// Check validates an operation against the pull request, including the
+// viewer's permissions, and describes its effect
+func (ix *Index) Check(op Operation) (Change, error) {
+	if err := op.Validate(); err != nil {
+		return Change{}, err
+	}
+
+	change := Change{Operation: op, Location: ix.location[op.Target()]}
+
+	switch op.Op {
+	case Reply, Resolve, Unresolve:
+		t, ok := ix.threads[op.Thread]
+		if !ok {
+			return Change{}, fmt.Errorf("thread not found: %s", op.Thread)
+		}
+		return ix.checkThread(change, t)
+	default:
+		c, ok := ix.comments[op.Comment]
+		if !ok {
+			return Change{}, fmt.Errorf("comment not found: %s", op.Comment)
+		}
+		return ix.checkComment(change, c)
+	}
+}
+
+func (ix *Index) checkThread(change Change, t *api.Thread) (Change, error) {
+	state := "open"
+	if t.IsResolved {
+		state = "resolved"
+	}
+	change.Before, change.After = state, state
+
+	switch change.Op {
+	case Reply:
+		if !t.ViewerCanReply {
+			return Change{}, fmt.Errorf("you cannot reply to %s", t.ID)
+		}
+		change.After = fmt.Sprintf("%s, %d comments", state, len(t.Comments)+1)
+		change.Before = fmt.Sprintf("%s, %d comments", state, len(t.Comments))
+	case Resolve:
+		change.After = "resolved"
+		change.NoOp = t.IsResolved
+		if !change.NoOp && !t.ViewerCanResolve {
+			return Change{}, fmt.Errorf("you cannot resolve %s", t.ID)
+		}
+	case Unresolve:
+		change.After = "open"
+		change.NoOp = !t.IsResolved
+		if !change.NoOp && !t.ViewerCanUnresolve {
+			return Change{}, fmt.Errorf("you cannot unresolve %s", t.ID)
+		}
+	}
+	return change, nil
+}
+
+func (ix *Index) checkComment(change Change, c *api.Comment) (Change, error) {
+	switch change.Op {
+	case React, Unreact:
+		if !validReaction(change.Reaction) {
+			return Change{}, fmt.Errorf("invalid reaction: %s", change.Reaction)
+		}
+		if !c.ViewerCanReact {
+			return Change{}, fmt.Errorf("you cannot react to %s", c.ID)
+		}
+
+		reacted := false
+		for _, rg := range c.ReactionGroups {
+			if rg.Content == change.Reaction && rg.ViewerHasReacted {
+				reacted = true
+			}
+		}
+		change.Before = reactionState(change.Reaction, reacted)
+		change.After = reactionState(change.Reaction, change.Op == React)
+		change.NoOp = reacted == (change.Op == React)
+	case Hide, Unhide:
+		if !c.ViewerCanMinimize {
+			return Change{}, fmt.Errorf("you cannot hide or unhide %s", c.ID)
+		}
+		if change.Op == Hide {
+			classifier, err := api.ParseClassifier(reasonOrDefault(change.Reason))
+			if err != nil {
+				return Change{}, err
+			}
+			change.Reason = classifier
+		}
+
+		change.Before = "visible"
+		if c.IsMinimized {
+			change.Before = "hidden"
+		}
+		change.After = "visible"
+		if change.Op == Hide {
+			change.After = fmt.Sprintf("hidden (%s)", strings.ToLower(change.Reason))
+		}
+		change.NoOp = c.IsMinimized == (change.Op == Hide)
+	}
+	return change, nil
+}
+
+// CheckAll validates every operation, returning all problems at once so
+// a plan can be fixed in one pass
+func (ix *Index) CheckAll(operations []Operation) ([]Change, error) {
+	changes := make([]Change, 0, len(operations))
+	var errs []error
+	for i, op := range operations {
+		change, err := ix.Check(op)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("operation %d (%s): %w", i+1, op, err))
+			continue
+		}
+		changes = append(changes, change)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return changes, nil
+}
+
+// reasonOrDefault returns the hide reason, defaulting like gh talk hide
+func reasonOrDefault(reason string) string {
+	if reason == "" {
+		return "off-topic"
+	}
+	return reason
+}
+
+func reactionState(content string, reacted bool) string {
+	if reacted {
+		return "reacted " + content
+	}
+	return "no " + content
+}
+
+func validReaction(content string) bool {
+	switch content {
+	case "THUMBS_UP", "THUMBS_DOWN", "LAUGH", "HOORAY", "CONFUSED", "HEART", "ROCKET", "EYES":
+		return true
+	}
+	return false
+}
@@ -7,8 +7,12 @@ import (
 	_ "github.com/hamishmorgan/gh-talk/internal/cache"
 	_ "github.com/hamishmorgan/gh-talk/internal/commands"
 	_ "github.com/hamishmorgan/gh-talk/internal/config"
+	_ "github.com/hamishmorgan/gh-talk/internal/diff"
 	_ "github.com/hamishmorgan/gh-talk/internal/filter"
 	_ "github.com/hamishmorgan/gh-talk/internal/format"
+	_ "github.com/hamishmorgan/gh-talk/internal/journal"
+	_ "github.com/hamishmorgan/gh-talk/internal/mcp"
+	_ "github.com/hamishmorgan/gh-talk/internal/ops"
 	_ "github.com/hamishmorgan/gh-talk/internal/tui"
 )
 
@@ -22,7 +26,11 @@ func TestDirectoryStructure(t *testing.T) {
 		{"Filter package", "github.com/hamishmorgan/gh-talk/internal/filter"},
 		{"Format package", "github.com/hamishmorgan/gh-talk/internal/format"},
 		{"Config package", "github.com/hamishmorgan/gh-talk/internal/config"},
+		{"Diff package", "github.com/hamishmorgan/gh-talk/internal/diff"},
 		{"Cache package", "github.com/hamishmorgan/gh-talk/internal/cache"},
+		{"Ops package", "github.com/hamishmorgan/gh-talk/internal/ops"},
+		{"Journal package", "github.com/hamishmorgan/gh-talk/internal/journal"},
+		{"MCP package", "github.com/hamishmorgan/gh-talk/internal/mcp"},
 		{"TUI package", "github.com/hamishmorgan/gh-talk/internal/tui"},
 	}
 